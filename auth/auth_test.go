@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// testParams keep password hashing fast in tests
+var testParams = ScryptParams{LogN: 4, R: 1, P: 1}
+
+func TestScryptVectors(t *testing.T) {
+	// Test vectors from RFC 7914 section 12
+	tests := []struct {
+		password, salt string
+		N, r, p        int
+		want           string
+	}{
+		{"", "", 16, 1, 1, "77d6576238657b203b19ca42c18a0497f16b4844e3074ae8dfdffa3fede21442fcd0069ded0948f8326a753a0fc81f17e8d3e0fb2e0d3628cf35e20c38d18906"},
+		{"password", "NaCl", 1024, 8, 16, "fdbabe1c9d3472007856e7190d01e9fe7c6ad7cbc8237830e77376634b3731622eaf30d92e22a3886ff109279d9830dac727afb94a83ee6d8360cbdfa2cc0640"},
+	}
+	for _, tt := range tests {
+		key, err := scryptKey([]byte(tt.password), []byte(tt.salt), tt.N, tt.r, tt.p, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key); got != tt.want {
+			t.Errorf("scrypt(%q, %q) = %s, want %s", tt.password, tt.salt, got, tt.want)
+		}
+	}
+}
+
+func TestHashPassword(t *testing.T) {
+	hash, err := HashPassword("correct horse", testParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := CheckPassword(hash, "correct horse"); err != nil || !ok {
+		t.Errorf("correct password rejected: %v", err)
+	}
+	if ok, _ := CheckPassword(hash, "wrong horse"); ok {
+		t.Error("wrong password accepted")
+	}
+	if _, err := CheckPassword("plaintext", "x"); !errors.Is(err, ErrMalformedHash) {
+		t.Errorf("malformed hash error = %v", err)
+	}
+}
+
+func newTestManager(t *testing.T) *Manager {
+	store, err := NewFileUserStore(filepath.Join(t.TempDir(), "users.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewManager(store, NewMemorySessionStore(), Config{Params: testParams, IdleTimeout: time.Hour})
+}
+
+func TestRegisterAndAuthenticate(t *testing.T) {
+	m := newTestManager(t)
+
+	if _, err := m.Register("ab", "password123"); err != ErrInvalidUsername {
+		t.Errorf("short username error = %v", err)
+	}
+	if _, err := m.Register("gopher", "short"); err != ErrWeakPassword {
+		t.Errorf("short password error = %v", err)
+	}
+	if _, err := m.Register("gopher", "password123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Register("Gopher", "password123"); err != ErrUserExists {
+		t.Errorf("duplicate username error = %v", err)
+	}
+
+	if _, err := m.Authenticate("GOPHER", "password123"); err != nil {
+		t.Errorf("valid login failed: %v", err)
+	}
+	if _, err := m.Authenticate("gopher", "password124"); err != ErrInvalidCredentials {
+		t.Errorf("wrong password error = %v", err)
+	}
+	if _, err := m.Authenticate("nobody", "password123"); err != ErrInvalidCredentials {
+		t.Errorf("unknown user error = %v", err)
+	}
+}
+
+// sessionCookie returns the session cookie set on a recorded response
+func sessionCookie(rr *httptest.ResponseRecorder) *http.Cookie {
+	for _, c := range rr.Result().Cookies() {
+		if c.Name == "session" {
+			return c
+		}
+	}
+	return nil
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	m := newTestManager(t)
+	user, err := m.Register("gopher", "password123")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rr := httptest.NewRecorder()
+	if _, err := m.Login(rr, httptest.NewRequest("POST", "/login", nil), user); err != nil {
+		t.Fatal(err)
+	}
+	cookie := sessionCookie(rr)
+	if cookie == nil || !cookie.HttpOnly || cookie.SameSite != http.SameSiteLaxMode {
+		t.Fatalf("session cookie missing or insecure: %+v", cookie)
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(cookie)
+	if got, ok := m.CurrentUser(req); !ok || got.ID != user.ID {
+		t.Fatal("session was not recognized")
+	}
+
+	// A tampered cookie must not be accepted
+	forged := httptest.NewRequest("GET", "/", nil)
+	forged.AddCookie(&http.Cookie{Name: "session", Value: cookie.Value + "x"})
+	if _, ok := m.CurrentUser(forged); ok {
+		t.Error("forged cookie accepted")
+	}
+
+	// Sessions end after the idle timeout
+	m.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if _, ok := m.CurrentUser(req); ok {
+		t.Error("idle session was not expired")
+	}
+	m.now = time.Now
+
+	// Logging out removes the session on the server
+	rr = httptest.NewRecorder()
+	if _, err := m.Login(rr, httptest.NewRequest("POST", "/login", nil), user); err != nil {
+		t.Fatal(err)
+	}
+	req = httptest.NewRequest("GET", "/", nil)
+	req.AddCookie(sessionCookie(rr))
+	if err := m.Logout(httptest.NewRecorder(), req); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.CurrentUser(req); ok {
+		t.Error("session still valid after logout")
+	}
+}
+
+func TestRoles(t *testing.T) {
+	m := newTestManager(t)
+	m.config.Admins = []string{"Root"}
+	for _, name := range []string{"gopher", "root"} {
+		if _, err := m.Register(name, "password123"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	user, _ := m.Authenticate("gopher", "password123")
+	if !user.Can(PermRead) || !user.Can(PermComment) || user.Can(PermAuthor) {
+		t.Errorf("learner permissions wrong: %+v", user)
+	}
+	if _, err := m.SetRoles("gopher", []Role{"wizard"}); err != ErrUnknownRole {
+		t.Errorf("unknown role error = %v", err)
+	}
+	user, err := m.SetRoles("GOPHER", []Role{RoleReviewer, RoleLearner, RoleAuthor, RoleReviewer})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(user.Roles) != 2 || user.Roles[0] != RoleAuthor || user.Roles[1] != RoleReviewer {
+		t.Errorf("roles = %v", user.Roles)
+	}
+	if user, _ = m.Authenticate("gopher", "password123"); !user.Can(PermPublish) || user.Can(PermManageUsers) {
+		t.Errorf("reviewer permissions wrong: %v", user.Roles)
+	}
+
+	// Configured admins keep the admin role even after it is taken away
+	if _, err := m.SetRoles("root", nil); err != nil {
+		t.Fatal(err)
+	}
+	if root, _ := m.Authenticate("root", "password123"); !root.HasRole(RoleAdmin) || !root.Can(PermManageUsers) {
+		t.Errorf("configured admin lost the admin role: %v", root.Roles)
+	}
+	if users, _ := m.ListUsers(); len(users) != 2 || !users[1].HasRole(RoleAdmin) || users[0].HasRole(RoleAdmin) {
+		t.Errorf("ListUsers = %+v", users)
+	}
+}
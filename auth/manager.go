@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidUsername is returned when a username does not meet the rules
+	ErrInvalidUsername = errors.New("usernames must be 3-32 letters, digits, dots, dashes or underscores")
+
+	// ErrWeakPassword is returned when a password is too short or too long
+	ErrWeakPassword = errors.New("passwords must be between 8 and 128 characters")
+
+	// ErrInvalidCredentials is returned when a username or password is wrong
+	ErrInvalidCredentials = errors.New("invalid username or password")
+)
+
+// validUsername matches the usernames accepted at registration
+var validUsername = regexp.MustCompile(`^[A-Za-z0-9._-]{3,32}$`)
+
+// Config controls session cookies and lifetimes
+type Config struct {
+	// CookieName is the name of the session cookie
+	CookieName string
+
+	// MaxAge is the absolute lifetime of a session
+	MaxAge time.Duration
+
+	// IdleTimeout ends sessions that have not been used for this long
+	IdleTimeout time.Duration
+
+	// Secret signs session cookies; a random secret is generated when empty,
+	// which logs everyone out when the server restarts
+	Secret []byte
+
+	// Params are the scrypt costs for new password hashes
+	Params ScryptParams
+
+	// Admins are usernames that always have the admin role, so the first
+	// administrator can be set up before anyone can assign roles
+	Admins []string
+}
+
+// Manager handles registration, login and sessions for the site
+type Manager struct {
+	Users    UserStore
+	Sessions SessionStore
+	config   Config
+	now      func() time.Time
+
+	dummyOnce sync.Once
+	dummyHash string
+}
+
+// NewManager creates a manager using the given stores, filling in defaults
+// for any zero configuration values
+func NewManager(users UserStore, sessions SessionStore, config Config) *Manager {
+	if config.CookieName == "" {
+		config.CookieName = "session"
+	}
+	if config.MaxAge == 0 {
+		config.MaxAge = 7 * 24 * time.Hour
+	}
+	if config.IdleTimeout == 0 {
+		config.IdleTimeout = 24 * time.Hour
+	}
+	if config.Params == (ScryptParams{}) {
+		config.Params = DefaultScryptParams
+	}
+	if len(config.Secret) == 0 {
+		config.Secret = make([]byte, 32)
+		if _, err := rand.Read(config.Secret); err != nil {
+			panic("auth: cannot generate session secret: " + err.Error())
+		}
+	}
+	return &Manager{Users: users, Sessions: sessions, config: config, now: time.Now}
+}
+
+// Register creates a new account with a hashed password
+func (m *Manager) Register(username, password string) (User, error) {
+	username = strings.TrimSpace(username)
+	if !validUsername.MatchString(username) {
+		return User{}, ErrInvalidUsername
+	}
+	if n := utf8.RuneCountInString(password); n < 8 || n > 128 {
+		return User{}, ErrWeakPassword
+	}
+
+	hash, err := HashPassword(password, m.config.Params)
+	if err != nil {
+		return User{}, err
+	}
+	id, err := RandomID()
+	if err != nil {
+		return User{}, err
+	}
+
+	user := User{ID: id, Username: username, PasswordHash: hash, Created: m.now().UTC()}
+	if err := m.Users.Create(user); err != nil {
+		return User{}, err
+	}
+	return m.withAdmins(user), nil
+}
+
+// Authenticate checks a username and password, returning the matching user
+func (m *Manager) Authenticate(username, password string) (User, error) {
+	user, err := m.Users.ByUsername(strings.TrimSpace(username))
+	if errors.Is(err, ErrUserNotFound) {
+		// Hash anyway so response times do not reveal which usernames exist
+		CheckPassword(m.dummy(), password)
+		return User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return User{}, err
+	}
+
+	ok, err := CheckPassword(user.PasswordHash, password)
+	if err != nil {
+		return User{}, err
+	}
+	if !ok {
+		return User{}, ErrInvalidCredentials
+	}
+	return m.withAdmins(user), nil
+}
+
+// dummy returns a hash that is checked when a username does not exist
+func (m *Manager) dummy() string {
+	m.dummyOnce.Do(func() {
+		m.dummyHash, _ = HashPassword("not a real password", m.config.Params)
+	})
+	return m.dummyHash
+}
+
+// Login starts a new session for user and sets the session cookie. Any
+// session already attached to the request is ended first so session IDs are
+// never reused across logins.
+func (m *Manager) Login(w http.ResponseWriter, r *http.Request, user User) (Session, error) {
+	if id, ok := m.sessionID(r); ok {
+		m.Sessions.Delete(id)
+	}
+
+	id, err := RandomID()
+	if err != nil {
+		return Session{}, err
+	}
+	now := m.now()
+	session := Session{
+		ID:       id,
+		UserID:   user.ID,
+		Created:  now,
+		LastSeen: now,
+		Expires:  now.Add(m.config.MaxAge),
+	}
+	if err := m.Sessions.Save(session); err != nil {
+		return Session{}, err
+	}
+
+	http.SetCookie(w, m.cookie(r, m.sign(id), int(m.config.MaxAge/time.Second)))
+	return session, nil
+}
+
+// Logout ends the request's session and clears the cookie
+func (m *Manager) Logout(w http.ResponseWriter, r *http.Request) error {
+	http.SetCookie(w, m.cookie(r, "", -1))
+	if id, ok := m.sessionID(r); ok {
+		return m.Sessions.Delete(id)
+	}
+	return nil
+}
+
+// CurrentUser returns the user logged in on the request, if any. Sessions past
+// their absolute lifetime or idle timeout are removed.
+func (m *Manager) CurrentUser(r *http.Request) (User, bool) {
+	id, ok := m.sessionID(r)
+	if !ok {
+		return User{}, false
+	}
+	session, err := m.Sessions.Get(id)
+	if err != nil {
+		return User{}, false
+	}
+
+	now := m.now()
+	if now.After(session.Expires) || now.Sub(session.LastSeen) > m.config.IdleTimeout {
+		m.Sessions.Delete(id)
+		return User{}, false
+	}
+
+	user, err := m.Users.ByID(session.UserID)
+	if err != nil {
+		return User{}, false
+	}
+
+	// Only record activity once a minute to avoid a write on every request
+	if now.Sub(session.LastSeen) > time.Minute {
+		session.LastSeen = now
+		m.Sessions.Save(session)
+	}
+	return m.withAdmins(user), true
+}
+
+// ListUsers returns every user with the roles they have, ordered by username
+func (m *Manager) ListUsers() ([]User, error) {
+	users, err := m.Users.List()
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		users[i] = m.withAdmins(users[i])
+	}
+	return users, nil
+}
+
+// CookieName returns the name of the session cookie
+func (m *Manager) CookieName() string {
+	return m.config.CookieName
+}
+
+// sessionID returns the verified session ID from the request cookie
+func (m *Manager) sessionID(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(m.config.CookieName)
+	if err != nil {
+		return "", false
+	}
+	return m.verify(cookie.Value)
+}
+
+// cookie builds the session cookie; a negative maxAge deletes it
+func (m *Manager) cookie(r *http.Request, value string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     m.config.CookieName,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// sign appends an HMAC of the session ID so forged cookies are rejected before
+// the store is consulted
+func (m *Manager) sign(id string) string {
+	return id + "." + base64.RawURLEncoding.EncodeToString(m.mac(id))
+}
+
+// verify checks a signed cookie value and returns the session ID it carries
+func (m *Manager) verify(value string) (string, bool) {
+	i := strings.LastIndexByte(value, '.')
+	if i < 0 {
+		return "", false
+	}
+	id, sig := value[:i], value[i+1:]
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, m.mac(id)) {
+		return "", false
+	}
+	return id, true
+}
+
+func (m *Manager) mac(id string) []byte {
+	h := hmac.New(sha256.New, m.config.Secret)
+	h.Write([]byte("session:" + id))
+	return h.Sum(nil)
+}
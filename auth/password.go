@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ScryptParams are the cost parameters used when hashing new passwords
+type ScryptParams struct {
+	LogN int // log2 of the CPU/memory cost N
+	R    int // block size
+	P    int // parallelization
+}
+
+// DefaultScryptParams use about 32 MiB and tens of milliseconds per hash
+var DefaultScryptParams = ScryptParams{LogN: 15, R: 8, P: 1}
+
+const (
+	saltLen = 16
+	keyLen  = 32
+)
+
+// ErrMalformedHash is returned when a stored password hash cannot be parsed
+var ErrMalformedHash = errors.New("auth: malformed password hash")
+
+// HashPassword hashes a password with scrypt and a random salt. The result
+// records its parameters, so costs can be raised without invalidating old hashes.
+func HashPassword(password string, params ScryptParams) (string, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key, err := scryptKey([]byte(password), salt, 1<<params.LogN, params.R, params.P, keyLen)
+	if err != nil {
+		return "", err
+	}
+
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+		params.LogN, params.R, params.P, enc.EncodeToString(salt), enc.EncodeToString(key)), nil
+}
+
+// CheckPassword reports whether password matches a hash from HashPassword
+func CheckPassword(hash, password string) (bool, error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 5 || parts[0] != "" || parts[1] != "scrypt" {
+		return false, ErrMalformedHash
+	}
+
+	var params ScryptParams
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &params.LogN, &params.R, &params.P); err != nil {
+		return false, ErrMalformedHash
+	}
+	if params.LogN < 1 || params.LogN > 24 {
+		return false, ErrMalformedHash
+	}
+
+	enc := base64.RawStdEncoding
+	salt, err := enc.DecodeString(parts[3])
+	if err != nil {
+		return false, ErrMalformedHash
+	}
+	want, err := enc.DecodeString(parts[4])
+	if err != nil {
+		return false, ErrMalformedHash
+	}
+
+	got, err := scryptKey([]byte(password), salt, 1<<params.LogN, params.R, params.P, len(want))
+	if err != nil {
+		return false, ErrMalformedHash
+	}
+	return subtle.ConstantTimeCompare(got, want) == 1, nil
+}
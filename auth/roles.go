@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// Role is a set of permissions given to a user
+type Role string
+
+// Roles build on one another: authors can do everything learners can,
+// reviewers everything authors can, and admins everything
+const (
+	RoleLearner  Role = "learner"
+	RoleAuthor   Role = "author"
+	RoleReviewer Role = "reviewer"
+	RoleAdmin    Role = "admin"
+)
+
+// Permission is something a user may be allowed to do
+type Permission string
+
+const (
+	// PermRead allows reading tutorials; everyone has it, even visitors who
+	// are not logged in
+	PermRead Permission = "read"
+
+	// PermComment allows commenting on content
+	PermComment Permission = "comment"
+
+	// PermAuthor allows writing and editing drafts in the admin area
+	PermAuthor Permission = "author"
+
+	// PermReview allows reviewing drafts written by others
+	PermReview Permission = "review"
+
+	// PermPublish allows putting drafts live
+	PermPublish Permission = "publish"
+
+	// PermManageUsers allows assigning roles to users
+	PermManageUsers Permission = "manage_users"
+)
+
+// AllRoles lists every role from least to most powerful
+var AllRoles = []Role{RoleLearner, RoleAuthor, RoleReviewer, RoleAdmin}
+
+// rolePermissions lists the permissions each role grants
+var rolePermissions = map[Role][]Permission{
+	RoleLearner:  {PermRead, PermComment},
+	RoleAuthor:   {PermRead, PermComment, PermAuthor},
+	RoleReviewer: {PermRead, PermComment, PermAuthor, PermReview, PermPublish},
+	RoleAdmin:    {PermRead, PermComment, PermAuthor, PermReview, PermPublish, PermManageUsers},
+}
+
+// ErrUnknownRole is returned when assigning a role that does not exist
+var ErrUnknownRole = errors.New("auth: unknown role")
+
+// Permissions returns the permissions the role grants
+func (r Role) Permissions() []Permission {
+	return rolePermissions[r]
+}
+
+// Can reports whether the role grants p
+func (r Role) Can(p Permission) bool {
+	for _, granted := range rolePermissions[r] {
+		if granted == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Can reports whether the user has a role granting p. Every user is a
+// learner, whatever roles they have been given.
+func (u User) Can(p Permission) bool {
+	if RoleLearner.Can(p) {
+		return true
+	}
+	for _, role := range u.Roles {
+		if role.Can(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasRole reports whether the user has been given the role
+func (u User) HasRole(role Role) bool {
+	if role == RoleLearner {
+		return true
+	}
+	for _, r := range u.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeRoles checks roles exist and returns them without duplicates, in
+// the order of AllRoles. Learner is implied, so it is dropped.
+func normalizeRoles(roles []Role) ([]Role, error) {
+	seen := make(map[Role]bool)
+	var normalized []Role
+	for _, role := range roles {
+		if _, ok := rolePermissions[role]; !ok {
+			return nil, ErrUnknownRole
+		}
+		if role == RoleLearner || seen[role] {
+			continue
+		}
+		seen[role] = true
+		normalized = append(normalized, role)
+	}
+	rank := make(map[Role]int)
+	for i, role := range AllRoles {
+		rank[role] = i
+	}
+	sort.Slice(normalized, func(i, j int) bool { return rank[normalized[i]] < rank[normalized[j]] })
+	return normalized, nil
+}
+
+// SetRoles replaces the roles of the user with the given username
+func (m *Manager) SetRoles(username string, roles []Role) (User, error) {
+	roles, err := normalizeRoles(roles)
+	if err != nil {
+		return User{}, err
+	}
+	user, err := m.Users.ByUsername(strings.TrimSpace(username))
+	if err != nil {
+		return User{}, err
+	}
+	user.Roles = roles
+	if err := m.Users.Update(user); err != nil {
+		return User{}, err
+	}
+	return m.withAdmins(user), nil
+}
+
+// withAdmins gives the admin role to users listed in Config.Admins
+func (m *Manager) withAdmins(user User) User {
+	if user.HasRole(RoleAdmin) {
+		return user
+	}
+	for _, name := range m.config.Admins {
+		if strings.EqualFold(name, user.Username) {
+			user.Roles = append(append([]Role(nil), user.Roles...), RoleAdmin)
+			break
+		}
+	}
+	return user
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// The module has no third-party dependencies, so scrypt (RFC 7914) is
+// implemented here on top of the standard library's HMAC-SHA256.
+
+// scryptKey derives a key of keyLen bytes from password and salt. N is the
+// CPU/memory cost and must be a power of two; memory use is roughly 128*N*r bytes.
+func scryptKey(password, salt []byte, N, r, p, keyLen int) ([]byte, error) {
+	if N <= 1 || N&(N-1) != 0 {
+		return nil, errors.New("scrypt: N must be a power of two greater than 1")
+	}
+	if r <= 0 || p <= 0 || uint64(r)*uint64(p) >= 1<<30 || r > (1<<31-1)/128/p || N > (1<<31-1)/128/r {
+		return nil, errors.New("scrypt: parameters are too large")
+	}
+
+	xy := make([]uint32, 64*r)
+	v := make([]uint32, 32*N*r)
+	b := pbkdf2SHA256(password, salt, 1, p*128*r)
+
+	for i := 0; i < p; i++ {
+		smix(b[i*128*r:], r, N, v, xy)
+	}
+
+	return pbkdf2SHA256(password, b, 1, keyLen), nil
+}
+
+// pbkdf2SHA256 implements PBKDF2 (RFC 8018) with HMAC-SHA256 as the PRF
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+
+	var counter [4]byte
+	dk := make([]byte, 0, blocks*hashLen)
+	u := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Write(counter[:])
+		dk = prf.Sum(dk)
+		t := dk[len(dk)-hashLen:]
+		copy(u, t)
+
+		for n := 2; n <= iterations; n++ {
+			prf.Reset()
+			prf.Write(u)
+			u = u[:0]
+			u = prf.Sum(u)
+			for i := range u {
+				t[i] ^= u[i]
+			}
+		}
+	}
+	return dk[:keyLen]
+}
+
+// smix is the sequential memory-hard mixing function from RFC 7914 section 5
+func smix(b []byte, r, N int, v, xy []uint32) {
+	var tmp [16]uint32
+	R := 32 * r
+	x := xy
+	y := xy[R:]
+
+	for i := 0; i < R; i++ {
+		x[i] = binary.LittleEndian.Uint32(b[i*4:])
+	}
+	for i := 0; i < N; i += 2 {
+		copy(v[i*R:], x)
+		blockMix(&tmp, x, y, r)
+		copy(v[(i+1)*R:], y)
+		blockMix(&tmp, y, x, r)
+	}
+	for i := 0; i < N; i += 2 {
+		j := int(integerify(x, r) & uint64(N-1))
+		xorWords(x, v[j*R:j*R+R])
+		blockMix(&tmp, x, y, r)
+
+		j = int(integerify(y, r) & uint64(N-1))
+		xorWords(y, v[j*R:j*R+R])
+		blockMix(&tmp, y, x, r)
+	}
+	for i := 0; i < R; i++ {
+		binary.LittleEndian.PutUint32(b[i*4:], x[i])
+	}
+}
+
+// blockMix applies Salsa20/8 across the 2r 64-byte blocks of in, writing the
+// even-indexed results followed by the odd-indexed ones to out
+func blockMix(tmp *[16]uint32, in, out []uint32, r int) {
+	copy(tmp[:], in[(2*r-1)*16:])
+	for i := 0; i < 2*r; i += 2 {
+		salsaXOR(tmp, in[i*16:], out[i*8:])
+		salsaXOR(tmp, in[i*16+16:], out[i*8+r*16:])
+	}
+}
+
+// integerify interprets the last 64-byte block of b as a little-endian integer
+func integerify(b []uint32, r int) uint64 {
+	j := (2*r - 1) * 16
+	return uint64(b[j]) | uint64(b[j+1])<<32
+}
+
+// xorWords sets dst[i] ^= src[i]
+func xorWords(dst, src []uint32) {
+	for i := range src {
+		dst[i] ^= src[i]
+	}
+}
+
+// salsaXOR sets tmp and out to Salsa20/8(tmp XOR in)
+func salsaXOR(tmp *[16]uint32, in, out []uint32) {
+	var w [16]uint32
+	for i := range w {
+		w[i] = tmp[i] ^ in[i]
+	}
+
+	x := w
+	for round := 0; round < 8; round += 2 {
+		// Column round
+		quarterRound(&x, 0, 4, 8, 12)
+		quarterRound(&x, 5, 9, 13, 1)
+		quarterRound(&x, 10, 14, 2, 6)
+		quarterRound(&x, 15, 3, 7, 11)
+
+		// Row round
+		quarterRound(&x, 0, 1, 2, 3)
+		quarterRound(&x, 5, 6, 7, 4)
+		quarterRound(&x, 10, 11, 8, 9)
+		quarterRound(&x, 15, 12, 13, 14)
+	}
+
+	for i := range x {
+		x[i] += w[i]
+		out[i] = x[i]
+		tmp[i] = x[i]
+	}
+}
+
+// quarterRound is the Salsa20 quarter-round applied to four words of x
+func quarterRound(x *[16]uint32, a, b, c, d int) {
+	x[b] ^= bits.RotateLeft32(x[a]+x[d], 7)
+	x[c] ^= bits.RotateLeft32(x[b]+x[a], 9)
+	x[d] ^= bits.RotateLeft32(x[c]+x[b], 13)
+	x[a] ^= bits.RotateLeft32(x[d]+x[c], 18)
+}
@@ -0,0 +1,88 @@
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Session is a server-side login session
+type Session struct {
+	ID       string
+	UserID   string
+	Created  time.Time
+	LastSeen time.Time
+	Expires  time.Time
+}
+
+// ErrSessionNotFound is returned when a session does not exist or has been removed
+var ErrSessionNotFound = errors.New("auth: session not found")
+
+// SessionStore persists sessions on the server
+type SessionStore interface {
+	Get(id string) (Session, error)
+	Save(session Session) error
+	Delete(id string) error
+	DeleteExpired(now time.Time) error
+}
+
+// MemorySessionStore keeps sessions in memory; it is safe for concurrent use
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]Session
+}
+
+// NewMemorySessionStore creates an empty in-memory session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]Session)}
+}
+
+// Get returns the session with the given ID
+func (s *MemorySessionStore) Get(id string) (Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok {
+		return Session{}, ErrSessionNotFound
+	}
+	return session, nil
+}
+
+// Save creates or replaces a session
+func (s *MemorySessionStore) Save(session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[session.ID] = session
+	return nil
+}
+
+// Delete removes a session
+func (s *MemorySessionStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// DeleteExpired removes every session that expired before now
+func (s *MemorySessionStore) DeleteExpired(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, session := range s.sessions {
+		if now.After(session.Expires) {
+			delete(s.sessions, id)
+		}
+	}
+	return nil
+}
+
+// RandomID returns a URL-safe random identifier with 256 bits of entropy
+func RandomID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
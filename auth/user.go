@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-webserver-tutorial/jsonfile"
+)
+
+// User is a registered account on the tutorial site
+type User struct {
+	ID           string    `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"password_hash"`
+	Created      time.Time `json:"created"`
+	Roles        []Role    `json:"roles,omitempty"`
+}
+
+var (
+	// ErrUserNotFound is returned when no user matches a lookup
+	ErrUserNotFound = errors.New("auth: user not found")
+
+	// ErrUserExists is returned when registering a username that is taken
+	ErrUserExists = errors.New("auth: username already taken")
+)
+
+// UserStore persists user accounts. Usernames are compared case-insensitively.
+type UserStore interface {
+	Create(user User) error
+	Update(user User) error
+	ByID(id string) (User, error)
+	ByUsername(username string) (User, error)
+	List() ([]User, error)
+}
+
+// MemoryUserStore keeps users in memory; it is safe for concurrent use
+type MemoryUserStore struct {
+	mu    sync.RWMutex
+	users map[string]User
+}
+
+// NewMemoryUserStore creates an empty in-memory user store
+func NewMemoryUserStore() *MemoryUserStore {
+	return &MemoryUserStore{users: make(map[string]User)}
+}
+
+// Create adds a new user, failing if the ID or username is already in use
+func (s *MemoryUserStore) Create(user User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.create(user)
+}
+
+func (s *MemoryUserStore) create(user User) error {
+	if _, ok := s.users[user.ID]; ok {
+		return ErrUserExists
+	}
+	for _, existing := range s.users {
+		if strings.EqualFold(existing.Username, user.Username) {
+			return ErrUserExists
+		}
+	}
+	s.users[user.ID] = user
+	return nil
+}
+
+// Update replaces an existing user
+func (s *MemoryUserStore) Update(user User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[user.ID]; !ok {
+		return ErrUserNotFound
+	}
+	s.users[user.ID] = user
+	return nil
+}
+
+// ByID looks up a user by ID
+func (s *MemoryUserStore) ByID(id string) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	if !ok {
+		return User{}, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// ByUsername looks up a user by username
+func (s *MemoryUserStore) ByUsername(username string) (User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, user := range s.users {
+		if strings.EqualFold(user.Username, username) {
+			return user, nil
+		}
+	}
+	return User{}, ErrUserNotFound
+}
+
+// List returns all users ordered by username
+func (s *MemoryUserStore) List() ([]User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	users := make([]User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return strings.ToLower(users[i].Username) < strings.ToLower(users[j].Username)
+	})
+	return users, nil
+}
+
+// FileUserStore is a MemoryUserStore that saves every change to a JSON file
+type FileUserStore struct {
+	*MemoryUserStore
+	path string
+}
+
+// NewFileUserStore loads users from path, starting empty if the file does not exist
+func NewFileUserStore(path string) (*FileUserStore, error) {
+	s := &FileUserStore{MemoryUserStore: NewMemoryUserStore(), path: path}
+
+	var users []User
+	if _, err := jsonfile.Load(path, &users); err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		s.users[user.ID] = user
+	}
+	return s, nil
+}
+
+// Create adds a new user and saves the store
+func (s *FileUserStore) Create(user User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.create(user); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// Update replaces an existing user and saves the store
+func (s *FileUserStore) Update(user User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[user.ID]; !ok {
+		return ErrUserNotFound
+	}
+	s.users[user.ID] = user
+	return s.save()
+}
+
+// save writes all users to disk atomically; the caller must hold the lock
+func (s *FileUserStore) save() error {
+	users := make([]User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return jsonfile.Save(s.path, users)
+}
@@ -0,0 +1,163 @@
+package authoring
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Field is one field of a document as text, so versions can be compared
+// line by line
+type Field struct {
+	Name  string
+	Label string
+	Text  string
+}
+
+// Fields returns the editable fields of a document in the order they appear
+// in the editor. Each code block's source is a field of its own, with its
+// filename, language, highlighted lines and callouts in a second field.
+func Fields(d Document) []Field {
+	switch {
+	case d.Tutorial != nil:
+		t := d.Tutorial
+		fields := []Field{
+			{"title", "Title", t.Title},
+			{"level", "Level", d.Level},
+			{"description", "Description", string(t.Description)},
+			{"explanation", "Explanation", string(t.Explanation)},
+			{"prerequisites", "Prerequisites", strings.Join(t.Prerequisites, "\n")},
+			{"topics", "Topics", strings.Join(t.Topics, "\n")},
+		}
+		for i, b := range t.Code {
+			n := strconv.Itoa(i + 1)
+			settings := []string{"filename: " + b.Filename, "language: " + b.Language}
+			if b.Highlight != "" {
+				settings = append(settings, "highlight: "+b.Highlight)
+			}
+			for _, c := range b.Callouts {
+				settings = append(settings, "callout "+strconv.Itoa(c.Line)+": "+string(c.Note))
+			}
+			fields = append(fields,
+				Field{"code." + n, "Code block " + n, b.Source},
+				Field{"code." + n + ".settings", "Code block " + n + " settings", strings.Join(settings, "\n")})
+		}
+		return fields
+	case d.Example != nil:
+		e := d.Example
+		return []Field{
+			{"title", "Title", e.Title},
+			{"description", "Description", string(e.Description)},
+			{"code", "Code", e.Code},
+			{"live_path", "Live path", e.LivePath},
+		}
+	}
+	return nil
+}
+
+// Line operations in a diff
+const (
+	LineSame    = "same"
+	LineAdded   = "added"
+	LineRemoved = "removed"
+)
+
+// DiffLine is one line of a field diff. Old and New are the line's numbers in
+// each version, zero where the line is missing from that version.
+type DiffLine struct {
+	Op   string
+	Text string
+	Old  int
+	New  int
+}
+
+// FieldDiff is the line diff of one field that changed
+type FieldDiff struct {
+	Name  string
+	Label string
+	Lines []DiffLine
+}
+
+// Diff compares each field of two versions of a document and returns the
+// fields that changed. Fields only in the old version are listed last.
+func Diff(old, new Document) []FieldDiff {
+	previous := make(map[string]Field)
+	for _, f := range Fields(old) {
+		previous[f.Name] = f
+	}
+
+	var diffs []FieldDiff
+	add := func(name, label, a, b string) {
+		if a != b {
+			diffs = append(diffs, FieldDiff{Name: name, Label: label, Lines: diffLines(splitLines(a), splitLines(b))})
+		}
+	}
+	for _, f := range Fields(new) {
+		add(f.Name, f.Label, previous[f.Name].Text, f.Text)
+		delete(previous, f.Name)
+	}
+	for _, f := range Fields(old) {
+		if _, ok := previous[f.Name]; ok {
+			add(f.Name, f.Label, f.Text, "")
+		}
+	}
+	return diffs
+}
+
+// lineCount returns the number of lines in the named field of d, or -1 if d
+// has no such field
+func lineCount(d Document, name string) int {
+	for _, f := range Fields(d) {
+		if f.Name == name {
+			return len(splitLines(f.Text))
+		}
+	}
+	return -1
+}
+
+// splitLines splits text into lines, ignoring a final newline
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffLines returns the shortest edit from a to b, found from their longest
+// common subsequence
+func diffLines(a, b []string) []DiffLine {
+	// common[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	common := make([][]int, len(a)+1)
+	for i := range common {
+		common[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				common[i][j] = common[i+1][j+1] + 1
+			case common[i+1][j] >= common[i][j+1]:
+				common[i][j] = common[i+1][j]
+			default:
+				common[i][j] = common[i][j+1]
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: LineSame, Text: b[j], Old: i + 1, New: j + 1})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && common[i+1][j] >= common[i][j+1]):
+			lines = append(lines, DiffLine{Op: LineRemoved, Text: a[i], Old: i + 1})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: LineAdded, Text: b[j], New: j + 1})
+			j++
+		}
+	}
+	return lines
+}
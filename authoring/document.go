@@ -0,0 +1,159 @@
+// Package authoring keeps the tutorials and examples edited on the site: each
+// edit is saved as a revision, drafts are reviewed and then published now or
+// at a set time, and any earlier revision can be restored.
+package authoring
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/highlight"
+)
+
+// Kinds of content that can be edited
+const (
+	KindTutorial = "tutorial"
+	KindExample  = "example"
+)
+
+// maxTitleLength is the longest title accepted, in characters
+const maxTitleLength = 200
+
+var (
+	tutorialID      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	exampleFilename = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*\.go$`)
+)
+
+// Document is one version of a tutorial or example. Tutorials are edited
+// without their quiz and exercise, which stay as they are in the code.
+type Document struct {
+	Kind     string               `json:"kind"`
+	Level    string               `json:"level,omitempty"`
+	Tutorial *content.Tutorial    `json:"tutorial,omitempty"`
+	Example  *content.CodeExample `json:"example,omitempty"`
+}
+
+// ID returns the tutorial ID or the example filename
+func (d Document) ID() string {
+	switch {
+	case d.Tutorial != nil:
+		return d.Tutorial.ID
+	case d.Example != nil:
+		return d.Example.Filename
+	}
+	return ""
+}
+
+// Title returns the tutorial or example title
+func (d Document) Title() string {
+	switch {
+	case d.Tutorial != nil:
+		return d.Tutorial.Title
+	case d.Example != nil:
+		return d.Example.Title
+	}
+	return ""
+}
+
+// Key identifies a tutorial or example in the store, such as
+// "tutorial/hello-world"
+func Key(kind, id string) string {
+	return kind + "/" + id
+}
+
+// InvalidError lists the problems with a document, keyed by form field
+type InvalidError map[string]string
+
+func (e InvalidError) Error() string {
+	var problems []string
+	for field, problem := range e {
+		problems = append(problems, field+" "+problem)
+	}
+	return "authoring: invalid document: " + strings.Join(problems, "; ")
+}
+
+// Validate checks a document and returns a message for each invalid field,
+// or nil if the document is valid. Prerequisites are checked when publishing.
+func Validate(d Document) InvalidError {
+	problems := make(InvalidError)
+	switch {
+	case d.Kind == KindTutorial && d.Tutorial != nil && d.Example == nil:
+		validateTutorial(d.Level, *d.Tutorial, problems)
+	case d.Kind == KindExample && d.Example != nil && d.Tutorial == nil:
+		validateExample(*d.Example, problems)
+	default:
+		problems["kind"] = "must be a tutorial or an example"
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+func validateTutorial(level string, t content.Tutorial, problems InvalidError) {
+	if !tutorialID.MatchString(t.ID) {
+		problems["id"] = "must be lowercase words separated by hyphens"
+	}
+	validateTitle(t.Title, problems)
+	known := false
+	for _, l := range content.GetLevels() {
+		known = known || l.ID == level
+	}
+	if !known {
+		problems["level"] = "must be one of the tutorial levels"
+	}
+	if strings.TrimSpace(string(t.Description)) == "" {
+		problems["description"] = "is required"
+	}
+	if len(t.Code) == 0 {
+		problems["code"] = "needs at least one code block"
+	}
+	for i, block := range t.Code {
+		field := fmt.Sprintf("code.%d", i+1)
+		lines := highlight.LineCount(highlight.TrimSource(block.Source))
+		_, err := highlight.ParseLines(block.Highlight, lines)
+		switch {
+		case block.Filename == "":
+			problems[field] = "needs a filename"
+		case !highlight.Supported(block.Language):
+			problems[field] = fmt.Sprintf("has unsupported language %q", block.Language)
+		case strings.TrimSpace(block.Source) == "":
+			problems[field] = "needs source code"
+		case err != nil:
+			problems[field] = "has invalid highlighted lines: " + err.Error()
+		}
+		for _, callout := range block.Callouts {
+			if callout.Line < 1 || callout.Line > lines {
+				problems[field] = fmt.Sprintf("has a callout on missing line %d", callout.Line)
+			}
+		}
+	}
+}
+
+func validateExample(e content.CodeExample, problems InvalidError) {
+	if !exampleFilename.MatchString(e.Filename) {
+		problems["id"] = "must be a lowercase .go filename with words separated by underscores"
+	}
+	validateTitle(e.Title, problems)
+	if strings.TrimSpace(string(e.Description)) == "" {
+		problems["description"] = "is required"
+	}
+	if strings.TrimSpace(e.Code) == "" {
+		problems["code"] = "is required"
+	}
+	if e.LivePath != "" && !strings.HasPrefix(e.LivePath, "/") {
+		problems["live_path"] = "must start with /"
+	}
+}
+
+func validateTitle(title string, problems InvalidError) {
+	switch {
+	case strings.TrimSpace(title) == "":
+		problems["title"] = "is required"
+	case utf8.RuneCountInString(title) > maxTitleLength:
+		problems["title"] = "must be at most 200 characters"
+	}
+}
@@ -0,0 +1,150 @@
+package authoring
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrOwnDraft is returned when authors review their own draft
+	ErrOwnDraft = errors.New("authoring: you cannot review your own draft")
+
+	// ErrNotApproved is returned when publishing a draft that has not been
+	// approved by enough reviewers
+	ErrNotApproved = errors.New("authoring: draft is not approved")
+)
+
+// Review verdicts
+const (
+	VerdictApprove = "approve"
+	VerdictChanges = "changes"
+)
+
+// Comment is a note on a draft, anchored to a line of one of its fields.
+// Line zero comments on the field as a whole.
+type Comment struct {
+	ID       int       `json:"id"`
+	Revision int       `json:"revision"`
+	Author   string    `json:"author"`
+	Field    string    `json:"field"`
+	Line     int       `json:"line,omitempty"`
+	Body     string    `json:"body"`
+	Created  time.Time `json:"created"`
+}
+
+// Review is a reviewer's verdict on a draft revision
+type Review struct {
+	Revision int       `json:"revision"`
+	Reviewer string    `json:"reviewer"`
+	Verdict  string    `json:"verdict"`
+	Body     string    `json:"body,omitempty"`
+	Created  time.Time `json:"created"`
+}
+
+// ReviewState sums up the reviews of a draft. Only each reviewer's latest
+// verdict counts.
+type ReviewState struct {
+	Approvals        []string
+	ChangesRequested []string
+}
+
+// ReviewState returns the verdicts given on the current draft. Saving a new
+// draft starts its review afresh.
+func (e Entry) ReviewState() ReviewState {
+	latest := make(map[string]string)
+	var order []string
+	for _, r := range e.Reviews {
+		if r.Revision != e.Draft {
+			continue
+		}
+		if _, ok := latest[r.Reviewer]; !ok {
+			order = append(order, r.Reviewer)
+		}
+		latest[r.Reviewer] = r.Verdict
+	}
+	var state ReviewState
+	for _, reviewer := range order {
+		if latest[reviewer] == VerdictApprove {
+			state.Approvals = append(state.Approvals, reviewer)
+		} else {
+			state.ChangesRequested = append(state.ChangesRequested, reviewer)
+		}
+	}
+	return state
+}
+
+// DraftComments returns the comments left on the current draft
+func (e Entry) DraftComments() []Comment {
+	var comments []Comment
+	for _, c := range e.Comments {
+		if e.Draft != 0 && c.Revision == e.Draft {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// AddComment leaves a comment on the current draft of a tutorial or example
+func (s *Store) AddComment(kind, id string, c Comment) (Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[Key(kind, id)]
+	if !ok || e.Draft == 0 {
+		return Comment{}, ErrNoDraft
+	}
+
+	c.Body = strings.TrimSpace(c.Body)
+	problems := make(InvalidError)
+	if c.Body == "" {
+		problems["body"] = "is required"
+	}
+	switch lines := lineCount(e.History[e.Draft-1].Doc, c.Field); {
+	case lines < 0:
+		problems["field"] = "does not exist"
+	case c.Line < 0 || c.Line > lines:
+		problems["line"] = "is not in the field"
+	}
+	if len(problems) > 0 {
+		return Comment{}, problems
+	}
+
+	c.ID, c.Revision, c.Created = len(e.Comments)+1, e.Draft, s.now()
+	e.Comments = append(e.Comments, c)
+	return c, s.save()
+}
+
+// AddReview records a reviewer's verdict on the current draft. Reviewers
+// cannot approve or reject a draft they wrote.
+func (s *Store) AddReview(kind, id, reviewer, verdict, body string) (Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[Key(kind, id)]
+	if !ok || e.Draft == 0 {
+		return Review{}, ErrNoDraft
+	}
+	if verdict != VerdictApprove && verdict != VerdictChanges {
+		return Review{}, InvalidError{"verdict": "must be approve or changes"}
+	}
+	if strings.EqualFold(e.History[e.Draft-1].Author, reviewer) {
+		return Review{}, ErrOwnDraft
+	}
+
+	r := Review{Revision: e.Draft, Reviewer: reviewer, Verdict: verdict, Body: strings.TrimSpace(body), Created: s.now()}
+	e.Reviews = append(e.Reviews, r)
+	return r, s.save()
+}
+
+// checkApproved returns an error wrapping ErrNotApproved unless enough
+// reviewers approve the draft and none ask for changes
+func (s *Store) checkApproved(e *Entry) error {
+	state := e.ReviewState()
+	if len(state.ChangesRequested) > 0 {
+		return fmt.Errorf("%w: %s asked for changes", ErrNotApproved, strings.Join(state.ChangesRequested, ", "))
+	}
+	if len(state.Approvals) < s.RequiredApprovals {
+		return fmt.Errorf("%w: %d of %d approvals", ErrNotApproved, len(state.Approvals), s.RequiredApprovals)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package authoring
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"golang-webserver-tutorial/content"
+)
+
+func TestDiff(t *testing.T) {
+	old := tutorialDoc("cookies", "Cookies")
+	new := tutorialDoc("cookies", "Cookies")
+	changed := *new.Tutorial
+	changed.Code = []content.CodeBlock{{Filename: "main.go", Language: "go", Source: "package main\n\nimport \"net/http\"\n\nfunc main() {}\n", Highlight: "3"}}
+	changed.Topics = []string{"cookies"}
+	new.Tutorial = &changed
+
+	diffs := Diff(old, new)
+	if len(diffs) != 2 || diffs[0].Name != "topics" || diffs[1].Name != "code.1" {
+		t.Fatalf("Diff = %+v", diffs)
+	}
+	want := []DiffLine{
+		{LineSame, "package main", 1, 1},
+		{LineSame, "", 2, 2},
+		{LineAdded, "import \"net/http\"", 0, 3},
+		{LineAdded, "", 0, 4},
+		{LineSame, "func main() {}", 3, 5},
+	}
+	if got := diffs[1].Lines; len(got) != len(want) {
+		t.Fatalf("code diff = %+v", got)
+	} else {
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+			}
+		}
+	}
+
+	// A new tutorial is all additions and a removed block all removals;
+	// explanation and prerequisites are empty in both
+	if diffs := Diff(Document{Kind: KindTutorial}, new); len(diffs) != len(Fields(new))-2 {
+		t.Errorf("new tutorial: %d changed fields", len(diffs))
+	}
+	changed.Code = nil
+	diffs = Diff(old, new)
+	if last := diffs[len(diffs)-1]; last.Name != "code.1.settings" || last.Lines[0].Op != LineRemoved {
+		t.Errorf("removed block = %+v", last)
+	}
+}
+
+func TestReview(t *testing.T) {
+	s := NewMemoryStore()
+	s.RequiredApprovals = 2
+	now := time.Date(2025, time.July, 1, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	if _, err := s.AddComment(KindTutorial, "cookies", Comment{Field: "title", Body: "Hi"}); err != ErrNoDraft {
+		t.Errorf("comment without a draft: %v", err)
+	}
+	if _, err := s.SaveDraft(tutorialDoc("cookies", "Cookies"), "ann", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Comments point at a line of a field of the draft
+	c, err := s.AddComment(KindTutorial, "cookies", Comment{Author: "bob", Field: "code.1", Line: 3, Body: " Explain main "})
+	if err != nil || c.ID != 1 || c.Revision != 1 || c.Body != "Explain main" {
+		t.Fatalf("AddComment = %+v, %v", c, err)
+	}
+	for _, bad := range []Comment{{Field: "code.9", Body: "x"}, {Field: "code.1", Line: 4, Body: "x"}, {Field: "title"}} {
+		var invalid InvalidError
+		if _, err := s.AddComment(KindTutorial, "cookies", bad); !errors.As(err, &invalid) {
+			t.Errorf("AddComment(%+v) = %v", bad, err)
+		}
+	}
+
+	// Publishing waits for enough approvals and no requests for changes
+	if _, err := s.AddReview(KindTutorial, "cookies", "Ann", VerdictApprove, ""); err != ErrOwnDraft {
+		t.Errorf("self review: %v", err)
+	}
+	s.AddReview(KindTutorial, "cookies", "bob", VerdictApprove, "")
+	s.AddReview(KindTutorial, "cookies", "cat", VerdictChanges, "Needs an example")
+	if err := s.Publish(KindTutorial, "cookies", now, "bob"); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("published with changes requested: %v", err)
+	}
+	s.AddReview(KindTutorial, "cookies", "cat", VerdictApprove, "")
+	e, _ := s.Entry(KindTutorial, "cookies")
+	if state := e.ReviewState(); len(state.Approvals) != 2 || len(state.ChangesRequested) != 0 {
+		t.Errorf("ReviewState = %+v", state)
+	}
+
+	// A new draft needs reviewing again
+	if _, err := s.SaveDraft(tutorialDoc("cookies", "Cookies!"), "ann", ""); err != nil {
+		t.Fatal(err)
+	}
+	e, _ = s.Entry(KindTutorial, "cookies")
+	if len(e.ReviewState().Approvals) != 0 || len(e.DraftComments()) != 0 || len(e.Comments) != 1 {
+		t.Errorf("review carried over to a new draft: %+v", e)
+	}
+	if err := s.Publish(KindTutorial, "cookies", now.Add(time.Hour), "bob"); !errors.Is(err, ErrNotApproved) {
+		t.Errorf("scheduled without approval: %v", err)
+	}
+	s.AddReview(KindTutorial, "cookies", "bob", VerdictApprove, "")
+	s.AddReview(KindTutorial, "cookies", "cat", VerdictApprove, "")
+	if err := s.Publish(KindTutorial, "cookies", now.Add(time.Hour), "bob"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Asking for changes after scheduling stops the draft going live
+	s.AddReview(KindTutorial, "cookies", "cat", VerdictChanges, "Wait")
+	now = now.Add(2 * time.Hour)
+	if keys, err := s.PublishDue(now); len(keys) != 0 || err == nil {
+		t.Errorf("PublishDue = %v, %v", keys, err)
+	}
+	if e, _ := s.Entry(KindTutorial, "cookies"); e.Status() != StatusDraft {
+		t.Errorf("status = %s", e.Status())
+	}
+}
@@ -0,0 +1,400 @@
+package authoring
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/jsonfile"
+)
+
+var (
+	// ErrNotFound is returned for content or revisions that were never saved
+	ErrNotFound = errors.New("authoring: not found")
+
+	// ErrNoDraft is returned when publishing or discarding without a draft
+	ErrNoDraft = errors.New("authoring: no draft")
+)
+
+// Statuses of an entry
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusPublished = "published"
+)
+
+// Revision is one saved version of a tutorial or example
+type Revision struct {
+	Number  int       `json:"number"`
+	Author  string    `json:"author"`
+	Created time.Time `json:"created"`
+	Note    string    `json:"note,omitempty"`
+	Doc     Document  `json:"document"`
+
+	// Published is when the revision went live, zero if it never did
+	Published time.Time `json:"published,omitempty"`
+	Publisher string    `json:"publisher,omitempty"`
+}
+
+// Entry is the edit history of one tutorial or example
+type Entry struct {
+	Kind    string     `json:"kind"`
+	ID      string     `json:"id"`
+	History []Revision `json:"history"`
+
+	// Draft and Published are revision numbers, zero when there is no
+	// draft or nothing has been published over the built-in content
+	Draft     int `json:"draft,omitempty"`
+	Published int `json:"published,omitempty"`
+
+	// PublishAt is when the draft is scheduled to go live
+	PublishAt   time.Time `json:"publish_at,omitempty"`
+	ScheduledBy string    `json:"scheduled_by,omitempty"`
+
+	// FirstPublished is when the content was first published from here
+	FirstPublished time.Time `json:"first_published,omitempty"`
+
+	// Comments and Reviews are left on drafts by reviewers
+	Comments []Comment `json:"comments,omitempty"`
+	Reviews  []Review  `json:"reviews,omitempty"`
+}
+
+// Status returns whether the entry has a draft, a scheduled draft or only
+// published content
+func (e Entry) Status() string {
+	switch {
+	case e.Draft != 0 && !e.PublishAt.IsZero():
+		return StatusScheduled
+	case e.Draft != 0:
+		return StatusDraft
+	}
+	return StatusPublished
+}
+
+// Revision returns the revision with the given number
+func (e Entry) Revision(number int) (Revision, bool) {
+	if number < 1 || number > len(e.History) {
+		return Revision{}, false
+	}
+	return e.History[number-1], true
+}
+
+// Latest returns the draft if there is one, or the published revision
+func (e Entry) Latest() Revision {
+	if r, ok := e.Revision(e.Draft); ok {
+		return r
+	}
+	r, _ := e.Revision(e.Published)
+	return r
+}
+
+// Store keeps every entry in memory and, when it has a path, saves each
+// change to a JSON file. It is safe for concurrent use.
+type Store struct {
+	// RequiredApprovals is how many reviewers must approve a draft before
+	// it can be published. It should be set before the store is used.
+	RequiredApprovals int
+
+	mu      sync.Mutex
+	path    string
+	entries map[string]*Entry
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an empty store that is not saved. Drafts need one
+// approval to be published.
+func NewMemoryStore() *Store {
+	return &Store{RequiredApprovals: 1, entries: make(map[string]*Entry), now: time.Now}
+}
+
+// NewFileStore loads entries from path, starting empty if the file does not exist
+func NewFileStore(path string) (*Store, error) {
+	s := NewMemoryStore()
+	s.path = path
+	if _, err := jsonfile.Load(path, &s.entries); err != nil {
+		return nil, err
+	}
+	if s.entries == nil {
+		s.entries = make(map[string]*Entry)
+	}
+	return s, nil
+}
+
+// Reload reads the store's file again, for when it was changed by hand or
+// replaced. It reports whether anything changed. Entries whose published
+// revisions would leave the site invalid are refused and the entries already
+// loaded are kept.
+func (s *Store) Reload() (bool, error) {
+	if s.path == "" {
+		return false, nil
+	}
+	var entries map[string]*Entry
+	if _, err := jsonfile.Load(s.path, &entries); err != nil {
+		return false, err
+	}
+	if entries == nil {
+		entries = make(map[string]*Entry)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The store rewrites the file on every change, so compare the encoded
+	// entries to skip reloading what was just saved
+	before, err := json.Marshal(s.entries)
+	if err != nil {
+		return false, err
+	}
+	after, err := json.Marshal(entries)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(before, after) {
+		return false, nil
+	}
+
+	loaded := &Store{entries: entries, now: s.now}
+	if err := loaded.published(nil).Check(); err != nil {
+		return false, fmt.Errorf("authoring: not reloading %s: %v", s.path, err)
+	}
+	s.entries = entries
+	return true, nil
+}
+
+func (s *Store) save() error {
+	if s.path == "" {
+		return nil
+	}
+	return jsonfile.Save(s.path, s.entries)
+}
+
+// Entries returns every entry sorted by kind and ID
+func (s *Store) Entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entries := make([]Entry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, copyEntry(e))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return Key(entries[i].Kind, entries[i].ID) < Key(entries[j].Kind, entries[j].ID)
+	})
+	return entries
+}
+
+// Entry returns the history of a tutorial or example
+func (s *Store) Entry(kind, id string) (Entry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[Key(kind, id)]
+	if !ok {
+		return Entry{}, false
+	}
+	return copyEntry(e), true
+}
+
+// SaveDraft saves a document as a new revision and makes it the draft,
+// replacing any earlier draft and cancelling its schedule
+func (s *Store) SaveDraft(doc Document, author, note string) (Revision, error) {
+	if problems := Validate(doc); problems != nil {
+		return Revision{}, problems
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saveDraft(doc, author, note)
+}
+
+func (s *Store) saveDraft(doc Document, author, note string) (Revision, error) {
+	// Quizzes and exercises are never edited here, so never stored either
+	if doc.Tutorial != nil {
+		t := *doc.Tutorial
+		t.Quiz, t.Exercise, t.Locale, t.Outdated = nil, nil, "", false
+		doc.Tutorial = &t
+	}
+
+	key := Key(doc.Kind, doc.ID())
+	e, ok := s.entries[key]
+	if !ok {
+		e = &Entry{Kind: doc.Kind, ID: doc.ID()}
+		s.entries[key] = e
+	}
+	r := Revision{Number: len(e.History) + 1, Author: author, Created: s.now(), Note: note, Doc: doc}
+	e.History = append(e.History, r)
+	e.Draft = r.Number
+	e.PublishAt, e.ScheduledBy = time.Time{}, ""
+	return r, s.save()
+}
+
+// Revert saves a copy of an earlier revision as the new draft
+func (s *Store) Revert(kind, id string, number int, author string) (Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[Key(kind, id)]
+	if !ok {
+		return Revision{}, ErrNotFound
+	}
+	old, ok := e.Revision(number)
+	if !ok {
+		return Revision{}, ErrNotFound
+	}
+	return s.saveDraft(old.Doc, author, fmt.Sprintf("Revert to revision %d", number))
+}
+
+// Discard drops the draft, keeping it in the history. Content that was never
+// published is forgotten.
+func (s *Store) Discard(kind, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := Key(kind, id)
+	e, ok := s.entries[key]
+	if !ok || e.Draft == 0 {
+		return ErrNoDraft
+	}
+	if e.Published == 0 {
+		delete(s.entries, key)
+	} else {
+		e.Draft, e.PublishAt, e.ScheduledBy = 0, time.Time{}, ""
+	}
+	return s.save()
+}
+
+// Publish makes the draft live. A time in the future schedules it instead,
+// to be published by PublishDue. Drafts that are not approved, or would
+// leave the site with unknown prerequisites or a cycle, are refused.
+func (s *Store) Publish(kind, id string, at time.Time, publisher string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[Key(kind, id)]
+	if !ok || e.Draft == 0 {
+		return ErrNoDraft
+	}
+	if err := s.checkApproved(e); err != nil {
+		return err
+	}
+	if at.After(s.now()) {
+		e.PublishAt, e.ScheduledBy = at, publisher
+		return s.save()
+	}
+	if err := s.publish(e, publisher); err != nil {
+		return err
+	}
+	return s.save()
+}
+
+// PublishDue publishes every scheduled draft whose time has come and returns
+// the keys of the entries published. Drafts that fail to publish, such as
+// those a reviewer has since asked to change, are unscheduled and reported
+// in err.
+func (s *Store) PublishDue(now time.Time) (published []string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var failed []string
+	for key, e := range s.entries {
+		if e.Draft == 0 || e.PublishAt.IsZero() || e.PublishAt.After(now) {
+			continue
+		}
+		err := s.checkApproved(e)
+		if err == nil {
+			err = s.publish(e, e.ScheduledBy)
+		}
+		if err != nil {
+			e.PublishAt, e.ScheduledBy = time.Time{}, ""
+			failed = append(failed, err.Error())
+			continue
+		}
+		published = append(published, key)
+	}
+	if len(published) == 0 && len(failed) == 0 {
+		return nil, nil
+	}
+	sort.Strings(published)
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		err = errors.New(strings.Join(failed, "; "))
+	}
+	if saveErr := s.save(); saveErr != nil {
+		return published, saveErr
+	}
+	return published, err
+}
+
+// publish makes e's draft live after checking the site stays valid
+func (s *Store) publish(e *Entry, publisher string) error {
+	draft := *e
+	draft.Published = e.Draft
+	candidate := s.published(&draft)
+	if err := candidate.Check(); err != nil {
+		return fmt.Errorf("authoring: cannot publish %s: %v", Key(e.Kind, e.ID), err)
+	}
+
+	now := s.now()
+	e.History[e.Draft-1].Published = now
+	e.History[e.Draft-1].Publisher = publisher
+	e.Published, e.Draft = e.Draft, 0
+	e.PublishAt, e.ScheduledBy = time.Time{}, ""
+	if e.FirstPublished.IsZero() {
+		e.FirstPublished = now
+	}
+	return nil
+}
+
+// Published returns the published content to apply over the built-in content
+func (s *Store) Published() content.Published {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.published(nil)
+}
+
+// published builds the published content, using replace in place of the
+// stored entry with the same key
+func (s *Store) published(replace *Entry) content.Published {
+	keys := make([]string, 0, len(s.entries))
+	for key := range s.entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var p content.Published
+	for _, key := range keys {
+		e := s.entries[key]
+		if replace != nil && Key(replace.Kind, replace.ID) == key {
+			e = replace
+		}
+		r, ok := e.Revision(e.Published)
+		if !ok {
+			continue
+		}
+		// A draft being checked before it is published goes live now
+		first, updated := e.FirstPublished, r.Published
+		if first.IsZero() {
+			first = s.now()
+		}
+		if updated.IsZero() {
+			updated = s.now()
+		}
+		switch {
+		case r.Doc.Tutorial != nil:
+			t := *r.Doc.Tutorial
+			t.Published, t.Updated = first, updated
+			p.Tutorials = append(p.Tutorials, content.PublishedTutorial{Level: r.Doc.Level, Tutorial: t})
+		case r.Doc.Example != nil:
+			ex := *r.Doc.Example
+			ex.Published, ex.Updated = first, updated
+			p.Examples = append(p.Examples, ex)
+		}
+	}
+	return p
+}
+
+func copyEntry(e *Entry) Entry {
+	c := *e
+	c.History = append([]Revision(nil), e.History...)
+	c.Comments = append([]Comment(nil), e.Comments...)
+	c.Reviews = append([]Review(nil), e.Reviews...)
+	return c
+}
@@ -0,0 +1,187 @@
+package authoring
+
+import (
+	"errors"
+	"html/template"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/jsonfile"
+)
+
+// tutorialDoc returns a valid new tutorial document
+func tutorialDoc(id, title string, prereqs ...string) Document {
+	return Document{Kind: KindTutorial, Level: "intermediate", Tutorial: &content.Tutorial{
+		ID:            id,
+		Title:         title,
+		Description:   template.HTML("<p>About " + title + "</p>"),
+		Prerequisites: prereqs,
+		Code:          []content.CodeBlock{{Filename: "main.go", Language: "go", Source: "package main\n\nfunc main() {}\n", Highlight: "3"}},
+	}}
+}
+
+func TestValidate(t *testing.T) {
+	if problems := Validate(tutorialDoc("cookies", "Cookies")); problems != nil {
+		t.Fatalf("valid tutorial: %v", problems)
+	}
+	example := Document{Kind: KindExample, Example: &content.CodeExample{Title: "Echo", Description: "<p>Echo</p>", Filename: "echo_server.go", Code: "package main", LivePath: "/"}}
+	if problems := Validate(example); problems != nil {
+		t.Fatalf("valid example: %v", problems)
+	}
+
+	bad := tutorialDoc("Bad ID", "")
+	bad.Level = "expert"
+	bad.Tutorial.Code = append(bad.Tutorial.Code,
+		content.CodeBlock{Filename: "a.go", Language: "cobol", Source: "x"},
+		content.CodeBlock{Filename: "b.go", Language: "go", Source: "x", Highlight: "9"},
+		content.CodeBlock{Filename: "c.go", Language: "go", Source: "x", Callouts: []content.Callout{{Line: 2}}})
+	problems := Validate(bad)
+	for _, field := range []string{"id", "title", "level", "code.2", "code.3", "code.4"} {
+		if problems[field] == "" {
+			t.Errorf("no problem reported for %s: %v", field, problems)
+		}
+	}
+	if problems["code.1"] != "" || problems["description"] != "" {
+		t.Errorf("unexpected problems: %v", problems)
+	}
+
+	example.Example.Filename = "Echo.go"
+	example.Example.LivePath = "echo"
+	if problems := Validate(example); problems["id"] == "" || problems["live_path"] == "" {
+		t.Errorf("example problems = %v", problems)
+	}
+	if problems := Validate(Document{Kind: KindExample, Tutorial: &content.Tutorial{}}); problems["kind"] == "" {
+		t.Errorf("mismatched kind: %v", problems)
+	}
+}
+
+func TestWorkflow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "content.json")
+	s, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, time.July, 1, 9, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	s.RequiredApprovals = 0
+
+	if _, err := s.SaveDraft(tutorialDoc("cookies", ""), "ann", ""); err == nil {
+		t.Error("saved an invalid draft")
+	}
+	if _, err := s.SaveDraft(tutorialDoc("cookies", "Cookies", "html-templates"), "ann", "first go"); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := s.Entry(KindTutorial, "cookies"); e.Status() != StatusDraft || e.Draft != 1 || e.Published != 0 {
+		t.Fatalf("after save: %+v", e)
+	}
+	if len(s.Published().Tutorials) != 0 {
+		t.Error("draft is published")
+	}
+
+	// Publishing now makes the draft live
+	if err := s.Publish(KindTutorial, "cookies", now, "bob"); err != nil {
+		t.Fatal(err)
+	}
+	p := s.Published()
+	if len(p.Tutorials) != 1 || p.Tutorials[0].Tutorial.Title != "Cookies" || !p.Tutorials[0].Tutorial.Updated.Equal(now) {
+		t.Fatalf("published = %+v", p)
+	}
+	if err := s.Publish(KindTutorial, "cookies", now, "bob"); err != ErrNoDraft {
+		t.Errorf("publishing twice: %v", err)
+	}
+
+	// A scheduled draft goes live once its time has come
+	if _, err := s.SaveDraft(tutorialDoc("cookies", "Cookies, Revised", "html-templates"), "ann", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Publish(KindTutorial, "cookies", now.Add(time.Hour), "bob"); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := s.Entry(KindTutorial, "cookies"); e.Status() != StatusScheduled {
+		t.Errorf("status = %s", e.Status())
+	}
+	if keys, err := s.PublishDue(now.Add(time.Minute)); keys != nil || err != nil {
+		t.Errorf("published early: %v %v", keys, err)
+	}
+	now = now.Add(2 * time.Hour)
+	if keys, err := s.PublishDue(now); len(keys) != 1 || keys[0] != "tutorial/cookies" || err != nil {
+		t.Errorf("PublishDue = %v, %v", keys, err)
+	}
+	if got := s.Published().Tutorials[0].Tutorial; got.Title != "Cookies, Revised" || !got.Published.Before(got.Updated) {
+		t.Errorf("after schedule: %q published %v updated %v", got.Title, got.Published, got.Updated)
+	}
+
+	// Reverting makes a new draft from the old revision
+	r, err := s.Revert(KindTutorial, "cookies", 1, "ann")
+	if err != nil || r.Number != 3 || r.Doc.Tutorial.Title != "Cookies" || r.Note != "Revert to revision 1" {
+		t.Fatalf("Revert = %+v, %v", r, err)
+	}
+	if _, err := s.Revert(KindTutorial, "cookies", 9, "ann"); err != ErrNotFound {
+		t.Errorf("reverting to a missing revision: %v", err)
+	}
+	if err := s.Discard(KindTutorial, "cookies"); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := s.Entry(KindTutorial, "cookies"); e.Draft != 0 || e.Published != 2 || len(e.History) != 3 {
+		t.Errorf("after discard: %+v", e)
+	}
+
+	// Drafts that break the prerequisite graph are refused
+	if _, err := s.SaveDraft(tutorialDoc("loop", "Loop", "loop"), "ann", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Publish(KindTutorial, "loop", now, "bob"); err == nil || errors.Is(err, ErrNoDraft) {
+		t.Errorf("published a cycle: %v", err)
+	}
+	if err := s.Discard(KindTutorial, "loop"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.Entry(KindTutorial, "loop"); ok {
+		t.Error("unpublished entry kept after discard")
+	}
+
+	// Everything survives a restart
+	reloaded, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := reloaded.Entries(); len(entries) != 1 || len(entries[0].History) != 3 || entries[0].History[1].Publisher != "bob" {
+		t.Errorf("reloaded = %+v", entries)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "content.json")
+	a, _ := NewFileStore(path)
+	b, _ := NewFileStore(path)
+	a.RequiredApprovals = 0
+	a.SaveDraft(tutorialDoc("cookies", "Cookies"), "ann", "")
+	if err := a.Publish(KindTutorial, "cookies", time.Now(), "ann"); err != nil {
+		t.Fatal(err)
+	}
+
+	if changed, err := b.Reload(); !changed || err != nil {
+		t.Fatalf("Reload = %v, %v", changed, err)
+	}
+	if changed, err := b.Reload(); changed || err != nil {
+		t.Errorf("Reload without changes = %v, %v", changed, err)
+	}
+	if len(b.Published().Tutorials) != 1 {
+		t.Errorf("reloaded store publishes %+v", b.Published())
+	}
+
+	// A file that would publish a cycle is refused
+	e, _ := a.Entry(KindTutorial, "cookies")
+	e.History[0].Doc.Tutorial.Prerequisites = []string{"cookies"}
+	if err := jsonfile.Save(path, map[string]Entry{"tutorial/cookies": e}); err != nil {
+		t.Fatal(err)
+	}
+	if changed, err := b.Reload(); changed || err == nil {
+		t.Errorf("Reload of a cycle = %v, %v", changed, err)
+	}
+	if got := b.Published().Tutorials[0].Tutorial.Prerequisites; len(got) != 0 {
+		t.Errorf("prerequisites = %v after a refused reload", got)
+	}
+}
@@ -0,0 +1,49 @@
+// Package bookshelf hosts practice copies of the books API from the RESTful
+// tutorials. Each learner gets a private shelf of books, addressed by a random
+// token, that they can read and change with curl and reset at any time.
+package bookshelf
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Book is a book on a shelf, as in the rest_api example
+type Book struct {
+	ID     int    `json:"id"`
+	Title  string `json:"title"`
+	Author string `json:"author"`
+	Year   int    `json:"year"`
+}
+
+// SeedBooks are the books every new or reset shelf starts with
+var SeedBooks = []Book{
+	{ID: 1, Title: "The Go Programming Language", Author: "Alan Donovan & Brian Kernighan", Year: 2015},
+	{ID: 2, Title: "Go in Action", Author: "William Kennedy", Year: 2015},
+	{ID: 3, Title: "Concurrency in Go", Author: "Katherine Cox-Buday", Year: 2017},
+}
+
+// maxTextLength limits titles and authors, in characters
+const maxTextLength = 200
+
+// Validate checks a book sent by a client and returns a message for each
+// invalid field, or nil if the book is valid
+func Validate(b Book) map[string]string {
+	problems := make(map[string]string)
+	for field, value := range map[string]string{"title": b.Title, "author": b.Author} {
+		switch {
+		case strings.TrimSpace(value) == "":
+			problems[field] = "is required"
+		case utf8.RuneCountInString(value) > maxTextLength:
+			problems[field] = "must be at most 200 characters"
+		}
+	}
+	if b.Year != 0 && (b.Year < 1450 || b.Year > time.Now().Year()+1) {
+		problems["year"] = "must be between 1450 and next year"
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
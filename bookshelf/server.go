@@ -0,0 +1,440 @@
+package bookshelf
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCreateLimit is returned by Create when a client creates shelves faster
+// than Config.CreatesPerHour allows
+var ErrCreateLimit = errors.New("bookshelf: too many sandboxes created, try again later")
+
+// MaxBodySize limits the size of request bodies
+const MaxBodySize = 16 << 10
+
+// Config sets the limits of a Server. Zero values are replaced by defaults.
+type Config struct {
+	// Prefix is the path the server is mounted under, such as /sandbox
+	Prefix string
+
+	// TTL is how long a shelf lives after its last request (default 2 hours)
+	TTL time.Duration
+
+	// MaxShelves caps the number of shelves kept at once (default 1000).
+	// Creating one more removes the shelf that was used least recently.
+	MaxShelves int
+
+	// MaxBooks caps the number of books on one shelf (default 100)
+	MaxBooks int
+
+	// RequestsPerMinute is the sustained request rate allowed for one shelf
+	// (default 60), with bursts of up to Burst requests (default 20)
+	RequestsPerMinute int
+	Burst             int
+
+	// CreatesPerHour is the sustained rate at which one client may create
+	// shelves (default 20), with bursts of up to CreateBurst (default 5)
+	CreatesPerHour int
+	CreateBurst    int
+}
+
+func (c Config) withDefaults() Config {
+	if c.TTL <= 0 {
+		c.TTL = 2 * time.Hour
+	}
+	if c.MaxShelves <= 0 {
+		c.MaxShelves = 1000
+	}
+	if c.MaxBooks <= 0 {
+		c.MaxBooks = 100
+	}
+	if c.RequestsPerMinute <= 0 {
+		c.RequestsPerMinute = 60
+	}
+	if c.Burst <= 0 {
+		c.Burst = 20
+	}
+	if c.CreatesPerHour <= 0 {
+		c.CreatesPerHour = 20
+	}
+	if c.CreateBurst <= 0 {
+		c.CreateBurst = 5
+	}
+	return c
+}
+
+// Server serves the books API for every shelf. Requests look like
+// {Prefix}/{token}/api/books and {Prefix}/{token}/api/books/{id}, and
+// POST {Prefix}/{token}/reset puts the seed books back.
+type Server struct {
+	config Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	shelves map[string]*shelf
+
+	// creators holds each client's budget for creating shelves
+	creators map[string]*bucket
+}
+
+// shelf is one learner's books and request budget
+type shelf struct {
+	books    []Book
+	nextID   int
+	lastUsed time.Time
+	bucket
+}
+
+// bucket is a token bucket for rate limiting
+type bucket struct {
+	tokens   float64
+	refilled time.Time
+}
+
+// Info describes a shelf
+type Info struct {
+	Books   int
+	Expires time.Time
+}
+
+// NewServer creates a server with no shelves
+func NewServer(config Config) *Server {
+	return &Server{
+		config:   config.withDefaults(),
+		now:      time.Now,
+		shelves:  make(map[string]*shelf),
+		creators: make(map[string]*bucket),
+	}
+}
+
+// Create makes a new shelf of seed books for client, such as the remote IP
+// address, and returns its token. When the server is full, the shelf used
+// least recently is removed to make room.
+func (s *Server) Create(client string) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	creator, ok := s.creators[client]
+	if !ok {
+		creator = &bucket{tokens: float64(s.config.CreateBurst), refilled: now}
+		s.creators[client] = creator
+	}
+	if _, wait := creator.take(now, float64(s.config.CreatesPerHour)/3600, s.config.CreateBurst); wait > 0 {
+		return "", ErrCreateLimit
+	}
+
+	s.deleteExpired(now)
+	if len(s.shelves) >= s.config.MaxShelves {
+		s.deleteLeastRecentlyUsed()
+	}
+	sh := &shelf{bucket: bucket{tokens: float64(s.config.Burst), refilled: now}}
+	sh.reset(now)
+	s.shelves[token] = sh
+	return token, nil
+}
+
+// Info reports on the shelf with the given token, if it exists
+func (s *Server) Info(token string) (Info, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh, ok := s.shelf(token)
+	if !ok {
+		return Info{}, false
+	}
+	return Info{Books: len(sh.books), Expires: sh.lastUsed.Add(s.config.TTL)}, true
+}
+
+// Reset puts the seed books back on a shelf and reports whether it exists
+func (s *Server) Reset(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sh, ok := s.shelf(token)
+	if ok {
+		sh.reset(s.now())
+	}
+	return ok
+}
+
+// DeleteExpired removes shelves that have not been used within the TTL
+func (s *Server) DeleteExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleteExpired(now)
+}
+
+func (s *Server) deleteExpired(now time.Time) {
+	for token, sh := range s.shelves {
+		if now.Sub(sh.lastUsed) > s.config.TTL {
+			delete(s.shelves, token)
+		}
+	}
+	// Clients whose budget has refilled are no different from new ones
+	refill := time.Duration(float64(s.config.CreateBurst) / float64(s.config.CreatesPerHour) * float64(time.Hour))
+	for client, b := range s.creators {
+		if now.Sub(b.refilled) > refill {
+			delete(s.creators, client)
+		}
+	}
+}
+
+// deleteLeastRecentlyUsed removes the shelf that has gone unused longest.
+// The caller must hold s.mu.
+func (s *Server) deleteLeastRecentlyUsed() {
+	var oldest string
+	for token, sh := range s.shelves {
+		if oldest == "" || sh.lastUsed.Before(s.shelves[oldest].lastUsed) {
+			oldest = token
+		}
+	}
+	delete(s.shelves, oldest)
+}
+
+// shelf returns a shelf that has not expired. The caller must hold s.mu.
+func (s *Server) shelf(token string) (*shelf, bool) {
+	sh, ok := s.shelves[token]
+	if !ok || s.now().Sub(sh.lastUsed) > s.config.TTL {
+		return nil, false
+	}
+	return sh, true
+}
+
+func (sh *shelf) reset(now time.Time) {
+	sh.books = append([]Book(nil), SeedBooks...)
+	sh.nextID = len(SeedBooks) + 1
+	sh.lastUsed = now
+}
+
+// allow takes a token from the shelf's bucket, returning how long to wait
+// if it is empty
+func (sh *shelf) allow(now time.Time, config Config) (remaining int, wait time.Duration) {
+	return sh.take(now, float64(config.RequestsPerMinute)/60, config.Burst)
+}
+
+// take refills the bucket at rate tokens per second up to burst and takes a
+// token, returning how long to wait if it is empty
+func (b *bucket) take(now time.Time, rate float64, burst int) (remaining int, wait time.Duration) {
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.refilled).Seconds()*rate)
+	b.refilled = now
+	if b.tokens < 1 {
+		return 0, time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	}
+	b.tokens--
+	return int(b.tokens), 0
+}
+
+// find returns the index of the book with the given ID, or -1
+func (sh *shelf) find(id int) int {
+	for i, b := range sh.books {
+		if b.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// apiError is the JSON body of every error response
+type apiError struct {
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, apiError{Error: message})
+}
+
+// ServeHTTP routes a request to a shelf
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rest := strings.TrimPrefix(r.URL.Path, s.config.Prefix+"/")
+	token, rest, _ := strings.Cut(rest, "/")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	sh, ok := s.shelf(token)
+	if !ok {
+		writeError(w, http.StatusNotFound, "sandbox not found or expired")
+		return
+	}
+	sh.lastUsed = now
+
+	remaining, wait := sh.allow(now, s.config)
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.config.RequestsPerMinute))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+	if wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "rate limit exceeded, slow down")
+		return
+	}
+
+	base := s.config.Prefix + "/" + token + "/api/books"
+	switch {
+	case rest == "reset":
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", "POST")
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+		sh.reset(now)
+		writeJSON(w, http.StatusOK, sh.books)
+	case rest == "api/books":
+		s.serveBooks(w, r, sh, base)
+	case strings.HasPrefix(rest, "api/books/"):
+		id, err := strconv.Atoi(strings.TrimPrefix(rest, "api/books/"))
+		if err != nil || id < 1 {
+			writeError(w, http.StatusBadRequest, "invalid book ID")
+			return
+		}
+		s.serveBook(w, r, sh, id)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// serveBooks handles the collection: list and create
+func (s *Server) serveBooks(w http.ResponseWriter, r *http.Request, sh *shelf, base string) {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		books := append([]Book{}, sh.books...)
+		sort.Slice(books, func(i, j int) bool { return books[i].ID < books[j].ID })
+		writeJSON(w, http.StatusOK, books)
+
+	case http.MethodPost:
+		var book Book
+		if !decode(w, r, &book) {
+			return
+		}
+		if problems := Validate(book); problems != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, apiError{Error: "validation failed", Fields: problems})
+			return
+		}
+		if len(sh.books) >= s.config.MaxBooks {
+			writeError(w, http.StatusConflict, fmt.Sprintf("this sandbox already holds %d books; delete some or reset it", s.config.MaxBooks))
+			return
+		}
+		book.ID = sh.nextID
+		sh.nextID++
+		sh.books = append(sh.books, book)
+		w.Header().Set("Location", base+"/"+strconv.Itoa(book.ID))
+		writeJSON(w, http.StatusCreated, book)
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, POST")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// bookPatch holds the fields a PATCH request may change
+type bookPatch struct {
+	Title  *string `json:"title"`
+	Author *string `json:"author"`
+	Year   *int    `json:"year"`
+}
+
+// serveBook handles a single book: read, replace, update and delete
+func (s *Server) serveBook(w http.ResponseWriter, r *http.Request, sh *shelf, id int) {
+	i := sh.find(id)
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "book not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		writeJSON(w, http.StatusOK, sh.books[i])
+
+	case http.MethodPut:
+		var book Book
+		if !decode(w, r, &book) {
+			return
+		}
+		if book.ID != 0 && book.ID != id {
+			writeError(w, http.StatusBadRequest, "the ID in the body does not match the URL")
+			return
+		}
+		book.ID = id
+		if problems := Validate(book); problems != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, apiError{Error: "validation failed", Fields: problems})
+			return
+		}
+		sh.books[i] = book
+		writeJSON(w, http.StatusOK, book)
+
+	case http.MethodPatch:
+		var patch bookPatch
+		if !decode(w, r, &patch) {
+			return
+		}
+		book := sh.books[i]
+		if patch.Title != nil {
+			book.Title = *patch.Title
+		}
+		if patch.Author != nil {
+			book.Author = *patch.Author
+		}
+		if patch.Year != nil {
+			book.Year = *patch.Year
+		}
+		if problems := Validate(book); problems != nil {
+			writeJSON(w, http.StatusUnprocessableEntity, apiError{Error: "validation failed", Fields: problems})
+			return
+		}
+		sh.books[i] = book
+		writeJSON(w, http.StatusOK, book)
+
+	case http.MethodDelete:
+		sh.books = append(sh.books[:i], sh.books[i+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT, PATCH, DELETE")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// decode reads a JSON request body into v, writing an error response and
+// returning false if it cannot
+func decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, "send JSON with Content-Type: application/json")
+		return false
+	}
+
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeError(w, http.StatusRequestEntityTooLarge, "request body is too large")
+		} else {
+			writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,210 @@
+package bookshelf
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// do sends a request to the server and returns the recorder
+func do(s *Server, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rr := httptest.NewRecorder()
+	s.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestCRUD(t *testing.T) {
+	s := NewServer(Config{Prefix: "/sandbox"})
+	token, err := s.Create("192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	books := "/sandbox/" + token + "/api/books"
+
+	rr := do(s, "POST", books, `{"title": "Learning Go", "author": "Jon Bodner", "year": 2021}`)
+	if rr.Code != http.StatusCreated || rr.Header().Get("Location") != books+"/4" {
+		t.Fatalf("create: %d %q %s", rr.Code, rr.Header().Get("Location"), rr.Body)
+	}
+
+	rr = do(s, "GET", books+"/4", "")
+	var got Book
+	json.Unmarshal(rr.Body.Bytes(), &got)
+	if want := (Book{ID: 4, Title: "Learning Go", Author: "Jon Bodner", Year: 2021}); got != want {
+		t.Errorf("get: %+v, want %+v", got, want)
+	}
+
+	rr = do(s, "PATCH", books+"/4", `{"year": 2024}`)
+	json.Unmarshal(rr.Body.Bytes(), &got)
+	if rr.Code != http.StatusOK || got.Year != 2024 || got.Title != "Learning Go" {
+		t.Errorf("patch: %d %+v", rr.Code, got)
+	}
+
+	if rr = do(s, "PUT", books+"/4", `{"title": "Learning Go, 2nd Edition", "author": "Jon Bodner"}`); rr.Code != http.StatusOK {
+		t.Errorf("put: %d %s", rr.Code, rr.Body)
+	}
+	if rr = do(s, "DELETE", books+"/4", ""); rr.Code != http.StatusNoContent {
+		t.Errorf("delete: %d", rr.Code)
+	}
+	if rr = do(s, "GET", books+"/4", ""); rr.Code != http.StatusNotFound {
+		t.Errorf("get deleted book: %d", rr.Code)
+	}
+
+	// Shelves are separate
+	other, _ := s.Create("192.0.2.1")
+	do(s, "DELETE", books+"/1", "")
+	if rr = do(s, "GET", "/sandbox/"+other+"/api/books/1", ""); rr.Code != http.StatusOK {
+		t.Errorf("other shelf lost book 1: %d", rr.Code)
+	}
+
+	// Reset restores the seed books
+	if rr = do(s, "POST", "/sandbox/"+token+"/reset", ""); rr.Code != http.StatusOK {
+		t.Errorf("reset: %d", rr.Code)
+	}
+	var list []Book
+	json.Unmarshal(do(s, "GET", books, "").Body.Bytes(), &list)
+	if len(list) != len(SeedBooks) || list[0] != SeedBooks[0] {
+		t.Errorf("after reset: %+v", list)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	s := NewServer(Config{Prefix: "/sandbox", MaxBooks: 3})
+	token, _ := s.Create("192.0.2.1")
+	books := "/sandbox/" + token + "/api/books"
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		status       int
+	}{
+		{"unknown token", "GET", "/sandbox/nope/api/books", "", http.StatusNotFound},
+		{"unknown path", "GET", "/sandbox/" + token + "/api/authors", "", http.StatusNotFound},
+		{"bad ID", "GET", books + "/abc", "", http.StatusBadRequest},
+		{"missing book", "GET", books + "/99", "", http.StatusNotFound},
+		{"wrong method", "PUT", books, `{}`, http.StatusMethodNotAllowed},
+		{"not JSON", "POST", books, `title=Go`, http.StatusBadRequest},
+		{"unknown field", "POST", books, `{"title": "Go", "author": "A", "pages": 3}`, http.StatusBadRequest},
+		{"invalid book", "POST", books, `{"title": " ", "year": 3000}`, http.StatusUnprocessableEntity},
+		{"mismatched ID", "PUT", books + "/1", `{"id": 2, "title": "Go", "author": "A"}`, http.StatusBadRequest},
+		{"full shelf", "POST", books, `{"title": "Go", "author": "A"}`, http.StatusConflict},
+		{"too large", "POST", books, `{"title": "` + strings.Repeat("x", MaxBodySize) + `"}`, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		rr := do(s, tt.method, tt.path, tt.body)
+		if rr.Code != tt.status {
+			t.Errorf("%s: status %d, want %d: %s", tt.name, rr.Code, tt.status, rr.Body)
+		}
+		var body apiError
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body.Error == "" {
+			t.Errorf("%s: body is not a JSON error: %s", tt.name, rr.Body)
+		}
+	}
+
+	req := httptest.NewRequest("POST", books, strings.NewReader(`{"title": "Go"}`))
+	req.Header.Set("Content-Type", "text/plain")
+	rr := httptest.NewRecorder()
+	s.ServeHTTP(rr, req)
+	if rr.Code != http.StatusUnsupportedMediaType {
+		t.Errorf("wrong content type: %d", rr.Code)
+	}
+
+	var body apiError
+	json.Unmarshal(do(s, "POST", books, `{"title": " ", "year": 3000}`).Body.Bytes(), &body)
+	if body.Fields["title"] == "" || body.Fields["author"] == "" || body.Fields["year"] == "" {
+		t.Errorf("validation fields: %v", body.Fields)
+	}
+}
+
+func TestRateLimitAndExpiry(t *testing.T) {
+	now := time.Date(2025, time.June, 1, 12, 0, 0, 0, time.UTC)
+	s := NewServer(Config{Prefix: "/sandbox", RequestsPerMinute: 60, Burst: 2, TTL: time.Hour})
+	s.now = func() time.Time { return now }
+	token, _ := s.Create("192.0.2.1")
+	books := "/sandbox/" + token + "/api/books"
+
+	for i := 0; i < 2; i++ {
+		if rr := do(s, "GET", books, ""); rr.Code != http.StatusOK {
+			t.Fatalf("request %d: %d", i, rr.Code)
+		}
+	}
+	rr := do(s, "GET", books, "")
+	if rr.Code != http.StatusTooManyRequests || rr.Header().Get("Retry-After") != "1" {
+		t.Errorf("over limit: %d, Retry-After %q", rr.Code, rr.Header().Get("Retry-After"))
+	}
+	now = now.Add(time.Second)
+	if rr := do(s, "GET", books, ""); rr.Code != http.StatusOK {
+		t.Errorf("after waiting: %d", rr.Code)
+	}
+
+	if info, ok := s.Info(token); !ok || !info.Expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("Info = %+v, %v", info, ok)
+	}
+	now = now.Add(time.Hour + time.Second)
+	if rr := do(s, "GET", books, ""); rr.Code != http.StatusNotFound {
+		t.Errorf("expired shelf: %d", rr.Code)
+	}
+	s.DeleteExpired(now)
+	if len(s.shelves) != 0 {
+		t.Errorf("%d shelves left after DeleteExpired", len(s.shelves))
+	}
+}
+
+func TestMaxShelves(t *testing.T) {
+	s := NewServer(Config{MaxShelves: 2})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	old, _ := s.Create("192.0.2.1")
+	now = now.Add(time.Minute)
+	recent, _ := s.Create("192.0.2.2")
+	now = now.Add(time.Minute)
+	if _, err := s.Create("192.0.2.3"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A full server makes room by removing the shelf used least recently
+	if _, ok := s.Info(old); ok {
+		t.Error("least recently used shelf was kept")
+	}
+	if _, ok := s.Info(recent); !ok {
+		t.Error("recently used shelf was removed")
+	}
+}
+
+func TestCreateLimit(t *testing.T) {
+	s := NewServer(Config{CreatesPerHour: 60, CreateBurst: 2})
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.Create("192.0.2.1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Create("192.0.2.1"); err != ErrCreateLimit {
+		t.Errorf("third shelf in a burst: got %v, want ErrCreateLimit", err)
+	}
+	if _, err := s.Create("192.0.2.2"); err != nil {
+		t.Errorf("another client was limited: %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := s.Create("192.0.2.1"); err != nil {
+		t.Errorf("after a minute: %v", err)
+	}
+
+	// Clients with a full budget are forgotten
+	now = now.Add(time.Hour)
+	s.DeleteExpired(now)
+	if len(s.creators) != 0 {
+		t.Errorf("%d clients remembered", len(s.creators))
+	}
+}
@@ -0,0 +1,91 @@
+// Command conformance checks that a server on localhost implements the books
+// API from the RESTful tutorials.
+//
+// Usage:
+//
+//	go run ./cmd/conformance [-json] [-v] http://localhost:8080
+//
+// It exits with status 1 if any check fails.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/conformance"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	verbose := flag.Bool("v", false, "show the requests and responses of passing checks too")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: conformance [-json] [-v] base-url")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	base, err := conformance.ParseBaseURL(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	report := conformance.Run(ctx, nil, base)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		printReport(report, *verbose)
+	}
+	if !report.Passed {
+		os.Exit(1)
+	}
+}
+
+func printReport(report conformance.Report, verbose bool) {
+	fmt.Printf("Checking the books API at %s/api/books\n\n", report.BaseURL)
+	for _, result := range report.Results {
+		status := "PASS"
+		if result.Skipped {
+			status = "SKIP"
+		} else if !result.Passed {
+			status = "FAIL"
+		}
+		fmt.Printf("%s  %s\n", status, result.Name)
+		if result.Passed && !verbose {
+			continue
+		}
+		fmt.Printf("      %s\n", result.Description)
+		if result.Message != "" {
+			fmt.Printf("      %s\n", result.Message)
+		}
+		for _, ex := range result.Exchanges {
+			fmt.Printf("      > %s %s\n", ex.Method, ex.URL)
+			if ex.RequestBody != "" {
+				fmt.Printf("        %s\n", ex.RequestBody)
+			}
+			if ex.Error != "" {
+				fmt.Printf("      < error: %s\n", ex.Error)
+				continue
+			}
+			fmt.Printf("      < %d %s\n", ex.Status, ex.ContentType)
+			if body := strings.TrimSpace(ex.Body); body != "" {
+				fmt.Printf("        %s\n", body)
+			}
+		}
+	}
+	fmt.Printf("\n%d of %d checks passed in %s\n", report.PassedCount(), len(report.Results), report.Duration.Round(time.Millisecond))
+}
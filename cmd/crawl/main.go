@@ -0,0 +1,132 @@
+// Command crawl starts the tutorial site in-process and follows every link
+// from the home page, reporting links, #anchors, static files and downloads
+// that do not work along with the page they appear on. Links to other sites
+// are listed but not fetched.
+//
+// Usage:
+//
+//	go run ./cmd/crawl [-json] [-external] [-root dir]
+//
+// It exits with status 1 if any link is broken.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/authoring"
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/crawl"
+	"golang-webserver-tutorial/handlers"
+)
+
+// skipped are paths the crawler leaves alone: event streams never finish
+// and live examples only answer while they are running
+var skipped = []string{"/events/", "/live/"}
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	external := flag.Bool("external", false, "list the links to other sites")
+	root := flag.String("root", ".", "the repository directory")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: crawl [-json] [-external] [-root dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := os.Chdir(*root); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	site, err := newSite()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	report, err := crawlSite(ctx, site)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	} else {
+		printReport(report, *external)
+	}
+	if !report.Passed {
+		os.Exit(1)
+	}
+}
+
+// newSite sets up the site's handlers the way the server does, with the
+// content published from the admin area, and must be run from the
+// repository directory
+func newSite() (http.Handler, error) {
+	var err error
+	handlers.Authoring, err = authoring.NewFileStore(filepath.Join("data", "content.json"))
+	if err != nil {
+		return nil, err
+	}
+	content.SetPublished(handlers.Authoring.Published())
+	if err := handlers.ReloadTemplates(); err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	handlers.Register(mux, handlers.Routes())
+	return handlers.Localize(mux), nil
+}
+
+// crawlSite serves the site on a local port for the length of the crawl
+func crawlSite(ctx context.Context, site http.Handler) (crawl.Report, error) {
+	server := httptest.NewServer(site)
+	defer server.Close()
+
+	c := crawl.Crawler{
+		Client: server.Client(),
+		Skip: func(path string) bool {
+			for _, prefix := range skipped {
+				if strings.HasPrefix(path, prefix) {
+					return true
+				}
+			}
+			return false
+		},
+	}
+	return c.Crawl(ctx, server.URL+"/")
+}
+
+func printReport(report crawl.Report, external bool) {
+	fmt.Printf("Crawled %d pages and checked %d URLs in %s\n", len(report.Pages), report.Checked, report.Duration.Round(time.Millisecond))
+	if len(report.Broken) > 0 {
+		fmt.Printf("\nBroken links (%d):\n", len(report.Broken))
+		for _, link := range report.Broken {
+			fmt.Printf("  %s\n", link)
+		}
+	}
+	if external {
+		fmt.Printf("\nExternal links, not checked (%d):\n", len(report.External))
+		for _, link := range report.External {
+			fmt.Printf("  %s\n", link)
+		}
+	} else if len(report.External) > 0 {
+		fmt.Printf("%d links to other sites were not checked; list them with -external\n", len(report.External))
+	}
+}
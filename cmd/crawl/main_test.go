@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+// TestSite crawls the whole site so broken links fail the build
+func TestSite(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	site, err := newSite()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	report, err := crawlSite(ctx, site)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Pages) < 10 {
+		t.Errorf("crawled only %v", report.Pages)
+	}
+	for _, link := range report.Broken {
+		t.Error(link)
+	}
+}
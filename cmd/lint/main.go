@@ -0,0 +1,103 @@
+// Command lint checks the tutorials and examples for mistakes such as
+// duplicate IDs, broken links to the site, unclosed HTML tags and example
+// directories that do not match their files. Content published from the
+// admin area in data/content.json is checked in place of the built-in
+// content it replaces.
+//
+// Usage:
+//
+//	go run ./cmd/lint [-json] [-rules] [-root dir]
+//
+// It exits with status 1 if there are any errors; warnings alone pass.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"golang-webserver-tutorial/authoring"
+	"golang-webserver-tutorial/handlers"
+	"golang-webserver-tutorial/lint"
+)
+
+func main() {
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	listRules := flag.Bool("rules", false, "list the rules instead of checking")
+	root := flag.String("root", ".", "the repository directory")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: lint [-json] [-rules] [-root dir]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *listRules {
+		if *asJSON {
+			printJSON(lint.Rules)
+			return
+		}
+		for _, rule := range lint.Rules {
+			fmt.Printf("%-20s %-8s %s\n", rule.ID, rule.Severity, rule.Summary)
+		}
+		return
+	}
+
+	c, err := load(*root)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	report := lint.Check(c)
+
+	if *asJSON {
+		printJSON(report)
+	} else {
+		for _, f := range report.Findings {
+			fmt.Println(f)
+		}
+		fmt.Printf("%d errors, %d warnings\n", report.Errors, report.Warnings)
+	}
+	if report.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// load gathers the content served by the site, with the edits published
+// from the admin area applied. The built-in content is read as written, as
+// the site's sanitizer would quietly repair some of the mistakes lint reports.
+func load(root string) (lint.Content, error) {
+	store, err := authoring.NewFileStore(filepath.Join(root, "data", "content.json"))
+	if err != nil {
+		return lint.Content{}, err
+	}
+	published := store.Published()
+
+	c := lint.Content{
+		Levels:   published.SourceLevels(),
+		Examples: published.SourceCodeExamples(),
+		Root:     root,
+		Edited:   make(map[string]bool),
+	}
+	for _, route := range handlers.Routes() {
+		c.Routes = append(c.Routes, route.Pattern)
+	}
+	for _, pt := range published.Tutorials {
+		c.Edited["tutorial/"+pt.Tutorial.ID] = true
+	}
+	for _, ex := range published.Examples {
+		c.Edited["example/"+ex.Filename] = true
+	}
+	return c, nil
+}
+
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"testing"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/lint"
+)
+
+// TestContent lints the content in the repository, so mistakes fail the
+// build rather than reaching the site
+func TestContent(t *testing.T) {
+	c, err := load("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Levels) != len(content.GetLevels()) || len(c.Routes) == 0 {
+		t.Fatalf("loaded %d levels and %d routes", len(c.Levels), len(c.Routes))
+	}
+	report := lint.Check(c)
+	for _, f := range report.Findings {
+		if f.Severity == lint.SeverityError {
+			t.Error(f)
+		} else {
+			t.Log(f)
+		}
+	}
+}
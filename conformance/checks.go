@@ -0,0 +1,296 @@
+package conformance
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+)
+
+// check is one scripted check. run returns an error describing the first
+// problem it finds.
+type check struct {
+	name        string
+	description string
+
+	// needsBook skips the check unless an earlier check created a book
+	needsBook bool
+
+	run func(s *session) error
+}
+
+// testBook is the book the checks create
+var testBook = map[string]interface{}{
+	"title":  "The Conformance Checker's Handbook",
+	"author": "Go Web Server Tutorial",
+	"year":   2025,
+}
+
+// checks run in order; later ones use the book created by "Create a book"
+var checks = []check{
+	{
+		name:        "List books",
+		description: "GET /api/books returns 200 OK and a JSON array of books.",
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodGet, "/api/books", nil)
+			if err != nil {
+				return err
+			}
+			if err := expectStatus(resp, http.StatusOK); err != nil {
+				return err
+			}
+			var books interface{}
+			if err := expectJSON(resp, &books); err != nil {
+				return err
+			}
+			list, ok := books.([]interface{})
+			if !ok {
+				return fmt.Errorf("expected a JSON array, got %s", jsonType(books))
+			}
+			for i, book := range list {
+				if _, err := expectBook(book); err != nil {
+					return fmt.Errorf("book %d: %v", i+1, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		name:        "Create a book",
+		description: "POST /api/books with a JSON book returns 201 Created and the new book with its ID.",
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodPost, "/api/books", testBook)
+			if err != nil {
+				return err
+			}
+			if err := expectStatus(resp, http.StatusCreated); err != nil {
+				return err
+			}
+			var v interface{}
+			if err := expectJSON(resp, &v); err != nil {
+				return err
+			}
+			book, err := expectBook(v)
+			if err != nil {
+				return err
+			}
+			if book["title"] != testBook["title"] || book["author"] != testBook["author"] {
+				return fmt.Errorf("the created book's title and author do not match what was sent")
+			}
+			s.book = book
+			return nil
+		},
+	},
+	{
+		name:        "Fetch the created book",
+		description: "GET /api/books/{id} returns 200 OK and the book that was created.",
+		needsBook:   true,
+		run: func(s *session) error {
+			book, err := s.fetch()
+			if err != nil {
+				return err
+			}
+			if book["title"] != testBook["title"] {
+				return fmt.Errorf("expected title %q, got %v", testBook["title"], book["title"])
+			}
+			return nil
+		},
+	},
+	{
+		name:        "New book is listed",
+		description: "The created book appears in GET /api/books.",
+		needsBook:   true,
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodGet, "/api/books", nil)
+			if err != nil {
+				return err
+			}
+			var books []map[string]interface{}
+			if err := expectJSON(resp, &books); err != nil {
+				return err
+			}
+			for _, book := range books {
+				if book["id"] == s.book["id"] {
+					return nil
+				}
+			}
+			return fmt.Errorf("book %v is not in the list", s.book["id"])
+		},
+	},
+	{
+		name:        "Replace the book",
+		description: "PUT /api/books/{id} with a changed book returns 200 OK or 204 No Content, and the change is saved.",
+		needsBook:   true,
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodPut, s.bookPath(), s.updatedBook())
+			if err != nil {
+				return err
+			}
+			if err := expectStatus(resp, http.StatusOK, http.StatusNoContent); err != nil {
+				return err
+			}
+			book, err := s.fetch()
+			if err != nil {
+				return err
+			}
+			if book["title"] != s.updatedBook()["title"] {
+				return fmt.Errorf("after PUT the title is %v", book["title"])
+			}
+			return nil
+		},
+	},
+	{
+		name:        "PUT is idempotent",
+		description: "Sending the same PUT again succeeds and leaves the book and the number of books unchanged.",
+		needsBook:   true,
+		run: func(s *session) error {
+			before, err := s.snapshot()
+			if err != nil {
+				return err
+			}
+			resp, err := s.do(http.MethodPut, s.bookPath(), s.updatedBook())
+			if err != nil {
+				return err
+			}
+			if err := expectStatus(resp, http.StatusOK, http.StatusNoContent); err != nil {
+				return err
+			}
+			after, err := s.snapshot()
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(before, after) {
+				return fmt.Errorf("repeating the PUT changed the list of books")
+			}
+			return nil
+		},
+	},
+	{
+		name:        "Unknown book returns 404",
+		description: "GET /api/books/{id} for an ID that does not exist returns 404 Not Found.",
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodGet, "/api/books/987654321", nil)
+			if err != nil {
+				return err
+			}
+			return expectStatus(resp, http.StatusNotFound)
+		},
+	},
+	{
+		name:        "Invalid ID is rejected",
+		description: "GET /api/books/not-a-number returns 400 Bad Request or 404 Not Found.",
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodGet, "/api/books/not-a-number", nil)
+			if err != nil {
+				return err
+			}
+			return expectStatus(resp, http.StatusBadRequest, http.StatusNotFound)
+		},
+	},
+	{
+		name:        "Unsupported method returns 405",
+		description: "PATCH /api/books, which the collection does not support, returns 405 Method Not Allowed.",
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodPatch, "/api/books", testBook)
+			if err != nil {
+				return err
+			}
+			return expectStatus(resp, http.StatusMethodNotAllowed)
+		},
+	},
+	{
+		name:        "Malformed JSON is rejected",
+		description: "POST /api/books with a broken JSON body returns 400 Bad Request or 422 Unprocessable Entity.",
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodPost, "/api/books", `{"title": `)
+			if err != nil {
+				return err
+			}
+			return expectStatus(resp, http.StatusBadRequest, http.StatusUnprocessableEntity)
+		},
+	},
+	{
+		name:        "Delete the book",
+		description: "DELETE /api/books/{id} returns 204 No Content or 200 OK, and the book is gone afterwards.",
+		needsBook:   true,
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodDelete, s.bookPath(), nil)
+			if err != nil {
+				return err
+			}
+			if err := expectStatus(resp, http.StatusNoContent, http.StatusOK); err != nil {
+				return err
+			}
+			return s.expectGone()
+		},
+	},
+	{
+		name:        "DELETE is idempotent",
+		description: "Deleting the book again does not fail with a server error and the book stays deleted.",
+		needsBook:   true,
+		run: func(s *session) error {
+			resp, err := s.do(http.MethodDelete, s.bookPath(), nil)
+			if err != nil {
+				return err
+			}
+			if err := expectStatus(resp, http.StatusNotFound, http.StatusNoContent, http.StatusOK); err != nil {
+				return err
+			}
+			return s.expectGone()
+		},
+	},
+}
+
+// bookPath returns the URL path of the created book
+func (s *session) bookPath() string {
+	return fmt.Sprintf("/api/books/%d", int64(s.book["id"].(float64)))
+}
+
+// updatedBook returns the created book with a new title
+func (s *session) updatedBook() map[string]interface{} {
+	return map[string]interface{}{
+		"id":     s.book["id"],
+		"title":  "The Conformance Checker's Handbook, Second Edition",
+		"author": testBook["author"],
+		"year":   testBook["year"],
+	}
+}
+
+// fetch gets the created book, failing unless it is returned as JSON
+func (s *session) fetch() (map[string]interface{}, error) {
+	resp, err := s.do(http.MethodGet, s.bookPath(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+	var v interface{}
+	if err := expectJSON(resp, &v); err != nil {
+		return nil, err
+	}
+	return expectBook(v)
+}
+
+// snapshot returns the body of the book list
+func (s *session) snapshot() ([]byte, error) {
+	resp, err := s.do(http.MethodGet, "/api/books", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := expectStatus(resp, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// expectGone fails unless fetching the created book returns 404
+func (s *session) expectGone() error {
+	resp, err := s.do(http.MethodGet, s.bookPath(), nil)
+	if err != nil {
+		return err
+	}
+	if resp.Status != http.StatusNotFound {
+		return fmt.Errorf("after DELETE, GET %s returned %d instead of 404 Not Found", s.bookPath(), resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,283 @@
+// Package conformance checks that a learner's server implements the books
+// API from the RESTful tutorials. It sends scripted requests to a server on
+// localhost and reports on status codes, headers, JSON shapes, error
+// handling and idempotency.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrNotLocal is returned for base URLs that are not on this machine
+var ErrNotLocal = errors.New("conformance: the server must run on localhost")
+
+// MaxBodySize limits how much of each response is read
+const MaxBodySize = 1 << 20
+
+// RequestTimeout limits each request made by a check
+const RequestTimeout = 5 * time.Second
+
+// Exchange is a request made by a check and the response it got
+type Exchange struct {
+	Method      string `json:"method"`
+	URL         string `json:"url"`
+	RequestBody string `json:"request_body,omitempty"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	Passed      bool       `json:"passed"`
+	Skipped     bool       `json:"skipped,omitempty"`
+	Message     string     `json:"message,omitempty"`
+	Exchanges   []Exchange `json:"exchanges"`
+}
+
+// Report is the outcome of a whole run
+type Report struct {
+	BaseURL  string        `json:"base_url"`
+	Results  []Result      `json:"results"`
+	Passed   bool          `json:"passed"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// PassedCount returns the number of checks that passed
+func (r Report) PassedCount() int {
+	n := 0
+	for _, result := range r.Results {
+		if result.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// ParseBaseURL checks that raw is an http URL for a server on localhost and
+// returns it without a trailing slash. The books API is expected at
+// {base}/api/books.
+func ParseBaseURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return "", fmt.Errorf("conformance: %q is not an http URL such as http://localhost:8080", raw)
+	}
+	if !isLocal(u.Hostname()) {
+		return "", ErrNotLocal
+	}
+	u.RawQuery, u.Fragment = "", ""
+	return strings.TrimRight(u.String(), "/"), nil
+}
+
+func isLocal(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// HandlerClient returns a client that serves every request with h in the
+// same process instead of sending it over the network, whatever its host.
+// It does not follow redirects.
+func HandlerClient(h http.Handler) *http.Client {
+	return &http.Client{
+		Transport:     handlerTransport{h},
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+	}
+}
+
+type handlerTransport struct {
+	handler http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rr := httptest.NewRecorder()
+	req.RequestURI = req.URL.RequestURI()
+	t.handler.ServeHTTP(rr, req)
+	return rr.Result(), nil
+}
+
+// Run runs every check against the server at base, which must have been
+// accepted by ParseBaseURL. A nil client uses one that does not follow
+// redirects.
+func Run(ctx context.Context, client *http.Client, base string) Report {
+	if client == nil {
+		client = &http.Client{
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		}
+	}
+	report := Report{BaseURL: base, Passed: true, Started: time.Now()}
+	s := &session{ctx: ctx, client: client, base: base}
+	for _, c := range checks {
+		result := Result{Name: c.name, Description: c.description, Exchanges: []Exchange{}}
+		s.result = &result
+		switch {
+		case ctx.Err() != nil:
+			result.Skipped = true
+			result.Message = "the run was cancelled"
+		case c.needsBook && s.book == nil:
+			result.Skipped = true
+			result.Message = "skipped because no book could be created"
+		default:
+			if err := c.run(s); err != nil {
+				result.Message = err.Error()
+			} else {
+				result.Passed = true
+			}
+		}
+		report.Passed = report.Passed && result.Passed
+		report.Results = append(report.Results, result)
+	}
+	report.Duration = time.Since(report.Started)
+	return report
+}
+
+// session carries state between checks, such as the book created by one
+// check and used by later ones
+type session struct {
+	ctx    context.Context
+	client *http.Client
+	base   string
+	result *Result
+	book   map[string]interface{}
+}
+
+// response is a response with its body read
+type response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// do sends a request to base+path, with v encoded as a JSON body unless it
+// is nil or a string (sent as is), and records the exchange
+func (s *session) do(method, path string, v interface{}) (*response, error) {
+	var body []byte
+	switch v := v.(type) {
+	case nil:
+	case string:
+		body = []byte(v)
+	default:
+		body, _ = json.Marshal(v)
+	}
+
+	ex := Exchange{Method: method, URL: s.base + path, RequestBody: string(body)}
+	resp, err := s.send(ex.URL, method, body)
+	if err != nil {
+		ex.Error = err.Error()
+		s.result.Exchanges = append(s.result.Exchanges, ex)
+		return nil, fmt.Errorf("%s %s failed: %v", method, path, err)
+	}
+	ex.Status = resp.Status
+	ex.ContentType = resp.Header.Get("Content-Type")
+	ex.Body = string(resp.Body)
+	if len(ex.Body) > 2000 {
+		ex.Body = ex.Body[:2000] + "…"
+	}
+	s.result.Exchanges = append(s.result.Exchanges, ex)
+	return resp, nil
+}
+
+func (s *session) send(u, method string, body []byte) (*response, error) {
+	ctx, cancel := context.WithTimeout(s.ctx, RequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	return &response{Status: resp.StatusCode, Header: resp.Header, Body: data}, nil
+}
+
+// expectStatus fails unless the response has one of the given status codes
+func expectStatus(resp *response, codes ...int) error {
+	for _, code := range codes {
+		if resp.Status == code {
+			return nil
+		}
+	}
+	want := make([]string, len(codes))
+	for i, code := range codes {
+		want[i] = fmt.Sprintf("%d %s", code, http.StatusText(code))
+	}
+	return fmt.Errorf("expected status %s, got %d %s", strings.Join(want, " or "), resp.Status, http.StatusText(resp.Status))
+}
+
+// expectJSON fails unless the response says it is JSON and is valid JSON,
+// which it decodes into v
+func expectJSON(resp *response, v interface{}) error {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" {
+		return fmt.Errorf("expected Content-Type application/json, got %q", resp.Header.Get("Content-Type"))
+	}
+	if err := json.Unmarshal(resp.Body, v); err != nil {
+		return fmt.Errorf("response body is not valid JSON: %v", err)
+	}
+	return nil
+}
+
+// expectBook fails unless v is a JSON object shaped like a book
+func expectBook(v interface{}) (map[string]interface{}, error) {
+	book, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a JSON object, got %s", jsonType(v))
+	}
+	id, ok := book["id"].(float64)
+	if !ok || id != float64(int64(id)) {
+		return nil, fmt.Errorf(`expected "id" to be a whole number, got %s`, jsonType(book["id"]))
+	}
+	for _, field := range []string{"title", "author"} {
+		if _, ok := book[field].(string); !ok {
+			return nil, fmt.Errorf("expected %q to be a string, got %s", field, jsonType(book[field]))
+		}
+	}
+	return book, nil
+}
+
+// jsonType names the JSON type of a decoded value for error messages
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null or nothing"
+	case bool:
+		return "a boolean"
+	case float64:
+		return "a number"
+	case string:
+		return "a string"
+	case []interface{}:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
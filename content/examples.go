@@ -1,6 +1,11 @@
 package content
 
-import "html/template"
+import (
+	"html/template"
+	"path"
+	"strings"
+	"time"
+)
 
 // CodeExample represents a downloadable code example
 type CodeExample struct {
@@ -8,15 +13,24 @@ type CodeExample struct {
 	Description template.HTML
 	Filename    string
 	Code        string
+	Published   time.Time
+	Updated     time.Time
+
+	// LivePath is where to start exploring the example when it runs live;
+	// empty if the example cannot run without files of its own
+	LivePath string
 }
 
-// GetCodeExamples returns all downloadable code examples
-func GetCodeExamples() []CodeExample {
+// builtinExamples returns the code examples written in this package
+func builtinExamples() []CodeExample {
 	return []CodeExample{
 		{
 			Title:       "Simple HTTP Server",
 			Description: template.HTML("A basic HTTP server that responds with 'Hello, World!'"),
 			Filename:    "simple_server.go",
+			LivePath:    "/",
+			Published:   date(2025, time.March, 3),
+			Updated:     date(2025, time.March, 3),
 			Code: `package main
 
 import (
@@ -43,6 +57,8 @@ func hello(w http.ResponseWriter, r *http.Request) {
 			Title:       "Static File Server",
 			Description: template.HTML("A web server that serves static files from a directory"),
 			Filename:    "static_server.go",
+			Published:   date(2025, time.March, 3),
+			Updated:     date(2025, time.March, 3),
 			Code: `package main
 
 import (
@@ -68,6 +84,9 @@ func main() {
 			Title:       "HTML Template Server",
 			Description: template.HTML("A server that renders HTML templates with dynamic data"),
 			Filename:    "template_server.go",
+			LivePath:    "/",
+			Published:   date(2025, time.March, 17),
+			Updated:     date(2025, time.March, 17),
 			Code: `package main
 
 import (
@@ -127,6 +146,9 @@ func templateHandler(w http.ResponseWriter, r *http.Request) {
 			Title:       "RESTful API Server",
 			Description: template.HTML("A simple RESTful API server for a book collection"),
 			Filename:    "rest_api.go",
+			LivePath:    "/api/books",
+			Published:   date(2025, time.March, 31),
+			Updated:     date(2025, time.April, 21),
 			Code: `package main
 
 import (
@@ -261,6 +283,8 @@ func bookHandler(w http.ResponseWriter, r *http.Request) {
 			Title:       "Complete Web Application",
 			Description: template.HTML("A more complete web application with routing, templates, and a mock database"),
 			Filename:    "complete_app.go",
+			Published:   date(2025, time.April, 7),
+			Updated:     date(2025, time.May, 12),
 			Code: `package main
 
 import (
@@ -646,6 +670,9 @@ func getUserIDFromCookie(r *http.Request) int {
 			Title:       "Middleware Example",
 			Description: template.HTML("Example of creating and using middleware in Go web servers"),
 			Filename:    "middleware.go",
+			LivePath:    "/public",
+			Published:   date(2025, time.April, 7),
+			Updated:     date(2025, time.April, 7),
 			Code: `package main
 
 import (
@@ -728,3 +755,35 @@ func publicHandler(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 }
+
+// Name returns the example's file name without its extension, such as rest_api
+func (e CodeExample) Name() string {
+	return strings.TrimSuffix(e.Filename, path.Ext(e.Filename))
+}
+
+// CanRunLive reports whether the example can be started as a live server.
+// Live examples run outside the sandbox with network access, so only code
+// written in this package can run; an example whose code was changed in the
+// authoring UI cannot.
+func (e CodeExample) CanRunLive() bool {
+	if e.LivePath == "" {
+		return false
+	}
+	loadBuiltin()
+	for _, b := range builtin.examples {
+		if b.Filename == e.Filename {
+			return b.LivePath != "" && b.Code == e.Code
+		}
+	}
+	return false
+}
+
+// FindExample returns the example with the given name
+func FindExample(name string) (CodeExample, bool) {
+	for _, example := range GetCodeExamples() {
+		if example.Name() == name {
+			return example, true
+		}
+	}
+	return CodeExample{}, false
+}
@@ -0,0 +1,26 @@
+package content
+
+import "html/template"
+
+// Exercise is a hands-on task graded by running hidden tests against the
+// learner's code
+type Exercise struct {
+	Instructions template.HTML
+
+	// Filename is the file the learner edits, starting from Starter
+	Filename string
+	Starter  string
+
+	// Solution is a reference answer; it is never sent to learners but lets
+	// tests check that the hidden tests can be passed
+	Solution string
+
+	// Tests are the hidden _test.go files run with go test
+	Tests []SourceFile
+}
+
+// SourceFile is a named Go source file
+type SourceFile struct {
+	Name   string
+	Source string
+}
@@ -0,0 +1,176 @@
+package content
+
+import "html/template"
+
+// helloWorldExercise asks for a handler that greets by name
+var helloWorldExercise = &Exercise{
+	Instructions: template.HTML(`
+		<p>Write a handler called <code>greet</code> that responds with <code>Hello, NAME!</code>, where
+		<code>NAME</code> comes from the <code>name</code> query parameter, for example
+		<code>/?name=Gopher</code>. When no name is given, greet the <code>World</code>.</p>
+	`),
+	Filename: "main.go",
+	Starter: `package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func main() {
+	http.HandleFunc("/", greet)
+	http.ListenAndServe("localhost:8080", nil)
+}
+
+func greet(w http.ResponseWriter, r *http.Request) {
+	// Read the name from r.URL.Query() and write the greeting
+	fmt.Fprint(w, "Hello!")
+}
+`,
+	Solution: `package main
+
+import (
+	"fmt"
+	"net/http"
+)
+
+func main() {
+	http.HandleFunc("/", greet)
+	http.ListenAndServe("localhost:8080", nil)
+}
+
+func greet(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		name = "World"
+	}
+	fmt.Fprintf(w, "Hello, %s!", name)
+}
+`,
+	Tests: []SourceFile{
+		{
+			Name: "main_test.go",
+			Source: `package main
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGreetName(t *testing.T) {
+	rr := httptest.NewRecorder()
+	greet(rr, httptest.NewRequest("GET", "/?name=Gopher", nil))
+	if got := rr.Body.String(); got != "Hello, Gopher!" {
+		t.Errorf("body = %q, want %q", got, "Hello, Gopher!")
+	}
+}
+
+func TestGreetDefault(t *testing.T) {
+	rr := httptest.NewRecorder()
+	greet(rr, httptest.NewRequest("GET", "/", nil))
+	if got := rr.Body.String(); got != "Hello, World!" {
+		t.Errorf("body = %q, want %q", got, "Hello, World!")
+	}
+}
+`,
+		},
+	},
+}
+
+// jsonAPIsExercise asks for a handler that encodes a struct as JSON
+var jsonAPIsExercise = &Exercise{
+	Instructions: template.HTML(`
+		<p>Finish <code>bookHandler</code> so it responds with the <code>book</code> variable encoded as JSON.
+		Add struct tags so the fields are named <code>id</code>, <code>title</code> and <code>author</code>,
+		and set the <code>Content-Type</code> header to <code>application/json</code>.</p>
+	`),
+	Filename: "main.go",
+	Starter: `package main
+
+import (
+	"net/http"
+)
+
+type Book struct {
+	ID     int
+	Title  string
+	Author string
+}
+
+var book = Book{ID: 1, Title: "The Go Programming Language", Author: "Alan Donovan"}
+
+func main() {
+	http.HandleFunc("/book", bookHandler)
+	http.ListenAndServe("localhost:8080", nil)
+}
+
+func bookHandler(w http.ResponseWriter, r *http.Request) {
+	// Set the Content-Type header and encode book as JSON
+}
+`,
+	Solution: `package main
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type Book struct {
+	ID     int    ` + "`json:\"id\"`" + `
+	Title  string ` + "`json:\"title\"`" + `
+	Author string ` + "`json:\"author\"`" + `
+}
+
+var book = Book{ID: 1, Title: "The Go Programming Language", Author: "Alan Donovan"}
+
+func main() {
+	http.HandleFunc("/book", bookHandler)
+	http.ListenAndServe("localhost:8080", nil)
+}
+
+func bookHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(book)
+}
+`,
+	Tests: []SourceFile{
+		{
+			Name: "main_test.go",
+			Source: `package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContentType(t *testing.T) {
+	rr := httptest.NewRecorder()
+	bookHandler(rr, httptest.NewRequest("GET", "/book", nil))
+	if got := rr.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+		t.Errorf("Content-Type = %q, want application/json", got)
+	}
+}
+
+func TestJSONFields(t *testing.T) {
+	rr := httptest.NewRecorder()
+	bookHandler(rr, httptest.NewRequest("GET", "/book", nil))
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("response is not JSON: %v", err)
+	}
+	for _, key := range []string{"id", "title", "author"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing %q field in %s", key, rr.Body.String())
+		}
+	}
+	if got["title"] != book.Title {
+		t.Errorf("title = %v, want %q", got["title"], book.Title)
+	}
+}
+`,
+		},
+	},
+}
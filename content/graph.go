@@ -0,0 +1,196 @@
+package content
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Graph is the prerequisite graph between tutorials. It is a DAG: NewGraph
+// rejects unknown prerequisites and cycles.
+type Graph struct {
+	tutorials map[string]Tutorial
+	levels    map[string]Level
+	position  map[string]int // index in level order, used to break ties
+	order     []string       // topological order
+	depth     map[string]int
+}
+
+// NewGraph builds the prerequisite graph of the tutorials in levels
+func NewGraph(levels []Level) (*Graph, error) {
+	g := &Graph{
+		tutorials: make(map[string]Tutorial),
+		levels:    make(map[string]Level),
+		position:  make(map[string]int),
+		depth:     make(map[string]int),
+	}
+	var ids []string
+	for _, level := range levels {
+		for _, t := range level.Tutorials {
+			if _, dup := g.tutorials[t.ID]; dup {
+				return nil, fmt.Errorf("content: duplicate tutorial ID %q", t.ID)
+			}
+			g.tutorials[t.ID] = t
+			g.levels[t.ID] = level
+			g.position[t.ID] = len(ids)
+			ids = append(ids, t.ID)
+		}
+	}
+
+	// Count unmet prerequisites, rejecting ones that do not exist
+	pending := make(map[string]int)
+	dependents := make(map[string][]string)
+	for _, id := range ids {
+		seen := make(map[string]bool)
+		for _, p := range g.tutorials[id].Prerequisites {
+			if _, ok := g.tutorials[p]; !ok {
+				return nil, fmt.Errorf("content: tutorial %q has unknown prerequisite %q", id, p)
+			}
+			if seen[p] {
+				return nil, fmt.Errorf("content: tutorial %q lists prerequisite %q twice", id, p)
+			}
+			seen[p] = true
+			pending[id]++
+			dependents[p] = append(dependents[p], id)
+		}
+	}
+
+	// Kahn's algorithm, always taking the earliest tutorial in level order
+	var ready []string
+	for _, id := range ids {
+		if pending[id] == 0 {
+			ready = append(ready, id)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool { return g.position[ready[i]] < g.position[ready[j]] })
+		id := ready[0]
+		ready = ready[1:]
+		g.order = append(g.order, id)
+		for _, d := range dependents[id] {
+			if g.depth[id]+1 > g.depth[d] {
+				g.depth[d] = g.depth[id] + 1
+			}
+			if pending[d]--; pending[d] == 0 {
+				ready = append(ready, d)
+			}
+		}
+	}
+	if len(g.order) < len(ids) {
+		return nil, fmt.Errorf("content: prerequisite cycle: %s", g.findCycle(pending))
+	}
+	return g, nil
+}
+
+// findCycle describes a cycle among the tutorials left with unmet
+// prerequisites, such as "a -> b -> a"
+func (g *Graph) findCycle(pending map[string]int) string {
+	var start string
+	for id, n := range pending {
+		if n > 0 && (start == "" || g.position[id] < g.position[start]) {
+			start = id
+		}
+	}
+	// Every tutorial left has a prerequisite that is also left, so walking
+	// prerequisites must come back to one already visited
+	visited := make(map[string]int)
+	var path []string
+	for id := start; ; {
+		if i, ok := visited[id]; ok {
+			return strings.Join(append(path[i:], id), " -> ")
+		}
+		visited[id] = len(path)
+		path = append(path, id)
+		for _, p := range g.tutorials[id].Prerequisites {
+			if pending[p] > 0 {
+				id = p
+				break
+			}
+		}
+	}
+}
+
+// LoadGraph builds the graph of every tutorial and checks the learning paths
+// against it
+func LoadGraph() (*Graph, error) {
+	g, err := NewGraph(GetLevels())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range GetPaths() {
+		if _, err := g.Steps(p); err != nil {
+			return nil, err
+		}
+	}
+	return g, nil
+}
+
+// Tutorials returns every tutorial in an order that respects prerequisites,
+// keeping level order where it can
+func (g *Graph) Tutorials() []Tutorial {
+	list := make([]Tutorial, len(g.order))
+	for i, id := range g.order {
+		list[i] = g.tutorials[id]
+	}
+	return list
+}
+
+// Tutorial returns the tutorial with the given ID and its level
+func (g *Graph) Tutorial(id string) (Tutorial, Level, bool) {
+	t, ok := g.tutorials[id]
+	return t, g.levels[id], ok
+}
+
+// Depth returns the length of the longest prerequisite chain leading to a
+// tutorial; tutorials without prerequisites have depth 0
+func (g *Graph) Depth(id string) int {
+	return g.depth[id]
+}
+
+// Closure returns the given tutorials and everything they depend on, in
+// graph order
+func (g *Graph) Closure(ids ...string) ([]Tutorial, error) {
+	want := make(map[string]bool)
+	var visit func(id string) error
+	visit = func(id string) error {
+		t, ok := g.tutorials[id]
+		if !ok {
+			return fmt.Errorf("content: unknown tutorial %q", id)
+		}
+		if want[id] {
+			return nil
+		}
+		want[id] = true
+		for _, p := range t.Prerequisites {
+			if err := visit(p); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, id := range ids {
+		if err := visit(id); err != nil {
+			return nil, err
+		}
+	}
+
+	var list []Tutorial
+	for _, id := range g.order {
+		if want[id] {
+			list = append(list, g.tutorials[id])
+		}
+	}
+	return list, nil
+}
+
+// Missing returns the prerequisites of a tutorial that are not completed.
+// A tutorial is unlocked when nothing is missing.
+func (g *Graph) Missing(id string, completed func(id string) bool) []Tutorial {
+	var missing []Tutorial
+	for _, p := range g.tutorials[id].Prerequisites {
+		if !completed(p) {
+			missing = append(missing, g.tutorials[p])
+		}
+	}
+	return missing
+}
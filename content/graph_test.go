@@ -0,0 +1,96 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+// ids returns the IDs of tutorials joined with commas
+func ids(tutorials []Tutorial) string {
+	var list []string
+	for _, t := range tutorials {
+		list = append(list, t.ID)
+	}
+	return strings.Join(list, ",")
+}
+
+func TestLoadGraph(t *testing.T) {
+	g, err := LoadGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(g.Tutorials()); got != 6 {
+		t.Errorf("graph has %d tutorials", got)
+	}
+	for _, p := range GetPaths() {
+		steps, _ := g.Steps(p)
+		if len(steps) == 0 {
+			t.Errorf("path %s has no steps", p.ID)
+		}
+	}
+
+	steps, _ := g.Steps(GetPaths()[0])
+	if got := ids(steps); got != "hello-world,handling-routes,json-apis,rest-basics" {
+		t.Errorf("REST path = %s", got)
+	}
+}
+
+func TestNewGraph(t *testing.T) {
+	tut := func(id string, prereqs ...string) Tutorial {
+		return Tutorial{ID: id, Prerequisites: prereqs}
+	}
+	levels := func(tutorials ...Tutorial) []Level {
+		return []Level{{ID: "one", Tutorials: tutorials}}
+	}
+
+	g, err := NewGraph(levels(tut("d", "b", "c"), tut("c", "a"), tut("b", "a"), tut("a"), tut("e")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ids(g.Tutorials()); got != "a,c,b,d,e" {
+		t.Errorf("order = %s", got)
+	}
+	if g.Depth("a") != 0 || g.Depth("d") != 2 {
+		t.Errorf("depths: a=%d d=%d", g.Depth("a"), g.Depth("d"))
+	}
+	closure, _ := g.Closure("b")
+	if got := ids(closure); got != "a,b" {
+		t.Errorf("Closure(b) = %s", got)
+	}
+	done := map[string]bool{"a": true, "b": true}
+	if got := ids(g.Missing("d", func(id string) bool { return done[id] })); got != "c" {
+		t.Errorf("Missing(d) = %s", got)
+	}
+
+	for name, tt := range map[string]struct {
+		levels []Level
+		want   string
+	}{
+		"unknown":   {levels(tut("a", "zzz")), `unknown prerequisite "zzz"`},
+		"duplicate": {levels(tut("a"), tut("a")), `duplicate tutorial ID "a"`},
+		"twice":     {levels(tut("a"), tut("b", "a", "a")), `prerequisite "a" twice`},
+		"self":      {levels(tut("a", "a")), "cycle: a -> a"},
+		"cycle":     {levels(tut("x"), tut("a", "c"), tut("b", "a"), tut("c", "b", "x")), "cycle: a -> c -> b -> a"},
+	} {
+		if _, err := NewGraph(tt.levels); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got %v, want %q", name, err, tt.want)
+		}
+	}
+}
+
+func TestSteps(t *testing.T) {
+	g, err := NewGraph(GetLevels())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, p := range map[string]Path{
+		"unknown goal":    {ID: "p", Goals: []string{"nope"}},
+		"unknown topic":   {ID: "p", Topics: []string{"nope"}},
+		"unknown example": {ID: "p", Goals: []string{"hello-world"}, Example: "nope"},
+		"empty":           {ID: "p"},
+	} {
+		if _, err := g.Steps(p); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package content
+
+import "time"
+
+// Level groups the tutorials that are taught together on one section page
+type Level struct {
+	ID         string
+	Title      string
+	Path       string
+	Difficulty string
+	Tutorials  []Tutorial
+}
+
+// builtinLevels returns the levels with the tutorials written in this package
+func builtinLevels() []Level {
+	return []Level{
+		{
+			ID:         "basic",
+			Title:      "Basic Web Server Concepts",
+			Path:       "/basic",
+			Difficulty: "Beginner",
+			Tutorials:  GetBasicTutorials(),
+		},
+		{
+			ID:         "intermediate",
+			Title:      "Intermediate Web Server Concepts",
+			Path:       "/intermediate",
+			Difficulty: "Intermediate",
+			Tutorials:  GetIntermediateTutorials(),
+		},
+		{
+			ID:         "advanced",
+			Title:      "Advanced Web Server Concepts",
+			Path:       "/advanced",
+			Difficulty: "Advanced",
+			Tutorials:  GetAdvancedTutorials(),
+		},
+		{
+			ID:         "restful",
+			Title:      "RESTful API Development",
+			Path:       "/restful",
+			Difficulty: "Advanced",
+			Tutorials:  GetRestfulTutorials(),
+		},
+	}
+}
+
+// Updated returns the most recent update time of any tutorial in the level
+func (l Level) Updated() time.Time {
+	var latest time.Time
+	for _, tutorial := range l.Tutorials {
+		if tutorial.Updated.After(latest) {
+			latest = tutorial.Updated
+		}
+	}
+	return latest
+}
+
+// LastUpdated returns the most recent update time across all tutorials and examples
+func LastUpdated() time.Time {
+	var latest time.Time
+	for _, level := range GetLevels() {
+		if updated := level.Updated(); updated.After(latest) {
+			latest = updated
+		}
+	}
+	for _, example := range GetCodeExamples() {
+		if example.Updated.After(latest) {
+			latest = example.Updated
+		}
+	}
+	return latest
+}
+
+// FindTutorial returns the tutorial with the given ID and the level it belongs to
+func FindTutorial(id string) (Tutorial, Level, bool) {
+	for _, level := range GetLevels() {
+		for _, tutorial := range level.Tutorials {
+			if tutorial.ID == id {
+				return tutorial, level, true
+			}
+		}
+	}
+	return Tutorial{}, Level{}, false
+}
+
+// FindLevel returns the level with the given ID, including published edits
+func FindLevel(id string) (Level, bool) {
+	for _, level := range GetLevels() {
+		if level.ID == id {
+			return level, true
+		}
+	}
+	return Level{}, false
+}
@@ -0,0 +1,90 @@
+package content
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// Path is a learning path towards a goal. Its steps are generated from the
+// prerequisite graph: the goal tutorials, every tutorial on one of its
+// topics, and everything those depend on.
+type Path struct {
+	ID          string
+	Title       string
+	Description template.HTML
+	Goals       []string // tutorial IDs
+	Topics      []string
+
+	// Example names a code example to try once the path is done
+	Example string
+}
+
+// GetPaths returns the learning paths
+func GetPaths() []Path {
+	return []Path{
+		{
+			ID:          "rest-api-developer",
+			Title:       "REST API Developer",
+			Description: template.HTML("From a first handler to a JSON API that follows REST conventions, then practise against your own copy of the books API."),
+			Goals:       []string{"rest-basics"},
+			Example:     "rest_api",
+		},
+		{
+			ID:          "server-rendered-pages",
+			Title:       "Server-Rendered Pages",
+			Description: template.HTML("Serve HTML from Go and build pages from templates and data."),
+			Topics:      []string{"html"},
+			Example:     "template_server",
+		},
+		{
+			ID:          "middleware-deep-dive",
+			Title:       "Middleware Deep Dive",
+			Description: template.HTML("Learn how handlers and routing fit together, then see how middleware wraps handlers to add logging and authentication."),
+			Topics:      []string{"handlers", "routing"},
+			Example:     "middleware",
+		},
+	}
+}
+
+// FindPath returns the learning path with the given ID
+func FindPath(id string) (Path, bool) {
+	for _, p := range GetPaths() {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Path{}, false
+}
+
+// Steps returns the tutorials on a learning path in the order to take them
+func (g *Graph) Steps(p Path) ([]Tutorial, error) {
+	ids := append([]string(nil), p.Goals...)
+	for _, topic := range p.Topics {
+		found := false
+		for _, id := range g.order {
+			for _, t := range g.tutorials[id].Topics {
+				if t == topic {
+					ids = append(ids, id)
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("content: path %q: no tutorial covers topic %q", p.ID, topic)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("content: path %q has no goals or topics", p.ID)
+	}
+	if p.Example != "" {
+		if _, ok := FindExample(p.Example); !ok {
+			return nil, fmt.Errorf("content: path %q: unknown example %q", p.ID, p.Example)
+		}
+	}
+
+	steps, err := g.Closure(ids...)
+	if err != nil {
+		return nil, fmt.Errorf("content: path %q: %v", p.ID, err)
+	}
+	return steps, nil
+}
@@ -0,0 +1,217 @@
+package content
+
+import (
+	"fmt"
+	"sync"
+)
+
+// PublishedTutorial is a tutorial edited in the authoring UI and the ID of the
+// level it is shown on
+type PublishedTutorial struct {
+	Level    string
+	Tutorial Tutorial
+}
+
+// Published is content edited in the authoring UI. It replaces the built-in
+// tutorials and examples with the same ID or filename and adds new ones.
+// Quizzes and exercises are not edited there, so replaced tutorials keep them.
+type Published struct {
+	Tutorials []PublishedTutorial
+	Examples  []CodeExample
+}
+
+var (
+	publishedMu sync.RWMutex
+	published   Published
+)
+
+// SetPublished changes the published content served in place of the built-in
+// content, sanitized with ContentPolicy, and returns what was stripped from
+// it. Check it first: content that fails the check breaks the map.
+func SetPublished(p Published) []Stripped {
+	p, stripped := p.sanitize(ContentPolicy)
+	publishedMu.Lock()
+	defer publishedMu.Unlock()
+	published = p
+	return stripped
+}
+
+// builtin is the content written in this package, sanitized the first time
+// it is used
+var builtin struct {
+	once     sync.Once
+	levels   []Level
+	examples []CodeExample
+	stripped []Stripped
+}
+
+// loadBuiltin sanitizes the built-in tutorials, translations and examples
+func loadBuiltin() {
+	builtin.once.Do(func() {
+		var stripped []Stripped
+		levels := builtinLevels()
+		for i := range levels {
+			for j, t := range levels[i].Tutorials {
+				var s []Stripped
+				levels[i].Tutorials[j], s = ContentPolicy.SanitizeTutorial(t)
+				stripped = append(stripped, s...)
+			}
+		}
+		examples := builtinExamples()
+		for i, e := range examples {
+			var s []Stripped
+			examples[i], s = ContentPolicy.SanitizeExample(e)
+			stripped = append(stripped, s...)
+		}
+		for tag, byID := range translations {
+			for id, tr := range byID {
+				item := "translation " + tag + "/" + id
+				tr.Description = ContentPolicy.field(tr.Description, item, "Description", &stripped)
+				tr.Explanation = ContentPolicy.field(tr.Explanation, item, "Explanation", &stripped)
+				byID[id] = tr
+			}
+		}
+		builtin.levels, builtin.examples, builtin.stripped = levels, examples, stripped
+	})
+}
+
+// BuiltinStripped returns what was stripped from the built-in content when
+// it was sanitized
+func BuiltinStripped() []Stripped {
+	loadBuiltin()
+	return append([]Stripped(nil), builtin.stripped...)
+}
+
+// currentPublished returns the content set by SetPublished
+func currentPublished() Published {
+	publishedMu.RLock()
+	defer publishedMu.RUnlock()
+	return published
+}
+
+// GetLevels returns every tutorial level in the order it should be studied
+func GetLevels() []Level {
+	return currentPublished().Levels()
+}
+
+// GetCodeExamples returns all downloadable code examples
+func GetCodeExamples() []CodeExample {
+	return currentPublished().CodeExamples()
+}
+
+// Levels returns the built-in levels with the published tutorials applied.
+// A tutorial published to a different level moves to the end of that level.
+func (p Published) Levels() []Level {
+	loadBuiltin()
+	return p.levels(builtin.levels)
+}
+
+// SourceLevels is like Levels but returns the built-in tutorials as they are
+// written, before ContentPolicy is applied, for tools such as cmd/lint that
+// check the source rather than what is served
+func (p Published) SourceLevels() []Level {
+	return p.levels(builtinLevels())
+}
+
+// levels applies the published tutorials to a copy of base
+func (p Published) levels(base []Level) []Level {
+	levels := make([]Level, len(base))
+	for i, level := range base {
+		level.Tutorials = append([]Tutorial(nil), level.Tutorials...)
+		levels[i] = level
+	}
+	for _, pt := range p.Tutorials {
+		t := pt.Tutorial
+		placed := false
+		for i := range levels {
+			for j, old := range levels[i].Tutorials {
+				if old.ID != t.ID {
+					continue
+				}
+				t.Quiz, t.Exercise, t.Published = old.Quiz, old.Exercise, old.Published
+				if levels[i].ID == pt.Level {
+					levels[i].Tutorials[j] = t
+					placed = true
+				} else {
+					levels[i].Tutorials = append(levels[i].Tutorials[:j:j], levels[i].Tutorials[j+1:]...)
+				}
+				break
+			}
+		}
+		if placed {
+			continue
+		}
+		for i := range levels {
+			if levels[i].ID == pt.Level {
+				levels[i].Tutorials = append(levels[i].Tutorials, t)
+			}
+		}
+	}
+	return levels
+}
+
+// CodeExamples returns the built-in examples with the published examples applied
+func (p Published) CodeExamples() []CodeExample {
+	loadBuiltin()
+	return p.codeExamples(builtin.examples)
+}
+
+// SourceCodeExamples is like CodeExamples but returns the built-in examples
+// as they are written, before ContentPolicy is applied
+func (p Published) SourceCodeExamples() []CodeExample {
+	return p.codeExamples(builtinExamples())
+}
+
+// codeExamples applies the published examples to a copy of base
+func (p Published) codeExamples(base []CodeExample) []CodeExample {
+	examples := append([]CodeExample(nil), base...)
+	for _, e := range p.Examples {
+		replaced := false
+		for i, old := range examples {
+			if old.Filename == e.Filename {
+				e.Published = old.Published
+				examples[i] = e
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			examples = append(examples, e)
+		}
+	}
+	return examples
+}
+
+// Check reports whether the content would still form a valid site: every
+// tutorial is on a known level, the prerequisites form no cycles and the
+// learning paths can still be built
+func (p Published) Check() error {
+	levels := p.Levels()
+	known := make(map[string]bool)
+	for _, level := range levels {
+		known[level.ID] = true
+	}
+	for _, pt := range p.Tutorials {
+		if !known[pt.Level] {
+			return fmt.Errorf("content: tutorial %q is on unknown level %q", pt.Tutorial.ID, pt.Level)
+		}
+	}
+
+	g, err := NewGraph(levels)
+	if err != nil {
+		return err
+	}
+	examples := make(map[string]bool)
+	for _, e := range p.CodeExamples() {
+		examples[e.Name()] = true
+	}
+	for _, path := range GetPaths() {
+		if path.Example != "" && !examples[path.Example] {
+			return fmt.Errorf("content: path %q: unknown example %q", path.ID, path.Example)
+		}
+		if _, err := g.Steps(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
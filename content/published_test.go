@@ -0,0 +1,71 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPublished(t *testing.T) {
+	hello := GetBasicTutorials()[0]
+	hello.Title = "Hello Again"
+	hello.Quiz = nil
+
+	routes := GetBasicTutorials()[2]
+	routes.Title = "Routing, Moved"
+
+	p := Published{
+		Tutorials: []PublishedTutorial{
+			{Level: "basic", Tutorial: hello},
+			{Level: "intermediate", Tutorial: routes},
+			{Level: "advanced", Tutorial: Tutorial{ID: "new-one", Title: "New", Prerequisites: []string{"hello-world"}}},
+		},
+		Examples: []CodeExample{
+			{Title: "Simple, Edited", Filename: "simple_server.go"},
+			{Title: "Brand New", Filename: "brand_new.go"},
+		},
+	}
+	if err := p.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	levels := p.Levels()
+	if got := ids(levels[0].Tutorials); got != "hello-world,serve-html" {
+		t.Errorf("basic = %s", got)
+	}
+	if levels[0].Tutorials[0].Title != "Hello Again" || levels[0].Tutorials[0].Quiz == nil {
+		t.Errorf("replaced tutorial = %q, quiz %v", levels[0].Tutorials[0].Title, levels[0].Tutorials[0].Quiz)
+	}
+	if got := ids(levels[1].Tutorials); got != "html-templates,handling-routes" {
+		t.Errorf("intermediate = %s", got)
+	}
+	if got := ids(levels[2].Tutorials); !strings.HasSuffix(got, ",new-one") {
+		t.Errorf("advanced = %s", got)
+	}
+
+	examples := p.CodeExamples()
+	if examples[0].Title != "Simple, Edited" || examples[0].Published.IsZero() || examples[len(examples)-1].Filename != "brand_new.go" {
+		t.Errorf("examples = %+v", examples)
+	}
+	// Only the built-in code runs live
+	simple := builtinExamples()[0]
+	if !simple.CanRunLive() {
+		t.Error("built-in example cannot run live")
+	}
+	simple.Code += "\nfunc init() {}\n"
+	if simple.CanRunLive() || examples[len(examples)-1].CanRunLive() {
+		t.Error("edited or new example can run live")
+	}
+	if len(GetLevels()[0].Tutorials) != len(GetBasicTutorials()) || GetLevels()[0].Tutorials[0].Title == "Hello Again" {
+		t.Error("Levels changed the current content")
+	}
+
+	for name, bad := range map[string]Published{
+		"level":  {Tutorials: []PublishedTutorial{{Level: "expert", Tutorial: Tutorial{ID: "x"}}}},
+		"prereq": {Tutorials: []PublishedTutorial{{Level: "basic", Tutorial: Tutorial{ID: "x", Prerequisites: []string{"nope"}}}}},
+		"cycle":  {Tutorials: []PublishedTutorial{{Level: "basic", Tutorial: Tutorial{ID: "hello-world", Prerequisites: []string{"rest-basics"}}}}},
+	} {
+		if err := bad.Check(); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package content
+
+import (
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Kinds of quiz question
+const (
+	// MultipleChoice questions have exactly one correct choice
+	MultipleChoice = "choice"
+
+	// MultiSelect questions are correct when every correct choice, and no
+	// other, is selected
+	MultiSelect = "multi"
+
+	// ShortAnswer questions accept free text matching one of the Accept patterns
+	ShortAnswer = "short"
+)
+
+// Quiz checks understanding of a tutorial after the explanation
+type Quiz struct {
+	Questions []Question
+}
+
+// Question is one quiz question. Form fields are named after the question ID.
+type Question struct {
+	ID     string
+	Kind   string
+	Prompt template.HTML
+
+	// Choices are offered for MultipleChoice and MultiSelect questions
+	Choices []Choice
+
+	// Accept lists regular expressions for ShortAnswer questions. Each must
+	// match the whole trimmed answer and is case-insensitive.
+	Accept []string
+
+	// Explanation is shown with the result whether or not the answer was right
+	Explanation template.HTML
+}
+
+// Choice is one option of a choice question
+type Choice struct {
+	ID       string
+	Text     template.HTML
+	Correct  bool
+	Feedback template.HTML
+}
+
+// QuizResult is a graded quiz submission
+type QuizResult struct {
+	Score   int
+	Total   int
+	Answers []AnswerResult
+}
+
+// AnswerResult is the grading of one question
+type AnswerResult struct {
+	Question Question
+	Given    []string
+	Correct  bool
+
+	// Feedback holds the notes for each selected choice, in choice order
+	Feedback []template.HTML
+}
+
+// Selected reports whether the learner picked the choice with the given ID
+func (a AnswerResult) Selected(id string) bool {
+	for _, given := range a.Given {
+		if given == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Grade scores answers, keyed by question ID, one point per correct question
+func (q *Quiz) Grade(answers map[string][]string) QuizResult {
+	result := QuizResult{Total: len(q.Questions)}
+	for _, question := range q.Questions {
+		answer := question.grade(answers[question.ID])
+		if answer.Correct {
+			result.Score++
+		}
+		result.Answers = append(result.Answers, answer)
+	}
+	return result
+}
+
+func (q Question) grade(given []string) AnswerResult {
+	answer := AnswerResult{Question: q, Given: given}
+
+	if q.Kind == ShortAnswer {
+		if len(given) > 0 {
+			text := strings.TrimSpace(given[0])
+			answer.Given = []string{text}
+			for _, pattern := range q.Accept {
+				if re, err := compileAnswer(pattern); err == nil && re.MatchString(text) {
+					answer.Correct = true
+					break
+				}
+			}
+		}
+		return answer
+	}
+
+	// Choice questions: every correct choice selected and nothing else
+	answer.Correct = len(given) > 0
+	for _, choice := range q.Choices {
+		selected := answer.Selected(choice.ID)
+		if selected != choice.Correct {
+			answer.Correct = false
+		}
+		if selected && choice.Feedback != "" {
+			answer.Feedback = append(answer.Feedback, choice.Feedback)
+		}
+	}
+	if q.Kind == MultipleChoice && len(given) != 1 {
+		answer.Correct = false
+	}
+	return answer
+}
+
+// compileAnswer compiles a ShortAnswer pattern so it must match the whole answer
+func compileAnswer(pattern string) (*regexp.Regexp, error) {
+	return regexp.Compile(`(?i)^(?:` + pattern + `)$`)
+}
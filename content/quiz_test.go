@@ -0,0 +1,91 @@
+package content
+
+import "testing"
+
+// TestQuizzes checks every quiz in the content can be answered correctly
+func TestQuizzes(t *testing.T) {
+	for _, level := range GetLevels() {
+		for _, tutorial := range level.Tutorials {
+			if tutorial.Quiz == nil {
+				continue
+			}
+			ids := make(map[string]bool)
+			for _, q := range tutorial.Quiz.Questions {
+				name := tutorial.ID + "/" + q.ID
+				if q.ID == "" || ids[q.ID] {
+					t.Errorf("%s: missing or duplicate question ID", name)
+				}
+				ids[q.ID] = true
+
+				correct := 0
+				for _, choice := range q.Choices {
+					if choice.Correct {
+						correct++
+					}
+				}
+				switch q.Kind {
+				case MultipleChoice:
+					if len(q.Choices) < 2 || correct != 1 {
+						t.Errorf("%s: multiple choice needs 2+ choices and exactly one correct", name)
+					}
+				case MultiSelect:
+					if len(q.Choices) < 2 || correct == 0 {
+						t.Errorf("%s: multi-select needs 2+ choices and at least one correct", name)
+					}
+				case ShortAnswer:
+					if len(q.Accept) == 0 {
+						t.Errorf("%s: short answer has no accepted patterns", name)
+					}
+					for _, pattern := range q.Accept {
+						if _, err := compileAnswer(pattern); err != nil {
+							t.Errorf("%s: %v", name, err)
+						}
+					}
+				default:
+					t.Errorf("%s: unknown kind %q", name, q.Kind)
+				}
+			}
+		}
+	}
+}
+
+func TestGrade(t *testing.T) {
+	quiz := &Quiz{Questions: []Question{
+		{ID: "one", Kind: MultipleChoice, Choices: []Choice{
+			{ID: "a", Correct: true}, {ID: "b", Feedback: "b is wrong"},
+		}},
+		{ID: "many", Kind: MultiSelect, Choices: []Choice{
+			{ID: "a", Correct: true}, {ID: "b", Correct: true}, {ID: "c"},
+		}},
+		{ID: "text", Kind: ShortAnswer, Accept: []string{`(http\.)?NotFound`}},
+	}}
+
+	tests := []struct {
+		answers map[string][]string
+		want    []bool
+	}{
+		{map[string][]string{"one": {"a"}, "many": {"a", "b"}, "text": {"  http.notfound "}}, []bool{true, true, true}},
+		{map[string][]string{"one": {"a", "b"}, "many": {"a"}, "text": {"NotFoundHandler"}}, []bool{false, false, false}},
+		{map[string][]string{"many": {"a", "b", "c"}}, []bool{false, false, false}},
+	}
+	for i, tt := range tests {
+		result := quiz.Grade(tt.answers)
+		score := 0
+		for j, answer := range result.Answers {
+			if answer.Correct != tt.want[j] {
+				t.Errorf("case %d, question %s: correct = %v, want %v", i, answer.Question.ID, answer.Correct, tt.want[j])
+			}
+			if tt.want[j] {
+				score++
+			}
+		}
+		if result.Score != score || result.Total != 3 {
+			t.Errorf("case %d: score %d/%d, want %d/3", i, result.Score, result.Total, score)
+		}
+	}
+
+	result := quiz.Grade(map[string][]string{"one": {"b"}})
+	if fb := result.Answers[0].Feedback; len(fb) != 1 || fb[0] != "b is wrong" {
+		t.Errorf("feedback = %v", fb)
+	}
+}
@@ -0,0 +1,105 @@
+package content
+
+import "html/template"
+
+// helloWorldQuiz checks the basics of registering handlers and starting a server
+var helloWorldQuiz = &Quiz{
+	Questions: []Question{
+		{
+			ID:     "handlefunc",
+			Kind:   MultipleChoice,
+			Prompt: template.HTML(`What does <code>http.HandleFunc("/", hello)</code> do?`),
+			Choices: []Choice{
+				{ID: "a", Text: "Calls <code>hello</code> immediately to build the home page", Feedback: "Nothing is called at registration time; <code>hello</code> runs once per matching request."},
+				{ID: "b", Text: "Registers <code>hello</code> on the default ServeMux for the <code>/</code> pattern", Correct: true},
+				{ID: "c", Text: "Starts a server that only answers requests for exactly <code>/</code>", Feedback: "Registering a handler does not start a server, and <code>/</code> matches every path that has no more specific pattern."},
+			},
+			Explanation: "The handler is stored on <code>http.DefaultServeMux</code>, which is used because <code>ListenAndServe</code> is given a nil handler.",
+		},
+		{
+			ID:     "signature",
+			Kind:   MultiSelect,
+			Prompt: "Which parameters does a function need to be used with <code>http.HandleFunc</code>? Select all that apply.",
+			Choices: []Choice{
+				{ID: "writer", Text: "<code>http.ResponseWriter</code>", Correct: true},
+				{ID: "request", Text: "<code>*http.Request</code>", Correct: true},
+				{ID: "context", Text: "<code>context.Context</code>", Feedback: "The context is available from <code>r.Context()</code> rather than as a parameter."},
+				{ID: "error", Text: "An <code>error</code> return value", Feedback: "Handlers report failures by writing a response, not by returning an error."},
+			},
+		},
+		{
+			ID:          "listen",
+			Kind:        ShortAnswer,
+			Prompt:      "Which function from the <code>net/http</code> package starts the server and blocks while it runs?",
+			Accept:      []string{`(http\.)?ListenAndServe(\(\))?`},
+			Explanation: "<code>http.ListenAndServe</code> only returns when the server fails, which is why its error is usually passed to <code>log.Fatal</code>.",
+		},
+	},
+}
+
+// handlingRoutesQuiz checks how ServeMux patterns are matched
+var handlingRoutesQuiz = &Quiz{
+	Questions: []Question{
+		{
+			ID:     "fallback",
+			Kind:   MultipleChoice,
+			Prompt: "With the handlers from this tutorial, which handler receives a request for <code>/missing</code>?",
+			Choices: []Choice{
+				{ID: "home", Text: "<code>homeHandler</code>", Correct: true, Feedback: "Right, and it then replies with <code>http.NotFound</code> because the path is not exactly <code>/</code>."},
+				{ID: "none", Text: "None; the ServeMux replies with 404 itself", Feedback: "The <code>/</code> pattern ends in a slash, so it matches every path without a more specific pattern."},
+				{ID: "about", Text: "<code>aboutHandler</code>"},
+			},
+		},
+		{
+			ID:     "subtree",
+			Kind:   MultiSelect,
+			Prompt: "Which of these requests are routed to <code>aboutHandler</code>? Select all that apply.",
+			Choices: []Choice{
+				{ID: "exact", Text: "<code>/about</code>", Correct: true},
+				{ID: "query", Text: "<code>/about?team=go</code>", Correct: true, Feedback: "The query string is not part of the path used for matching."},
+				{ID: "child", Text: "<code>/about/team</code>", Feedback: "<code>/about</code> has no trailing slash, so it only matches that exact path."},
+			},
+		},
+		{
+			ID:          "notfound",
+			Kind:        ShortAnswer,
+			Prompt:      "Which <code>net/http</code> function replies with a 404 Not Found error?",
+			Accept:      []string{`(http\.)?NotFound(\(.*\))?`},
+			Explanation: "<code>http.NotFound(w, r)</code> writes a 404 status and a short plain text body.",
+		},
+	},
+}
+
+// jsonAPIsQuiz checks encoding and decoding JSON in handlers
+var jsonAPIsQuiz = &Quiz{
+	Questions: []Question{
+		{
+			ID:     "header",
+			Kind:   MultipleChoice,
+			Prompt: "Why set the <code>Content-Type</code> header before writing a JSON response?",
+			Choices: []Choice{
+				{ID: "required", Text: "Go refuses to write JSON without it", Feedback: "Go will write any bytes; without the header it guesses the type by sniffing the body."},
+				{ID: "clients", Text: "So clients know to parse the body as JSON", Correct: true},
+				{ID: "speed", Text: "It makes <code>json.NewEncoder</code> faster"},
+			},
+			Explanation: "Headers must be set before the first call to <code>Write</code> or <code>WriteHeader</code>, because they are sent with the status line.",
+		},
+		{
+			ID:     "tags",
+			Kind:   MultiSelect,
+			Prompt: "What can a struct tag such as <code>`json:\"username\"`</code> control? Select all that apply.",
+			Choices: []Choice{
+				{ID: "name", Text: "The key used for the field in JSON", Correct: true},
+				{ID: "omit", Text: "Leaving out empty values with <code>omitempty</code>", Correct: true, Feedback: "Right: <code>`json:\"email,omitempty\"`</code> skips the field when it is empty."},
+				{ID: "private", Text: "Encoding unexported (lowercase) fields", Feedback: "Unexported fields are never encoded, whatever their tags say."},
+			},
+		},
+		{
+			ID:          "encode",
+			Kind:        ShortAnswer,
+			Prompt:      "Which function from <code>encoding/json</code> creates an encoder that writes to the <code>http.ResponseWriter</code>?",
+			Accept:      []string{`(json\.)?NewEncoder(\(.*\))?`},
+			Explanation: "<code>json.NewEncoder(w).Encode(v)</code> writes JSON straight to the response without building the whole document in a string first.",
+		},
+	},
+}
@@ -0,0 +1,261 @@
+package content
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+
+	"golang-webserver-tutorial/htmltag"
+)
+
+// Policy is an allowlist of the HTML that content may contain. Elements and
+// attributes it does not list are removed, keeping the text inside removed
+// elements apart from elements such as <script> whose content is not text.
+// End tags that close nothing the content opened are removed and elements
+// left open are closed, so content cannot close the page around it.
+type Policy struct {
+	// Elements maps each allowed element to the attributes allowed on it
+	Elements map[string][]string
+
+	// Global lists attributes allowed on every allowed element
+	Global []string
+
+	// URLAttributes hold URLs, which must be relative or use one of Schemes
+	URLAttributes []string
+	Schemes       []string
+}
+
+// DefaultPolicy allows the formatting, lists, tables, links and images used
+// in tutorials, with links to http, https and mailto URLs
+func DefaultPolicy() Policy {
+	p := Policy{
+		Elements: map[string][]string{
+			"a":     {"href"},
+			"abbr":  nil,
+			"img":   {"src", "alt", "width", "height"},
+			"ol":    {"start"},
+			"th":    {"colspan", "rowspan", "scope"},
+			"td":    {"colspan", "rowspan"},
+			"table": nil,
+		},
+		// id is left out so content cannot clobber the ids of the page
+		Global:        []string{"class", "title", "lang"},
+		URLAttributes: []string{"href", "src"},
+		Schemes:       []string{"http", "https", "mailto"},
+	}
+	for _, name := range strings.Fields(`p br hr div span blockquote pre code kbd samp var
+		b i em strong small sub sup mark s q h2 h3 h4 h5 h6 ul li dl dt dd
+		thead tbody tfoot tr caption figure figcaption details summary`) {
+		p.Elements[name] = nil
+	}
+	return p
+}
+
+// ContentPolicy is applied to tutorials, translations and examples when they
+// are loaded. Change it before the content is first used.
+var ContentPolicy = DefaultPolicy()
+
+// Stripped is something the sanitizer removed from a field of a tutorial,
+// translation or example
+type Stripped struct {
+	Item    string `json:"item"`
+	Field   string `json:"field"`
+	Removed string `json:"removed"`
+}
+
+// String describes what was removed and where from
+func (s Stripped) String() string {
+	return fmt.Sprintf("%s %s: removed %s", s.Item, s.Field, s.Removed)
+}
+
+// rawText are elements whose content is removed with them
+var rawText = map[string]bool{
+	"script": true, "style": true, "template": true, "iframe": true, "object": true, "noscript": true,
+	"textarea": true, "title": true, "xmp": true, "noembed": true, "noframes": true,
+}
+
+// void are allowed elements that have no end tag
+var void = map[string]bool{"br": true, "hr": true, "img": true}
+
+// Sanitize returns h with everything the policy does not allow removed, and
+// a description of each thing removed
+func (p Policy) Sanitize(h template.HTML) (template.HTML, []string) {
+	var out strings.Builder
+	var removed []string
+	var open []string
+	s := string(h)
+	for s != "" {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			out.WriteString(s)
+			break
+		}
+		out.WriteString(s[:i])
+		s = s[i:]
+
+		if rest, ok := htmltag.SkipComment(s); ok {
+			s = rest
+			removed = append(removed, "a comment")
+			continue
+		}
+		t, n, ok := htmltag.Parse(s)
+		if !ok {
+			// Not a tag, so the browser shows it as text
+			out.WriteString("&lt;")
+			s = s[1:]
+			continue
+		}
+		s = s[n:]
+
+		allowed, ok := p.Elements[t.Name]
+		if !ok {
+			if t.End {
+				continue
+			}
+			removed = append(removed, "<"+t.Name+"> element")
+			if rawText[t.Name] && !t.SelfClosing {
+				s = htmltag.SkipRawText(s, t.Name)
+			}
+			continue
+		}
+		if t.End {
+			i := len(open) - 1
+			for i >= 0 && open[i] != t.Name {
+				i--
+			}
+			if i < 0 {
+				removed = append(removed, "unmatched </"+t.Name+"> end tag")
+				continue
+			}
+			// Close anything still open inside it, as browsers do
+			for len(open) > i {
+				if len(open) > i+1 {
+					removed = append(removed, "unclosed <"+open[len(open)-1]+"> element")
+				}
+				out.WriteString("</" + open[len(open)-1] + ">")
+				open = open[:len(open)-1]
+			}
+			continue
+		}
+		if !void[t.Name] {
+			open = append(open, t.Name)
+		}
+
+		out.WriteString("<" + t.Name)
+		seen := make(map[string]bool)
+		for _, a := range t.Attrs {
+			switch {
+			case seen[a.Name]:
+				// Browsers use the first
+			case !contains(allowed, a.Name) && !contains(p.Global, a.Name):
+				removed = append(removed, fmt.Sprintf("%s attribute on <%s>", a.Name, t.Name))
+			case contains(p.URLAttributes, a.Name) && !p.allowedURL(a.Value):
+				removed = append(removed, fmt.Sprintf("%s URL in %s on <%s>", urlScheme(a.Value), a.Name, t.Name))
+			default:
+				out.WriteString(" " + a.Name)
+				if a.HasValue {
+					out.WriteString(`="` + html.EscapeString(a.Value) + `"`)
+				}
+			}
+			seen[a.Name] = true
+		}
+		out.WriteString(">")
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		removed = append(removed, "unclosed <"+open[i]+"> element")
+		out.WriteString("</" + open[i] + ">")
+	}
+	return template.HTML(out.String()), removed
+}
+
+// allowedURL reports whether a URL is relative or uses an allowed scheme
+func (p Policy) allowedURL(u string) bool {
+	scheme := urlScheme(u)
+	return scheme == "" || contains(p.Schemes, scheme)
+}
+
+// urlScheme returns the lowercase scheme of a URL, or "" for a relative URL.
+// Browsers ignore whitespace and control characters in URLs, so they are
+// removed before looking.
+func urlScheme(u string) string {
+	u = strings.Map(func(r rune) rune {
+		if r <= ' ' {
+			return -1
+		}
+		return r
+	}, u)
+	i := strings.IndexAny(u, ":/?#")
+	if i <= 0 || u[i] != ':' || !htmltag.IsLetter(u[0]) {
+		return ""
+	}
+	for _, c := range []byte(u[1:i]) {
+		if !htmltag.IsLetter(c) && !('0' <= c && c <= '9') && c != '+' && c != '-' && c != '.' {
+			return ""
+		}
+	}
+	return strings.ToLower(u[:i])
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// field sanitizes one field, recording what was removed from it
+func (p Policy) field(h template.HTML, item, name string, stripped *[]Stripped) template.HTML {
+	clean, removed := p.Sanitize(h)
+	for _, r := range removed {
+		*stripped = append(*stripped, Stripped{Item: item, Field: name, Removed: r})
+	}
+	return clean
+}
+
+// SanitizeTutorial sanitizes a tutorial's description, explanation and
+// callouts
+func (p Policy) SanitizeTutorial(t Tutorial) (Tutorial, []Stripped) {
+	var stripped []Stripped
+	item := "tutorial " + t.ID
+	t.Description = p.field(t.Description, item, "Description", &stripped)
+	t.Explanation = p.field(t.Explanation, item, "Explanation", &stripped)
+	t.Code = append([]CodeBlock(nil), t.Code...)
+	for i := range t.Code {
+		t.Code[i].Callouts = append([]Callout(nil), t.Code[i].Callouts...)
+		for j := range t.Code[i].Callouts {
+			name := fmt.Sprintf("Code[%d].Callouts[%d]", i+1, j+1)
+			t.Code[i].Callouts[j].Note = p.field(t.Code[i].Callouts[j].Note, item, name, &stripped)
+		}
+	}
+	return t, stripped
+}
+
+// SanitizeExample sanitizes an example's description
+func (p Policy) SanitizeExample(e CodeExample) (CodeExample, []Stripped) {
+	var stripped []Stripped
+	e.Description = p.field(e.Description, "example "+e.Filename, "Description", &stripped)
+	return e, stripped
+}
+
+// sanitize returns the published content sanitized
+func (p Published) sanitize(policy Policy) (Published, []Stripped) {
+	var stripped []Stripped
+	clean := Published{
+		Tutorials: make([]PublishedTutorial, len(p.Tutorials)),
+		Examples:  make([]CodeExample, len(p.Examples)),
+	}
+	for i, pt := range p.Tutorials {
+		t, s := policy.SanitizeTutorial(pt.Tutorial)
+		clean.Tutorials[i] = PublishedTutorial{Level: pt.Level, Tutorial: t}
+		stripped = append(stripped, s...)
+	}
+	for i, e := range p.Examples {
+		e, s := policy.SanitizeExample(e)
+		clean.Examples[i] = e
+		stripped = append(stripped, s...)
+	}
+	return clean, stripped
+}
@@ -0,0 +1,85 @@
+package content
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	p := DefaultPolicy()
+	for _, tc := range []struct {
+		in, want string
+		removed  []string
+	}{
+		{`<p>Call <code>http.HandleFunc</code> &amp; <a href="/basic#hello-world">go</a></p>`,
+			`<p>Call <code>http.HandleFunc</code> &amp; <a href="/basic#hello-world">go</a></p>`, nil},
+		{`<p class='note' title="It's">x</p>`, `<p class="note" title="It&#39;s">x</p>`, nil},
+		{`a<script>alert("<p>")</script>b<SCRIPT src=x></SCRIPT >c`, `abc`,
+			[]string{"<script> element", "<script> element"}},
+		{`<p onclick="steal()" id=intro>x</p>`, `<p>x</p>`, []string{"onclick attribute on <p>", "id attribute on <p>"}},
+		{`<p>x</div></p></div><ul><li>a<li>b</ul><div><em>open`, `<p>x</p><ul><li>a<li>b</li></li></ul><div><em>open</em></div>`,
+			[]string{"unmatched </div> end tag", "unmatched </div> end tag", "unclosed <li> element", "unclosed <li> element", "unclosed <em> element", "unclosed <div> element"}},
+		{`<a href=" java&#x09;script:alert(1)">x</a><a href="HTTPS://go.dev">y</a><a href="mailto:a@b.c">z</a>`,
+			`<a>x</a><a href="HTTPS://go.dev">y</a><a href="mailto:a@b.c">z</a>`, []string{"javascript URL in href on <a>"}},
+		{`<img src="data:image/svg+xml,..." alt="x"><img/src="/static/a.png"/onerror=alert(1)>`,
+			`<img alt="x"><img src="/static/a.png">`, []string{"data URL in src on <img>", "onerror attribute on <img>"}},
+		{`<center>kept <b>text</b></center><!-- note -->`, `kept <b>text</b>`, []string{"<center> element", "a comment"}},
+		{`if a < b && c <d`, `if a &lt; b && c &lt;d`, nil},
+		{`<style>p { color: red }`, ``, []string{"<style> element"}},
+	} {
+		got, removed := p.Sanitize(template.HTML(tc.in))
+		if string(got) != tc.want || strings.Join(removed, "|") != strings.Join(tc.removed, "|") {
+			t.Errorf("Sanitize(%q) = %q, %q; want %q, %q", tc.in, got, removed, tc.want, tc.removed)
+		}
+	}
+
+	// The policy is configurable
+	p.Elements["center"] = nil
+	p.Schemes = append(p.Schemes, "data")
+	if got, removed := p.Sanitize(`<center><img src="data:image/png;base64,AA"></center>`); len(removed) != 0 {
+		t.Errorf("custom policy removed %q from %q", removed, got)
+	}
+}
+
+func TestSanitizeContent(t *testing.T) {
+	// The built-in content is already safe
+	if stripped := BuiltinStripped(); len(stripped) != 0 {
+		t.Errorf("stripped from built-in content: %v", stripped)
+	}
+
+	hello := GetBasicTutorials()[0]
+	hello.Description = `<p onmouseover="x()">Hi</p>`
+	hello.Code[0].Callouts = []Callout{{Line: 1, Note: "<script>x()</script>Starts"}}
+	example := builtinExamples()[0]
+	example.Description = `<iframe src="https://example.com"></iframe>Echo`
+	stripped := SetPublished(Published{
+		Tutorials: []PublishedTutorial{{Level: "basic", Tutorial: hello}},
+		Examples:  []CodeExample{example},
+	})
+	defer SetPublished(Published{})
+
+	var got []string
+	for _, s := range stripped {
+		got = append(got, s.String())
+	}
+	want := []string{
+		"tutorial hello-world Description: removed onmouseover attribute on <p>",
+		"tutorial hello-world Code[1].Callouts[1]: removed <script> element",
+		"example " + example.Filename + " Description: removed <iframe> element",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("stripped:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	served, _, _ := FindTutorial("hello-world")
+	if served.Description != "<p>Hi</p>" || served.Code[0].Callouts[0].Note != "Starts" {
+		t.Errorf("served %q and %q", served.Description, served.Code[0].Callouts[0].Note)
+	}
+	if hello.Code[0].Callouts[0].Note != "<script>x()</script>Starts" {
+		t.Error("sanitizing changed the published tutorial it was given")
+	}
+	if GetCodeExamples()[0].Description != "Echo" {
+		t.Errorf("served example %q", GetCodeExamples()[0].Description)
+	}
+}
@@ -0,0 +1,160 @@
+package content
+
+import (
+	"html/template"
+	"sort"
+	"time"
+)
+
+// Translation is a tutorial's text in another language. Code, quizzes and
+// exercises are shared with the English tutorial.
+type Translation struct {
+	Title       string
+	Description template.HTML
+	Explanation template.HTML
+
+	// Source is the Updated date of the English tutorial that was translated.
+	// A translation is stale once the tutorial is updated after it.
+	Source time.Time
+}
+
+// translations maps a language tag and tutorial ID to the translated text.
+// Tutorials without a translation are shown in English.
+var translations = map[string]map[string]Translation{
+	"es": {
+		"hello-world": {
+			Title:  "Servidor web Hola Mundo",
+			Source: date(2025, time.March, 3),
+			Description: template.HTML(`
+				<p>Este es el servidor web más sencillo posible en Go. Responde con "Hello, World!" a cada petición.</p>
+				<p>El paquete <code>net/http</code> ofrece todo lo necesario para crear servidores y clientes HTTP.</p>
+			`),
+			Explanation: template.HTML(`
+				<h4>Cómo funciona:</h4>
+				<ul>
+					<li><code>http.HandleFunc("/")</code> registra una función que atiende todas las peticiones a la ruta raíz (ver <a href="#hello-world-1-L10">línea 10</a>).</li>
+					<li><code>http.ListenAndServe</code> inicia un servidor HTTP que escucha en la dirección indicada (ver <a href="#hello-world-1-L14">línea 14</a>).</li>
+					<li>El segundo parámetro de <code>ListenAndServe</code> es un handler. <code>nil</code> significa usar el enrutador por defecto.</li>
+					<li>Nuestra función <code>hello</code> recibe los parámetros <code>http.ResponseWriter</code> y <code>http.Request</code>.</li>
+					<li>Con <code>fmt.Fprintf</code> escribimos el texto de la respuesta en el response writer.</li>
+				</ul>
+			`),
+		},
+		"serve-html": {
+			Title:  "Servir páginas HTML",
+			Source: date(2025, time.March, 3),
+			Description: template.HTML(`
+				<p>La mayoría de los servidores web necesitan servir páginas HTML. Así se sirve contenido HTML estático en Go.</p>
+			`),
+			Explanation: template.HTML(`
+				<h4>Cómo funciona:</h4>
+				<ul>
+					<li><code>http.FileServer</code> crea un handler que sirve los archivos del directorio indicado.</li>
+					<li><code>http.StripPrefix</code> quita el prefijo indicado de la ruta de la URL antes de pasarla al handler.</li>
+					<li><code>http.ServeFile</code> sirve un archivo concreto como respuesta a una petición.</li>
+				</ul>
+			`),
+		},
+		"handling-routes": {
+			Title:  "Manejar distintas rutas URL",
+			Source: date(2025, time.March, 10),
+			Description: template.HTML(`
+				<p>Un servidor web debe tratar cada ruta (URL) de forma distinta. Así se implementa un enrutamiento básico en Go.</p>
+			`),
+			Explanation: template.HTML(`
+				<h4>Cómo funciona:</h4>
+				<ul>
+					<li>Registramos una función handler distinta para cada ruta con <code>http.HandleFunc</code>.</li>
+					<li>Cada función handler puede hacer algo diferente según la ruta.</li>
+					<li>En <code>homeHandler</code> comprobamos si la ruta es exactamente "/" y, si no lo es, devolvemos un error 404.</li>
+					<li>Esto es importante porque la ruta "/" coincide con todas las rutas que no coinciden con otras.</li>
+					<li>Para un enrutamiento más complejo, considera bibliotecas como Gorilla Mux o Chi.</li>
+				</ul>
+			`),
+		},
+	},
+	"de": {
+		"hello-world": {
+			Title:  "Hallo-Welt-Webserver",
+			Source: date(2025, time.March, 3),
+			Description: template.HTML(`
+				<p>Das ist der einfachste mögliche Webserver in Go. Er beantwortet jede Anfrage mit "Hello, World!".</p>
+				<p>Das Paket <code>net/http</code> bietet alles, was man für HTTP-Server und -Clients braucht.</p>
+			`),
+			Explanation: template.HTML(`
+				<h4>So funktioniert es:</h4>
+				<ul>
+					<li><code>http.HandleFunc("/")</code> registriert eine Funktion für alle Anfragen an den Wurzelpfad (siehe <a href="#hello-world-1-L10">Zeile 10</a>).</li>
+					<li><code>http.ListenAndServe</code> startet einen HTTP-Server auf der angegebenen Adresse (siehe <a href="#hello-world-1-L14">Zeile 14</a>).</li>
+					<li>Der zweite Parameter von <code>ListenAndServe</code> ist ein Handler. <code>nil</code> bedeutet, dass der Standard-Router verwendet wird.</li>
+					<li>Unsere Funktion <code>hello</code> erhält die Parameter <code>http.ResponseWriter</code> und <code>http.Request</code>.</li>
+					<li>Mit <code>fmt.Fprintf</code> schreiben wir den Antworttext in den Response-Writer.</li>
+				</ul>
+			`),
+		},
+	},
+}
+
+// Localize returns the tutorial in the language tag, falling back to
+// English for any tutorial without a translation
+func Localize(t Tutorial, tag string) Tutorial {
+	loadBuiltin()
+	tr, ok := translations[tag][t.ID]
+	if !ok {
+		return t
+	}
+	t.Title = tr.Title
+	t.Description = tr.Description
+	t.Explanation = tr.Explanation
+	t.Locale = tag
+	t.Outdated = tr.Source.Before(t.Updated)
+	return t
+}
+
+// LocalizeAll localizes each tutorial in a list
+func LocalizeAll(tutorials []Tutorial, tag string) []Tutorial {
+	localized := make([]Tutorial, len(tutorials))
+	for i, t := range tutorials {
+		localized[i] = Localize(t, tag)
+	}
+	return localized
+}
+
+// StaleTranslation is a translation made before the latest update to its
+// English tutorial
+type StaleTranslation struct {
+	Locale     string
+	TutorialID string
+	Source     time.Time // the English version that was translated
+	Updated    time.Time // the current English version
+}
+
+// StaleTranslations lists translations that need updating, sorted by
+// language and tutorial ID. Translations of unknown tutorials are included
+// with a zero Updated time.
+func StaleTranslations() []StaleTranslation {
+	updated := make(map[string]time.Time)
+	known := make(map[string]bool)
+	for _, level := range GetLevels() {
+		for _, t := range level.Tutorials {
+			updated[t.ID] = t.Updated
+			known[t.ID] = true
+		}
+	}
+
+	var stale []StaleTranslation
+	for tag, byID := range translations {
+		for id, tr := range byID {
+			if !known[id] || tr.Source.Before(updated[id]) {
+				stale = append(stale, StaleTranslation{Locale: tag, TutorialID: id, Source: tr.Source, Updated: updated[id]})
+			}
+		}
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		if stale[i].Locale != stale[j].Locale {
+			return stale[i].Locale < stale[j].Locale
+		}
+		return stale[i].TutorialID < stale[j].TutorialID
+	})
+	return stale
+}
@@ -0,0 +1,60 @@
+package content
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslations(t *testing.T) {
+	tutorials := make(map[string]Tutorial)
+	for _, level := range GetLevels() {
+		for _, tutorial := range level.Tutorials {
+			tutorials[tutorial.ID] = tutorial
+		}
+	}
+
+	for tag, byID := range translations {
+		for id, tr := range byID {
+			tutorial, ok := tutorials[id]
+			if !ok {
+				t.Errorf("%s: translation of unknown tutorial %q", tag, id)
+				continue
+			}
+			if tr.Title == "" || tr.Description == "" || tr.Explanation == "" || tr.Source.IsZero() {
+				t.Errorf("%s/%s: incomplete translation", tag, id)
+			}
+			if tr.Source.After(tutorial.Updated) {
+				t.Errorf("%s/%s: translated from a version newer than the tutorial", tag, id)
+			}
+
+			// Line links must match the English explanation's
+			want := strings.Join(lineLink.FindAllString(string(tutorial.Explanation), -1), " ")
+			if got := strings.Join(lineLink.FindAllString(string(tr.Explanation), -1), " "); got != want {
+				t.Errorf("%s/%s: line links %q, want %q", tag, id, got, want)
+			}
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	hello := GetBasicTutorials()[0]
+	if got := Localize(hello, "es"); got.Title != "Servidor web Hola Mundo" || got.Locale != "es" || got.Outdated {
+		t.Errorf("Localize(es) = %q locale %q outdated %v", got.Title, got.Locale, got.Outdated)
+	}
+	if got := Localize(hello, "fr"); got.Title != hello.Title || got.Locale != "" {
+		t.Errorf("Localize(fr) = %q locale %q", got.Title, got.Locale)
+	}
+
+	localized := LocalizeAll(GetBasicTutorials(), "de")
+	if localized[0].Locale != "de" || localized[1].Locale != "" {
+		t.Errorf("LocalizeAll(de) locales = %q, %q", localized[0].Locale, localized[1].Locale)
+	}
+
+	stale := StaleTranslations()
+	if len(stale) != 1 || stale[0].Locale != "es" || stale[0].TutorialID != "serve-html" {
+		t.Fatalf("StaleTranslations() = %+v", stale)
+	}
+	if !Localize(GetBasicTutorials()[1], "es").Outdated {
+		t.Error("stale translation not marked outdated")
+	}
+}
@@ -1,7 +1,10 @@
 package content
 
 import (
+	"fmt"
 	"html/template"
+	"path"
+	"time"
 )
 
 // Tutorial represents a single tutorial with title, description, and code examples
@@ -9,23 +12,80 @@ type Tutorial struct {
 	ID          string
 	Title       string
 	Description template.HTML
-	Code        template.HTML
+	Code        []CodeBlock
 	Explanation template.HTML
+	Quiz        *Quiz
+	Exercise    *Exercise
+	Published   time.Time
+	Updated     time.Time
+
+	// Prerequisites are the IDs of tutorials to finish first
+	Prerequisites []string
+
+	// Topics tag the tutorial for learning paths, such as "json" or "routing"
+	Topics []string
+
+	// Locale is the language of a translated tutorial, empty for English.
+	// Outdated marks a translation of an earlier version of the tutorial.
+	Locale   string
+	Outdated bool
+}
+
+// CodeBlock is a single source file shown in a tutorial
+type CodeBlock struct {
+	Filename  string
+	Language  string
+	Source    string
+	Highlight string
+	Callouts  []Callout
+}
+
+// Callout attaches an explanatory note to one line of a code block
+type Callout struct {
+	Line int
+	Note template.HTML
+}
+
+// CodeAnchor returns the element ID of the code block at index i. Line anchors
+// append "-L" and the line number, so Explanation can link to "#hello-world-1-L10".
+func (t Tutorial) CodeAnchor(i int) string {
+	return fmt.Sprintf("%s-%d", t.ID, i+1)
+}
+
+// CodeURL returns the path serving the plain source of the code block at
+// index i, such as /tutorials/hello-world/code/1.go
+func (t Tutorial) CodeURL(i int) string {
+	return fmt.Sprintf("/tutorials/%s/code/%d%s", t.ID, i+1, path.Ext(t.Code[i].Filename))
+}
+
+// date returns midnight UTC on the given day, used for content metadata
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
 }
 
 // GetBasicTutorials returns all basic level tutorials
 func GetBasicTutorials() []Tutorial {
 	return []Tutorial{
 		{
-			ID:    "hello-world",
-			Title: "Hello World Web Server",
+			ID:        "hello-world",
+			Title:     "Hello World Web Server",
+			Topics:    []string{"handlers", "basics"},
+			Published: date(2025, time.March, 3),
+			Updated:   date(2025, time.March, 3),
 			Description: template.HTML(`
 				<p>This is the simplest possible web server in Go. It responds with "Hello, World!" to every request.</p>
 				<p>The <code>net/http</code> package provides all the functionality needed to create HTTP servers and clients.</p>
 			`),
-			Code: template.HTML(`
-<pre><code class="language-go">
-package main
+			Code: []CodeBlock{
+				{
+					Filename:  "main.go",
+					Language:  "go",
+					Highlight: "10,14",
+					Callouts: []Callout{
+						{Line: 10, Note: template.HTML(`Every request path is sent to <code>hello</code> because "/" matches everything.`)},
+						{Line: 14, Note: template.HTML(`<code>ListenAndServe</code> blocks for as long as the server is running.`)},
+					},
+					Source: `package main
 
 import (
 	"fmt"
@@ -45,28 +105,37 @@ func hello(w http.ResponseWriter, r *http.Request) {
 	// Write a response to the client
 	fmt.Fprintf(w, "Hello, World!")
 }
-</code></pre>
-			`),
+`,
+				},
+			},
 			Explanation: template.HTML(`
 				<h4>How It Works:</h4>
 				<ul>
-					<li><code>http.HandleFunc("/")</code> registers a function to handle all requests to the root path.</li>
-					<li><code>http.ListenAndServe</code> starts an HTTP server listening on the specified address.</li>
+					<li><code>http.HandleFunc("/")</code> registers a function to handle all requests to the root path (see <a href="#hello-world-1-L10">line 10</a>).</li>
+					<li><code>http.ListenAndServe</code> starts an HTTP server listening on the specified address (see <a href="#hello-world-1-L14">line 14</a>).</li>
 					<li>The second parameter to <code>ListenAndServe</code> is a handler. <code>nil</code> means use the default router.</li>
 					<li>Our <code>hello</code> function gets the <code>http.ResponseWriter</code> and <code>http.Request</code> parameters.</li>
 					<li>Using <code>fmt.Fprintf</code>, we write our response text to the response writer.</li>
 				</ul>
 			`),
+			Quiz:     helloWorldQuiz,
+			Exercise: helloWorldExercise,
 		},
 		{
-			ID:    "serve-html",
-			Title: "Serving HTML Pages",
+			ID:            "serve-html",
+			Title:         "Serving HTML Pages",
+			Prerequisites: []string{"hello-world"},
+			Topics:        []string{"html", "handlers"},
+			Published:     date(2025, time.March, 3),
+			Updated:       date(2025, time.April, 14),
 			Description: template.HTML(`
 				<p>Most web servers need to serve HTML pages. Here's how to serve static HTML content in Go.</p>
 			`),
-			Code: template.HTML(`
-<pre><code class="language-go">
-package main
+			Code: []CodeBlock{
+				{
+					Filename: "main.go",
+					Language: "go",
+					Source: `package main
 
 import (
 	"net/http"
@@ -88,8 +157,9 @@ func homePage(w http.ResponseWriter, r *http.Request) {
 	// Serve the home page HTML file
 	http.ServeFile(w, r, "templates/index.html")
 }
-</code></pre>
-			`),
+`,
+				},
+			},
 			Explanation: template.HTML(`
 				<h4>How It Works:</h4>
 				<ul>
@@ -102,14 +172,20 @@ func homePage(w http.ResponseWriter, r *http.Request) {
 			`),
 		},
 		{
-			ID:    "handling-routes",
-			Title: "Handling Different URL Routes",
+			ID:            "handling-routes",
+			Title:         "Handling Different URL Routes",
+			Prerequisites: []string{"hello-world"},
+			Topics:        []string{"routing", "handlers"},
+			Published:     date(2025, time.March, 10),
+			Updated:       date(2025, time.March, 10),
 			Description: template.HTML(`
 				<p>A web server needs to handle different routes (URLs) differently. Here's how to implement basic routing in Go.</p>
 			`),
-			Code: template.HTML(`
-<pre><code class="language-go">
-package main
+			Code: []CodeBlock{
+				{
+					Filename: "main.go",
+					Language: "go",
+					Source: `package main
 
 import (
 	"fmt"
@@ -143,8 +219,9 @@ func aboutHandler(w http.ResponseWriter, r *http.Request) {
 func contactHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "Contact Us page")
 }
-</code></pre>
-			`),
+`,
+				},
+			},
 			Explanation: template.HTML(`
 				<h4>How It Works:</h4>
 				<ul>
@@ -155,6 +232,7 @@ func contactHandler(w http.ResponseWriter, r *http.Request) {
 					<li>For more complex routing, consider using router libraries like Gorilla Mux or Chi.</li>
 				</ul>
 			`),
+			Quiz: handlingRoutesQuiz,
 		},
 	}
 }
@@ -163,15 +241,26 @@ func contactHandler(w http.ResponseWriter, r *http.Request) {
 func GetIntermediateTutorials() []Tutorial {
 	return []Tutorial{
 		{
-			ID:    "html-templates",
-			Title: "Using HTML Templates",
+			ID:            "html-templates",
+			Title:         "Using HTML Templates",
+			Prerequisites: []string{"serve-html", "handling-routes"},
+			Topics:        []string{"html", "templates"},
+			Published:     date(2025, time.March, 17),
+			Updated:       date(2025, time.May, 5),
 			Description: template.HTML(`
 				<p>Go's <code>html/template</code> package provides a powerful way to create dynamic HTML pages.</p>
 				<p>It allows you to insert dynamic content into HTML templates, with automatic HTML escaping to prevent XSS attacks.</p>
 			`),
-			Code: template.HTML(`
-<pre><code class="language-go">
-package main
+			Code: []CodeBlock{
+				{
+					Filename:  "main.go",
+					Language:  "go",
+					Highlight: "32,39",
+					Callouts: []Callout{
+						{Line: 32, Note: template.HTML(`Parsing on every request keeps the example short; real servers parse templates once at startup.`)},
+						{Line: 39, Note: template.HTML(`<code>Execute</code> writes the rendered page straight to the response.`)},
+					},
+					Source: `package main
 
 import (
 	"html/template"
@@ -214,15 +303,40 @@ func templateHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
-</code></pre>
-			`),
+`,
+				},
+				{
+					Filename:  "templates/demo.html",
+					Language:  "html",
+					Highlight: "7,9-11",
+					Callouts: []Callout{
+						{Line: 7, Note: template.HTML(`<code>{{.Message}}</code> prints the <code>Message</code> field of the data passed to <code>Execute</code>.`)},
+						{Line: 9, Note: template.HTML(`<code>{{range .Items}}</code> repeats its body once per item, with <code>{{.}}</code> set to the current item.`)},
+					},
+					Source: `<!DOCTYPE html>
+<html>
+<head>
+	<title>{{.Title}}</title>
+</head>
+<body>
+	<h1>{{.Message}}</h1>
+	<ul>
+		{{range .Items}}
+		<li>{{.}}</li>
+		{{end}}
+	</ul>
+</body>
+</html>
+`,
+				},
+			},
 			Explanation: template.HTML(`
 				<h4>How It Works:</h4>
 				<ul>
-					<li><code>template.ParseFiles</code> loads and parses the template file.</li>
-					<li><code>tmpl.Execute</code> fills in the template with the provided data and writes to the response writer.</li>
-					<li>In the template file, <code>{{.FieldName}}</code> inserts the value of the field.</li>
-					<li><code>{{range .Items}}</code> loops over the Items slice.</li>
+					<li><code>template.ParseFiles</code> loads and parses the template file (see <a href="#html-templates-1-L32">line 32</a>).</li>
+					<li><code>tmpl.Execute</code> fills in the template with the provided data and writes to the response writer (see <a href="#html-templates-1-L39">line 39</a>).</li>
+					<li>In the template file, <code>{{.FieldName}}</code> inserts the value of the field (see <a href="#html-templates-2-L7">line 7 of demo.html</a>).</li>
+					<li><code>{{range .Items}}</code> loops over the Items slice (see <a href="#html-templates-2-L9">line 9 of demo.html</a>).</li>
 					<li>Go templates automatically escape HTML to prevent XSS attacks.</li>
 					<li>The <code>html/template</code> package handles nested templates, conditionals, and more.</li>
 				</ul>
@@ -235,15 +349,21 @@ func templateHandler(w http.ResponseWriter, r *http.Request) {
 func GetAdvancedTutorials() []Tutorial {
 	return []Tutorial{
 		{
-			ID:    "json-apis",
-			Title: "Building JSON APIs",
+			ID:            "json-apis",
+			Title:         "Building JSON APIs",
+			Prerequisites: []string{"handling-routes"},
+			Topics:        []string{"json", "apis"},
+			Published:     date(2025, time.March, 24),
+			Updated:       date(2025, time.March, 24),
 			Description: template.HTML(`
 				<p>Go has excellent support for working with JSON, making it easy to build JSON APIs.</p>
 				<p>Let's explore how to create JSON endpoints, handle JSON requests, and parse JSON data.</p>
 			`),
-			Code: template.HTML(`
-<pre><code class="language-go">
-package main
+			Code: []CodeBlock{
+				{
+					Filename: "main.go",
+					Language: "go",
+					Source: `package main
 
 import (
 	"encoding/json"
@@ -280,8 +400,9 @@ func usersHandler(w http.ResponseWriter, r *http.Request) {
 	// Return all users as JSON
 	json.NewEncoder(w).Encode(users)
 }
-</code></pre>
-			`),
+`,
+				},
+			},
 			Explanation: template.HTML(`
 				<h4>How It Works:</h4>
 				<ul>
@@ -291,6 +412,8 @@ func usersHandler(w http.ResponseWriter, r *http.Request) {
 					<li>We set <code>Content-Type: application/json</code> in the response headers.</li>
 				</ul>
 			`),
+			Quiz:     jsonAPIsQuiz,
+			Exercise: jsonAPIsExercise,
 		},
 	}
 }
@@ -299,15 +422,21 @@ func usersHandler(w http.ResponseWriter, r *http.Request) {
 func GetRestfulTutorials() []Tutorial {
 	return []Tutorial{
 		{
-			ID:    "rest-basics",
-			Title: "RESTful API Basics",
+			ID:            "rest-basics",
+			Title:         "RESTful API Basics",
+			Prerequisites: []string{"json-apis"},
+			Topics:        []string{"rest", "apis", "json"},
+			Published:     date(2025, time.March, 31),
+			Updated:       date(2025, time.June, 2),
 			Description: template.HTML(`
 				<p>REST (Representational State Transfer) is an architectural style for designing networked applications.</p>
 				<p>RESTful APIs use HTTP methods explicitly and are stateless, with resources identified by URLs.</p>
 			`),
-			Code: template.HTML(`
-<pre><code class="language-go">
-package main
+			Code: []CodeBlock{
+				{
+					Filename: "main.go",
+					Language: "go",
+					Source: `package main
 
 import (
 	"encoding/json"
@@ -372,8 +501,9 @@ func productHandler(w http.ResponseWriter, r *http.Request) {
 	
 	http.NotFound(w, r)
 }
-</code></pre>
-			`),
+`,
+				},
+			},
 			Explanation: template.HTML(`
 				<h4>RESTful Principles:</h4>
 				<ul>
@@ -385,4 +515,4 @@ func productHandler(w http.ResponseWriter, r *http.Request) {
 			`),
 		},
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,55 @@
+package content
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+
+	"golang-webserver-tutorial/highlight"
+)
+
+// lineLink matches a reference from an Explanation to a code line anchor
+var lineLink = regexp.MustCompile(`href="#([a-z0-9-]+)-([0-9]+)-L([0-9]+)"`)
+
+func TestCodeBlocks(t *testing.T) {
+	for _, level := range GetLevels() {
+		for _, tutorial := range level.Tutorials {
+			if len(tutorial.Code) == 0 {
+				t.Errorf("%s: no code blocks", tutorial.ID)
+			}
+
+			lineCounts := make([]int, len(tutorial.Code))
+			for i, block := range tutorial.Code {
+				if block.Filename == "" || block.Language == "" {
+					t.Errorf("%s block %d: missing filename or language", tutorial.ID, i+1)
+				}
+				if !highlight.Supported(block.Language) {
+					t.Errorf("%s block %d: unsupported language %q", tutorial.ID, i+1, block.Language)
+				}
+
+				lineCounts[i] = highlight.LineCount(highlight.TrimSource(block.Source))
+				if _, err := highlight.ParseLines(block.Highlight, lineCounts[i]); err != nil {
+					t.Errorf("%s block %d: %v", tutorial.ID, i+1, err)
+				}
+				for _, callout := range block.Callouts {
+					if callout.Line < 1 || callout.Line > lineCounts[i] {
+						t.Errorf("%s block %d: callout on missing line %d", tutorial.ID, i+1, callout.Line)
+					}
+				}
+			}
+
+			// Line references in the explanation must point at real lines
+			for _, m := range lineLink.FindAllStringSubmatch(string(tutorial.Explanation), -1) {
+				if m[1] != tutorial.ID {
+					t.Errorf("%s: explanation links to another tutorial's code: %s", tutorial.ID, m[0])
+					continue
+				}
+				block, _ := strconv.Atoi(m[2])
+				line, _ := strconv.Atoi(m[3])
+				if block < 1 || block > len(tutorial.Code) || line > lineCounts[block-1] {
+					t.Errorf("%s: explanation links to a missing line: %s", tutorial.ID, m[0])
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,290 @@
+// Package crawl follows the links on a site, starting at its home page, and
+// reports links, anchors and assets that do not work. Pages on the site are
+// crawled; links to other sites are listed but never fetched.
+package crawl
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MaxBodySize limits how much of each page is read for links
+const MaxBodySize = 4 << 20
+
+// Link is a link, asset or anchor found on a page
+type Link struct {
+	// Page is the page the link appears on
+	Page string `json:"page"`
+	// Target is the link as it appears in the page
+	Target string `json:"target"`
+	Status int    `json:"status,omitempty"`
+	// Problem says why the link is broken
+	Problem string `json:"problem,omitempty"`
+}
+
+// String formats the link as a single line
+func (l Link) String() string {
+	s := l.Page + ": " + l.Target
+	if l.Problem != "" {
+		s += ": " + l.Problem
+	}
+	return s
+}
+
+// Report is the outcome of a crawl
+type Report struct {
+	BaseURL string `json:"base_url"`
+	// Pages are the paths of the HTML pages crawled
+	Pages []string `json:"pages"`
+	// Checked counts the URLs requested
+	Checked int `json:"checked"`
+	// Broken are the links, assets and anchors that do not work
+	Broken []Link `json:"broken"`
+	// External are links to other sites, which are not fetched
+	External []Link        `json:"external"`
+	Passed   bool          `json:"passed"`
+	Started  time.Time     `json:"started"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Crawler crawls a site
+type Crawler struct {
+	// Client makes the requests; http.DefaultClient is used when nil
+	Client *http.Client
+
+	// Skip leaves out paths for which it returns true, such as event
+	// streams that never finish. Links to them are not checked.
+	Skip func(path string) bool
+
+	// MaxPages stops the crawl after this many pages; zero means no limit
+	MaxPages int
+}
+
+// page is what was learned by fetching a URL
+type page struct {
+	status  int
+	problem string
+	html    bool
+	ids     map[string]bool
+	links   []found
+}
+
+// found is a link found in a page, with the URL it resolves to
+type found struct {
+	target string
+	url    *url.URL
+	follow bool
+	// problem is set when the link cannot be parsed
+	problem string
+}
+
+// Crawl crawls the site at base, which should be the address of its home
+// page such as http://localhost:5000/
+func (c *Crawler) Crawl(ctx context.Context, base string) (Report, error) {
+	root, err := url.Parse(base)
+	if err != nil || (root.Scheme != "http" && root.Scheme != "https") || root.Host == "" {
+		return Report{}, fmt.Errorf("crawl: %q is not an http URL", base)
+	}
+	if root.Path == "" {
+		root.Path = "/"
+	}
+	report := Report{BaseURL: root.String(), Started: time.Now()}
+
+	pages := make(map[string]*page)
+	queue := []string{root.String()}
+	fetched := func(u string) *page {
+		if p, ok := pages[u]; ok {
+			return p
+		}
+		p := c.fetch(ctx, u, root)
+		pages[u] = p
+		report.Checked++
+		return p
+	}
+
+	// Crawl every page reachable from the home page, collecting the
+	// links on each
+	var order []string
+	for len(queue) > 0 && ctx.Err() == nil {
+		u := queue[0]
+		queue = queue[1:]
+		if _, ok := pages[u]; ok {
+			continue
+		}
+		if c.MaxPages > 0 && len(order) >= c.MaxPages {
+			break
+		}
+		p := fetched(u)
+		if !p.html {
+			continue
+		}
+		order = append(order, u)
+		for _, l := range p.links {
+			if l.follow && !c.skipped(l.url) {
+				queue = append(queue, pageKey(l.url))
+			}
+		}
+	}
+
+	// Then check every link found, fetching assets not requested yet
+	seen := make(map[string]bool)
+	for _, u := range order {
+		from := mustParse(u).RequestURI()
+		report.Pages = append(report.Pages, from)
+		for _, l := range pages[u].links {
+			link := Link{Page: from, Target: l.target}
+			if seen[link.Page+" "+link.Target] {
+				continue
+			}
+			seen[link.Page+" "+link.Target] = true
+
+			if l.problem != "" {
+				link.Problem = l.problem
+				report.Broken = append(report.Broken, link)
+				continue
+			}
+			if l.url.Host != root.Host || (l.url.Scheme != "http" && l.url.Scheme != "https") {
+				report.External = append(report.External, link)
+				continue
+			}
+			if c.skipped(l.url) || ctx.Err() != nil {
+				continue
+			}
+			target := fetched(pageKey(l.url))
+			link.Status = target.status
+			switch {
+			case target.problem != "":
+				link.Problem = target.problem
+			case l.url.Fragment != "" && target.html && !target.ids[l.url.Fragment]:
+				link.Problem = "no element with id " + l.url.Fragment
+			default:
+				continue
+			}
+			report.Broken = append(report.Broken, link)
+		}
+	}
+
+	sort.Strings(report.Pages)
+	report.Passed = len(report.Broken) == 0 && ctx.Err() == nil
+	report.Duration = time.Since(report.Started)
+	return report, ctx.Err()
+}
+
+// skipped reports whether a link on the site is left out
+func (c *Crawler) skipped(u *url.URL) bool {
+	return c.Skip != nil && c.Skip(u.Path)
+}
+
+// fetch requests u and, if it is a page on the site, reads its links and IDs
+func (c *Crawler) fetch(ctx context.Context, u string, root *url.URL) *page {
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	p := &page{}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		p.problem = err.Error()
+		return p
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		p.problem = err.Error()
+		return p
+	}
+	defer resp.Body.Close()
+
+	p.status = resp.StatusCode
+	if resp.StatusCode >= 400 {
+		p.problem = resp.Status
+		return p
+	}
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" || resp.Request.URL.Host != root.Host {
+		io.Copy(io.Discard, io.LimitReader(resp.Body, MaxBodySize))
+		return p
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxBodySize))
+	if err != nil {
+		p.problem = err.Error()
+		return p
+	}
+	p.html = true
+	p.ids, p.links = parse(string(body), resp.Request.URL)
+	return p
+}
+
+var (
+	// tag matches a start tag and its attributes
+	tag = regexp.MustCompile(`<([a-zA-Z][a-zA-Z0-9-]*)((?:[^>"']|"[^"]*"|'[^']*')*)>`)
+
+	// attribute matches a quoted attribute
+	attribute = regexp.MustCompile(`([a-zA-Z-]+)\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+
+	// comment matches an HTML comment
+	comment = regexp.MustCompile(`<!--[\s\S]*?-->`)
+)
+
+// linkAttributes are the attributes of each element that link to another
+// page or asset
+var linkAttributes = map[string]string{
+	"a":      "href",
+	"link":   "href",
+	"script": "src",
+	"img":    "src",
+	"source": "src",
+	"iframe": "src",
+}
+
+// parse returns the element IDs in a page and the links in it, resolved
+// against the page's URL. Links in <a> elements are followed if they are on
+// the same site.
+func parse(body string, base *url.URL) (map[string]bool, []found) {
+	ids := make(map[string]bool)
+	var links []found
+	body = comment.ReplaceAllString(body, "")
+	for _, m := range tag.FindAllStringSubmatch(body, -1) {
+		name := strings.ToLower(m[1])
+		for _, a := range attribute.FindAllStringSubmatch(m[2], -1) {
+			key, value := strings.ToLower(a[1]), html.UnescapeString(a[2]+a[3])
+			switch {
+			case key == "id" || (name == "a" && key == "name"):
+				ids[value] = true
+			case linkAttributes[name] == key:
+				if value == "" || strings.HasPrefix(value, "javascript:") {
+					continue
+				}
+				u, err := base.Parse(value)
+				if err != nil {
+					links = append(links, found{target: value, url: base, problem: "not a valid URL"})
+					continue
+				}
+				follow := name == "a" && u.Host == base.Host && (u.Scheme == "http" || u.Scheme == "https")
+				links = append(links, found{target: value, url: u, follow: follow})
+			}
+		}
+	}
+	return ids, links
+}
+
+// pageKey returns the URL a link fetches, without its fragment
+func pageKey(u *url.URL) string {
+	c := *u
+	c.Fragment = ""
+	c.RawFragment = ""
+	return c.String()
+}
+
+func mustParse(raw string) *url.URL {
+	u, _ := url.Parse(raw)
+	return u
+}
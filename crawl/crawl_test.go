@@ -0,0 +1,79 @@
+package crawl
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCrawl(t *testing.T) {
+	pages := map[string]string{
+		"/": `<a href="/guide#intro">Guide</a> <a href="/guide#missing">Missing</a>
+			<a href='/files/app.css'>Styles</a> <a href="https://go.dev/doc/">Go</a>
+			<!-- <a href="/commented-out">Hidden</a> -->
+			<a href="/events">Events</a> <a href="mailto:team@example.com">Mail</a>`,
+		"/guide": `<h2 id="intro">Intro</h2> <a href="/">Home</a> <a href="/typo">Typo</a>
+			<img src="/files/logo.png"> <a href="/moved">Moved</a> <a href="?page=2&amp;sort=asc">Next</a>`,
+		"/new": `<a name="top"></a> <a href="/guide#intro">Back</a> <a href="#top">Top</a>`,
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := pages[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, "<!DOCTYPE html><html><body>"+body+"</body></html>")
+	})
+	mux.HandleFunc("/files/app.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		fmt.Fprint(w, `body { background: url("/not-a-link.png") }`)
+	})
+	mux.Handle("/moved", http.RedirectHandler("/new", http.StatusMovedPermanently))
+	mux.HandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("requested a skipped path")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := Crawler{Client: server.Client(), Skip: func(path string) bool { return path == "/events" }}
+	report, err := c.Crawl(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := strings.Join(report.Pages, " "); got != "/ /guide /guide?page=2&sort=asc /moved /new" {
+		t.Errorf("crawled %s", got)
+	}
+	var broken []string
+	for _, link := range report.Broken {
+		broken = append(broken, link.String())
+	}
+	want := []string{
+		"/: /guide#missing: no element with id missing",
+		"/guide: /typo: 404 Not Found",
+		"/guide: /files/logo.png: 404 Not Found",
+		"/guide?page=2&sort=asc: /typo: 404 Not Found",
+		"/guide?page=2&sort=asc: /files/logo.png: 404 Not Found",
+	}
+	if strings.Join(broken, "\n") != strings.Join(want, "\n") {
+		t.Errorf("broken links:\n%s\nwant:\n%s", strings.Join(broken, "\n"), strings.Join(want, "\n"))
+	}
+	if len(report.External) != 2 || report.External[0].Target != "https://go.dev/doc/" {
+		t.Errorf("external links: %v", report.External)
+	}
+	if report.Passed {
+		t.Error("passed with broken links")
+	}
+}
+
+func TestCrawlInvalidBase(t *testing.T) {
+	var c Crawler
+	if _, err := c.Crawl(context.Background(), "localhost:5000"); err == nil {
+		t.Error("crawled a URL without a scheme")
+	}
+}
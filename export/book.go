@@ -0,0 +1,55 @@
+package export
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"time"
+
+	"golang-webserver-tutorial/content"
+)
+
+// Book holds everything needed to export the tutorials as a single document
+type Book struct {
+	Title    string
+	Author   string
+	Language string
+	Modified time.Time
+	Levels   []content.Level
+}
+
+// NewBook creates a book containing the given levels in the order provided.
+// It is stamped with when the content last changed, so exporting the same
+// content always produces the same file.
+func NewBook(levels []content.Level) Book {
+	return Book{
+		Title:    "Go Web Server Tutorial",
+		Author:   "Go Web Server Tutorial",
+		Language: "en",
+		Modified: content.LastUpdated().UTC(),
+		Levels:   levels,
+	}
+}
+
+// Identifier returns a stable URN for the book derived from its chapter layout,
+// so re-exporting unchanged content keeps the same identity on e-readers
+func (b Book) Identifier() string {
+	h := sha1.New()
+	fmt.Fprintln(h, b.Title)
+	for _, level := range b.Levels {
+		fmt.Fprintln(h, level.ID)
+		for _, tutorial := range level.Tutorials {
+			fmt.Fprintln(h, tutorial.ID)
+		}
+	}
+	sum := h.Sum(nil)
+
+	// Format the hash as a version 5 style UUID
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// chapterFile returns the file name used for the chapter at index i
+func chapterFile(i int) string {
+	return fmt.Sprintf("chapter-%02d.xhtml", i+1)
+}
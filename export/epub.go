@@ -0,0 +1,222 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"html"
+	htmltemplate "html/template"
+	"io"
+	"text/template"
+	"time"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/highlight"
+)
+
+// epubTemplates holds the XML documents that make up an EPUB 3 package
+var epubTemplates = template.Must(template.New("epub").Funcs(template.FuncMap{
+	"esc":     html.EscapeString,
+	"xhtml":   func(h htmltemplate.HTML) string { return toXHTML(string(h)) },
+	"code":    renderCode,
+	"chapter": chapterFile,
+	"iso":     func(t time.Time) string { return t.UTC().Format("2006-01-02T15:04:05Z") },
+}).Parse(`
+{{define "container"}}<?xml version="1.0" encoding="UTF-8"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+  <rootfiles>
+    <rootfile full-path="OEBPS/package.opf" media-type="application/oebps-package+xml"/>
+  </rootfiles>
+</container>
+{{end}}
+
+{{define "package"}}<?xml version="1.0" encoding="UTF-8"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0" unique-identifier="book-id" xml:lang="{{esc .Language}}">
+  <metadata xmlns:dc="http://purl.org/dc/elements/1.1/">
+    <dc:identifier id="book-id">{{esc .Identifier}}</dc:identifier>
+    <dc:title>{{esc .Title}}</dc:title>
+    <dc:creator>{{esc .Author}}</dc:creator>
+    <dc:language>{{esc .Language}}</dc:language>
+    <meta property="dcterms:modified">{{iso .Modified}}</meta>
+  </metadata>
+  <manifest>
+    <item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+    <item id="style" href="style.css" media-type="text/css"/>
+    {{- range $i, $level := .Levels}}
+    <item id="chapter-{{$i}}" href="{{chapter $i}}" media-type="application/xhtml+xml"/>
+    {{- end}}
+  </manifest>
+  <spine>
+    {{- range $i, $level := .Levels}}
+    <itemref idref="chapter-{{$i}}"/>
+    {{- end}}
+  </spine>
+</package>
+{{end}}
+
+{{define "nav"}}<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{esc .Language}}" lang="{{esc .Language}}">
+<head>
+  <title>{{esc .Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <nav epub:type="toc" id="toc">
+    <h1>Contents</h1>
+    <ol>
+      {{- range $i, $level := .Levels}}
+      <li><a href="{{chapter $i}}">{{esc $level.Title}}</a>
+        <ol>
+          {{- range $level.Tutorials}}
+          <li><a href="{{chapter $i}}#{{esc .ID}}">{{esc .Title}}</a></li>
+          {{- end}}
+        </ol>
+      </li>
+      {{- end}}
+    </ol>
+  </nav>
+</body>
+</html>
+{{end}}
+
+{{define "chapter"}}<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE html>
+<html xmlns="http://www.w3.org/1999/xhtml" xmlns:epub="http://www.idpf.org/2007/ops" xml:lang="{{esc .Language}}" lang="{{esc .Language}}">
+<head>
+  <title>{{esc .Level.Title}}</title>
+  <link rel="stylesheet" type="text/css" href="style.css"/>
+</head>
+<body>
+  <section epub:type="chapter">
+    <h1>{{esc .Level.Title}}</h1>
+    <p class="level">{{esc .Level.Difficulty}}</p>
+    {{- range .Level.Tutorials}}
+    <section class="tutorial" id="{{esc .ID}}">
+      <h2>{{esc .Title}}</h2>
+      <div class="description">{{xhtml .Description}}</div>
+      {{- range .Code}}
+      <div class="code-example">
+        <p class="filename">{{esc .Filename}}</p>
+        {{code .}}
+      </div>
+      {{- end}}
+      <div class="explanation">{{xhtml .Explanation}}</div>
+    </section>
+    {{- end}}
+  </section>
+</body>
+</html>
+{{end}}
+`))
+
+// epubStyle is the stylesheet embedded in the EPUB package
+const epubStyle = `body { font-family: serif; line-height: 1.5; margin: 0 1em; }
+h1, h2, h4 { font-family: sans-serif; }
+h2 { margin-top: 2em; }
+.level { font-style: italic; color: #555; }
+pre { font-family: monospace; font-size: 0.8em; white-space: pre-wrap; word-wrap: break-word;
+      background: #f5f5f5; border: 1px solid #ddd; padding: 0.5em; page-break-inside: avoid; }
+code { font-family: monospace; }
+.filename { font-family: monospace; font-weight: bold; margin: 1em 0 0; }
+.line.highlighted { background: #fff3c4; }
+.tok-keyword { color: #8b3fa8; font-weight: bold; }
+.tok-builtin { color: #9a6a00; }
+.tok-function { color: #1f5fa8; }
+.tok-string, .tok-attr-value { color: #3a7d1f; }
+.tok-number, .tok-attr-name { color: #a85a1f; }
+.tok-comment { color: #6a737d; font-style: italic; }
+.tok-tag, .tok-variable, .tok-property { color: #b3263a; }
+.tok-action { color: #8b3fa8; }
+h2 { page-break-after: avoid; }
+`
+
+// renderCode highlights a code block using classes from the embedded stylesheet
+func renderCode(block content.CodeBlock) string {
+	opts := highlight.Options{Language: block.Language}
+	src, lines := highlight.Prepare(block.Source, block.Highlight)
+	opts.Highlight = lines
+	return highlight.Render(src, opts)
+}
+
+// chapterData is passed to the chapter template for each level
+type chapterData struct {
+	Language string
+	Level    content.Level
+}
+
+// WriteEPUB writes the book as an EPUB 3 package to w
+func (b Book) WriteEPUB(w io.Writer) error {
+	zw := zip.NewWriter(w)
+
+	// The mimetype entry must come first and be stored without compression
+	mimetype, err := zw.CreateHeader(&zip.FileHeader{Name: "mimetype", Method: zip.Store})
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(mimetype, "application/epub+zip"); err != nil {
+		return err
+	}
+
+	if err := writeTemplate(zw, "META-INF/container.xml", "container", nil); err != nil {
+		return err
+	}
+	if err := writeTemplate(zw, "OEBPS/package.opf", "package", b); err != nil {
+		return err
+	}
+	if err := writeTemplate(zw, "OEBPS/nav.xhtml", "nav", b); err != nil {
+		return err
+	}
+
+	style, err := zw.Create("OEBPS/style.css")
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(style, epubStyle); err != nil {
+		return err
+	}
+
+	for i, level := range b.Levels {
+		data := chapterData{Language: b.Language, Level: level}
+		if err := writeTemplate(zw, "OEBPS/"+chapterFile(i), "chapter", data); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+// writeTemplate renders the named template into a new compressed zip entry.
+// Reading systems reject documents that are not well-formed, so the export
+// fails rather than writing one.
+func writeTemplate(zw *zip.Writer, name, tmpl string, data interface{}) error {
+	var buf bytes.Buffer
+	if err := epubTemplates.ExecuteTemplate(&buf, tmpl, data); err != nil {
+		return err
+	}
+	if err := wellFormed(buf.Bytes()); err != nil {
+		return fmt.Errorf("export: %s is not well-formed: %w", name, err)
+	}
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(buf.Bytes())
+	return err
+}
+
+// wellFormed checks that a document is well-formed XML
+func wellFormed(doc []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(doc))
+	dec.Strict = true
+	for {
+		_, err := dec.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
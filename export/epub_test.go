@@ -0,0 +1,113 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/content"
+)
+
+func TestWriteEPUB(t *testing.T) {
+	book := NewBook(content.GetLevels())
+
+	var buf bytes.Buffer
+	if err := book.WriteEPUB(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// Downloading the same content twice gives the same file
+	var again bytes.Buffer
+	if err := NewBook(content.GetLevels()).WriteEPUB(&again); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), again.Bytes()) {
+		t.Error("exporting the same content twice gave different files")
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The mimetype entry must be first and uncompressed for readers to detect the format
+	first := zr.File[0]
+	if first.Name != "mimetype" || first.Method != zip.Store {
+		t.Fatalf("first entry = %s (method %d), want stored mimetype", first.Name, first.Method)
+	}
+
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	required := []string{"META-INF/container.xml", "OEBPS/package.opf", "OEBPS/nav.xhtml", "OEBPS/style.css"}
+	for i := range book.Levels {
+		required = append(required, "OEBPS/"+chapterFile(i))
+	}
+
+	for _, name := range required {
+		f, ok := files[name]
+		if !ok {
+			t.Errorf("missing %s", name)
+			continue
+		}
+		if strings.HasSuffix(name, ".css") {
+			continue
+		}
+
+		// Every document must be well-formed XML
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec := xml.NewDecoder(rc)
+		dec.Strict = true
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Errorf("%s is not well-formed: %v", name, err)
+				break
+			}
+		}
+		rc.Close()
+	}
+}
+
+func TestToXHTML(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`line<br>break`, `line<br />break`},
+		{`<img src="a.png">`, `<img src="a.png" />`},
+		{`Tom & Jerry &amp; friends`, `Tom &amp; Jerry &amp; friends`},
+		{`<code>x := <-ch</code>`, `<code>x := &lt;-ch</code>`},
+		{`<details open><summary>More</summary>x</details>`, `<details open="open"><summary>More</summary>x</details>`},
+		{`<ol start=3 class='steps'><li>one<li>two</ol>`, `<ol start="3" class="steps"><li>one</li><li>two</li></ol>`},
+		{`<p>first<p>second<ul><li>item</ul>`, `<p>first</p><p>second</p><ul><li>item</li></ul>`},
+		{`<p><b>bold</p></b>&nbsp;&copy;<!-- note -->`, `<p><b>bold</b></p>&#160;©`},
+		{`<a title="a &quot;b&quot;" href=x>y`, `<a title="a &#34;b&#34;" href="x">y</a>`},
+	}
+
+	for _, tt := range tests {
+		if got := toXHTML(tt.in); got != tt.want {
+			t.Errorf("toXHTML(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWellFormed(t *testing.T) {
+	if err := wellFormed([]byte(`<p><b>x</b>&#160;</p>`)); err != nil {
+		t.Errorf("well-formed document rejected: %v", err)
+	}
+	for _, doc := range []string{`<p><b>x</p></b>`, `<details open>x</details>`, `<p>&nbsp;</p>`} {
+		if wellFormed([]byte(doc)) == nil {
+			t.Errorf("%s accepted", doc)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package export
+
+import (
+	"html"
+	"strings"
+
+	"golang-webserver-tutorial/htmltag"
+)
+
+// voidElements have no content and are self-closed in XHTML
+var voidElements = map[string]bool{
+	"area": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// impliedEnd lists the open elements a start tag closes, as browsers do when
+// an end tag is left out, and the elements that stop the search
+var impliedEnd = map[string]struct{ closes, stops []string }{
+	"li": {[]string{"li"}, []string{"ul", "ol"}},
+	"dt": {[]string{"dt", "dd"}, []string{"dl"}},
+	"dd": {[]string{"dt", "dd"}, []string{"dl"}},
+	"tr": {[]string{"tr", "td", "th"}, []string{"table", "thead", "tbody", "tfoot"}},
+	"td": {[]string{"td", "th"}, []string{"tr", "table"}},
+	"th": {[]string{"td", "th"}, []string{"tr", "table"}},
+}
+
+// closesParagraph are the start tags that end an open paragraph
+var closesParagraph = map[string]bool{
+	"address": true, "blockquote": true, "details": true, "div": true, "dl": true,
+	"figure": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"hr": true, "ol": true, "p": true, "pre": true, "table": true, "ul": true,
+}
+
+// paragraphScope are the elements a paragraph cannot be closed across
+var paragraphScope = []string{"table", "td", "th", "caption", "button"}
+
+// textEscaper escapes text for XHTML, which only knows the XML entities
+var textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\u00a0", "&#160;")
+
+// attrEscaper escapes a double-quoted attribute value
+var attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&#34;", "\u00a0", "&#160;")
+
+// toXHTML converts an HTML fragment from the content package into well-formed
+// XHTML suitable for an EPUB content document. The fragment is parsed tag by
+// tag: boolean and unquoted attributes are given quoted values, void elements
+// are self-closed, end tags that were left out are added and stray end tags
+// and comments are dropped.
+func toXHTML(fragment string) string {
+	var b strings.Builder
+	var open []string
+	s := fragment
+	for s != "" {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			i = len(s)
+		}
+		b.WriteString(textEscaper.Replace(xmlChars(html.UnescapeString(s[:i]))))
+		s = s[i:]
+		if s == "" {
+			break
+		}
+
+		if rest, ok := htmltag.SkipComment(s); ok {
+			s = rest
+			continue
+		}
+		t, n, ok := htmltag.Parse(s)
+		if !ok || !xmlName(t.Name) {
+			// Not a tag, so it is text
+			b.WriteString("&lt;")
+			s = s[1:]
+			continue
+		}
+		s = s[n:]
+
+		if t.End {
+			if !voidElements[t.Name] {
+				open = closeElements(&b, open, []string{t.Name}, nil)
+			}
+			continue
+		}
+		if closesParagraph[t.Name] {
+			open = closeElements(&b, open, []string{"p"}, paragraphScope)
+		}
+		if implied, ok := impliedEnd[t.Name]; ok {
+			open = closeElements(&b, open, implied.closes, implied.stops)
+		}
+
+		b.WriteString("<" + t.Name)
+		seen := make(map[string]bool)
+		for _, a := range t.Attrs {
+			if seen[a.Name] || !xmlName(a.Name) {
+				continue
+			}
+			seen[a.Name] = true
+			value := a.Value
+			if !a.HasValue {
+				value = a.Name
+			}
+			b.WriteString(" " + a.Name + `="` + attrEscaper.Replace(xmlChars(value)) + `"`)
+		}
+		if voidElements[t.Name] {
+			b.WriteString(" />")
+			continue
+		}
+		b.WriteString(">")
+		open = append(open, t.Name)
+	}
+	for i := len(open) - 1; i >= 0; i-- {
+		b.WriteString("</" + open[i] + ">")
+	}
+	return b.String()
+}
+
+// closeElements closes the innermost open element named in names, and every
+// element opened inside it, unless an element named in stops is found first
+func closeElements(b *strings.Builder, open, names, stops []string) []string {
+	for i := len(open) - 1; i >= 0; i-- {
+		if contains(stops, open[i]) {
+			return open
+		}
+		if contains(names, open[i]) {
+			for j := len(open) - 1; j >= i; j-- {
+				b.WriteString("</" + open[j] + ">")
+			}
+			return open[:i]
+		}
+	}
+	return open
+}
+
+// xmlChars drops characters that XML does not allow, such as most control
+// characters
+func xmlChars(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\t', r == '\n', r == '\r',
+			0x20 <= r && r <= 0xD7FF, 0xE000 <= r && r <= 0xFFFD, 0x10000 <= r && r <= 0x10FFFF:
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// xmlName reports whether an HTML tag or attribute name is also an XML name
+// without a namespace prefix
+func xmlName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case htmltag.IsLetter(c), c == '_':
+		case i > 0 && ('0' <= c && c <= '9' || c == '-' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return name != ""
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
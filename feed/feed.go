@@ -0,0 +1,62 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"sort"
+	"time"
+)
+
+// Feed is a format-independent description of a syndication feed
+type Feed struct {
+	ID          string
+	Title       string
+	Description string
+	Link        string
+	Self        string
+	Author      string
+	Entries     []Entry
+}
+
+// Entry is a single item in a feed
+type Entry struct {
+	ID        string
+	Title     string
+	Link      string
+	Summary   string
+	Content   string
+	Published time.Time
+	Updated   time.Time
+}
+
+// Updated returns the most recent update time of any entry
+func (f Feed) Updated() time.Time {
+	var latest time.Time
+	for _, entry := range f.Entries {
+		if entry.Updated.After(latest) {
+			latest = entry.Updated
+		}
+	}
+	return latest
+}
+
+// SortByUpdated orders the entries from most to least recently updated
+func (f *Feed) SortByUpdated() {
+	sort.SliceStable(f.Entries, func(i, j int) bool {
+		return f.Entries[i].Updated.After(f.Entries[j].Updated)
+	})
+}
+
+// writeXML writes the XML declaration followed by the encoded document
+func writeXML(w io.Writer, doc interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,93 @@
+package feed
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testFeed() Feed {
+	return Feed{
+		ID:    "tag:example.com,2025:feed",
+		Title: "Test Feed",
+		Link:  "http://example.com/",
+		Self:  "http://example.com/feed.atom",
+		Entries: []Entry{
+			{ID: "old", Title: "Old", Link: "http://example.com/old", Summary: "<p>old</p>",
+				Published: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Updated: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+			{ID: "new", Title: "New", Link: "http://example.com/new", Content: "<p>new & shiny</p>",
+				Published: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Updated: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+}
+
+func TestWriteAtom(t *testing.T) {
+	f := testFeed()
+	f.SortByUpdated()
+
+	var buf bytes.Buffer
+	if err := WriteAtom(&buf, f); err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed atomFeed
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("invalid Atom document: %v", err)
+	}
+	if parsed.Updated != "2025-02-01T00:00:00Z" {
+		t.Errorf("feed updated = %s, want latest entry update", parsed.Updated)
+	}
+	if len(parsed.Entries) != 2 || parsed.Entries[0].ID != "new" {
+		t.Fatalf("entries not sorted by update time: %+v", parsed.Entries)
+	}
+	if parsed.Entries[0].Content == nil || parsed.Entries[0].Content.Body != "<p>new & shiny</p>" {
+		t.Errorf("content was not round-tripped: %+v", parsed.Entries[0].Content)
+	}
+}
+
+func TestWriteRSS(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteRSS(&buf, testFeed()); err != nil {
+		t.Fatal(err)
+	}
+
+	var parsed rssDocument
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("invalid RSS document: %v", err)
+	}
+	if got := len(parsed.Channel.Items); got != 2 {
+		t.Fatalf("got %d items, want 2", got)
+	}
+	if parsed.Channel.Items[0].Description != "<p>old</p>" {
+		t.Errorf("summary should be used when there is no content, got %q", parsed.Channel.Items[0].Description)
+	}
+}
+
+func TestWriteSitemapAndRobots(t *testing.T) {
+	var buf bytes.Buffer
+	urls := []URL{
+		{Loc: "http://example.com/", LastMod: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{Loc: "http://example.com/about"},
+	}
+	if err := WriteSitemap(&buf, urls); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, "<lastmod>2025-03-04</lastmod>") {
+		t.Errorf("missing lastmod in sitemap:\n%s", out)
+	}
+	if strings.Count(out, "<lastmod>") != 1 {
+		t.Errorf("lastmod should be omitted when unknown:\n%s", out)
+	}
+
+	buf.Reset()
+	if err := WriteRobots(&buf, "http://example.com/sitemap.xml", []string{"/download/"}); err != nil {
+		t.Fatal(err)
+	}
+	want := "User-agent: *\nDisallow: /download/\n\nSitemap: http://example.com/sitemap.xml\n"
+	if buf.String() != want {
+		t.Errorf("robots.txt = %q, want %q", buf.String(), want)
+	}
+}
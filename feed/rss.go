@@ -0,0 +1,78 @@
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// rssDocument is the XML representation of an RSS 2.0 feed
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Self          rssSelf   `xml:"atom:link"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssSelf struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+// WriteRSS writes the feed as an RSS 2.0 document
+func WriteRSS(w io.Writer, f Feed) error {
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         f.Title,
+			Link:          f.Link,
+			Description:   f.Description,
+			LastBuildDate: f.Updated().Format(time.RFC1123Z),
+			Self:          rssSelf{Href: f.Self, Rel: "self", Type: "application/rss+xml"},
+		},
+	}
+
+	for _, entry := range f.Entries {
+		// RSS has no separate summary, so prefer the full content when present
+		description := entry.Content
+		if description == "" {
+			description = entry.Summary
+		}
+
+		// RSS has no update date either, so report the update time as pubDate to
+		// let readers pick up revised entries
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       entry.Title,
+			Link:        entry.Link,
+			GUID:        rssGUID{IsPermaLink: false, Value: entry.ID},
+			PubDate:     entry.Updated.Format(time.RFC1123Z),
+			Description: description,
+		})
+	}
+
+	return writeXML(w, doc)
+}
@@ -0,0 +1,57 @@
+package feed
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// URL is a single location listed in a sitemap
+type URL struct {
+	Loc     string
+	LastMod time.Time
+}
+
+type sitemapDocument struct {
+	XMLName xml.Name     `xml:"http://www.sitemaps.org/schemas/sitemap/0.9 urlset"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// WriteSitemap writes the URLs as a sitemap protocol document
+func WriteSitemap(w io.Writer, urls []URL) error {
+	var doc sitemapDocument
+	for _, u := range urls {
+		entry := sitemapURL{Loc: u.Loc}
+		if !u.LastMod.IsZero() {
+			entry.LastMod = u.LastMod.Format("2006-01-02")
+		}
+		doc.URLs = append(doc.URLs, entry)
+	}
+	return writeXML(w, doc)
+}
+
+// WriteRobots writes a robots.txt that allows crawling everything except the
+// disallowed path prefixes and points crawlers at the sitemap
+func WriteRobots(w io.Writer, sitemap string, disallow []string) error {
+	if _, err := fmt.Fprintln(w, "User-agent: *"); err != nil {
+		return err
+	}
+	for _, path := range disallow {
+		if _, err := fmt.Fprintf(w, "Disallow: %s\n", path); err != nil {
+			return err
+		}
+	}
+	if len(disallow) == 0 {
+		if _, err := fmt.Fprintln(w, "Disallow:"); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "\nSitemap: %s\n", sitemap)
+	return err
+}
@@ -0,0 +1,284 @@
+// Package grader checks exercise submissions by running the exercise's hidden
+// tests against them with go test in a sandbox.
+package grader
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/sandbox"
+)
+
+// MaxSourceSize is the largest submission accepted, in bytes
+const MaxSourceSize = 64 << 10
+
+// ErrTooLarge is returned for submissions over MaxSourceSize
+var ErrTooLarge = errors.New("grader: submission is too large")
+
+// TestResult is the outcome of one test function
+type TestResult struct {
+	Name    string  `json:"name"`
+	Passed  bool    `json:"passed"`
+	Skipped bool    `json:"skipped,omitempty"`
+	Output  string  `json:"output,omitempty"`
+	Elapsed float64 `json:"elapsed"`
+}
+
+// Report is the result of grading a submission
+type Report struct {
+	Tests []TestResult `json:"tests"`
+
+	// BuildOutput holds compiler errors and anything else go test printed
+	// outside a test
+	BuildOutput string `json:"build_output,omitempty"`
+
+	Passed    bool `json:"passed"`
+	TimedOut  bool `json:"timed_out,omitempty"`
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// PassedCount returns how many tests passed
+func (r Report) PassedCount() int {
+	n := 0
+	for _, test := range r.Tests {
+		if test.Passed {
+			n++
+		}
+	}
+	return n
+}
+
+// Files the hidden TestMain uses to prove the tests ran to completion
+const (
+	nonceFile  = ".grader-nonce"
+	resultFile = ".grader-result"
+)
+
+// testMain is added to every exercise. It writes the per-run nonce and the
+// tests' exit code to resultFile once they have all finished, which a
+// submission that prints fake results and exits early never reaches.
+const testMain = `package %s
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	nonce, err := os.ReadFile(%q)
+	os.Remove(%q)
+	code := m.Run()
+	if err == nil {
+		os.WriteFile(%q, []byte(fmt.Sprintf("%%s %%d", nonce, code)), 0600)
+	}
+	os.Exit(code)
+}
+`
+
+// Grade runs the exercise's hidden tests against source. The tests are
+// compiled first and their sources removed before the test binary runs, and
+// the submission only passes if the hidden TestMain reports success with this
+// run's nonce and every hidden test ran and passed.
+func Grade(ctx context.Context, sb *sandbox.Sandbox, ex *content.Exercise, source string) (Report, error) {
+	if len(source) > MaxSourceSize {
+		return Report{}, ErrTooLarge
+	}
+	pkg, hidden, err := hiddenTests(ex)
+	if err != nil {
+		return Report{}, err
+	}
+
+	files := map[string]string{
+		ex.Filename:           source,
+		"grader_main_test.go": fmt.Sprintf(testMain, pkg, nonceFile, nonceFile, resultFile),
+	}
+	for _, test := range ex.Tests {
+		files[test.Name] = test.Source
+	}
+	m, err := sb.NewModule("exercise", files)
+	if err != nil {
+		return Report{}, err
+	}
+	defer m.Close()
+
+	var build strings.Builder
+	result, err := sb.Go(ctx, m, sandbox.DefaultLimits, &build, &build, "test", "-c", "-o", "exercise.test", ".")
+	if err != nil {
+		return Report{}, err
+	}
+	if result.ExitCode != 0 || result.TimedOut {
+		return Report{
+			Tests:       []TestResult{},
+			BuildOutput: strings.TrimSpace(build.String()),
+			TimedOut:    result.TimedOut,
+			Truncated:   result.Truncated,
+		}, nil
+	}
+
+	// The submission cannot read the hidden tests while it runs, and only
+	// learns the nonce once the tests start
+	for name := range files {
+		if name != ex.Filename {
+			if err := os.Remove(filepath.Join(m.Dir, name)); err != nil {
+				return Report{}, err
+			}
+		}
+	}
+	nonce, err := newNonce()
+	if err != nil {
+		return Report{}, err
+	}
+	if err := os.WriteFile(filepath.Join(m.Dir, nonceFile), []byte(nonce), 0600); err != nil {
+		return Report{}, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	result, err = sb.Go(ctx, m, sandbox.DefaultLimits, &stdout, &stderr, "tool", "test2json", "./exercise.test", "-test.v=test2json")
+	if err != nil {
+		return Report{}, err
+	}
+
+	report := parseTestJSON(&stdout)
+	report.BuildOutput = strings.TrimSpace(stderr.String() + report.BuildOutput)
+	report.TimedOut = result.TimedOut
+	report.Truncated = result.Truncated
+	report.Tests = onlyHidden(report.Tests, hidden)
+
+	completed, _ := os.ReadFile(filepath.Join(m.Dir, resultFile))
+	report.Passed = result.ExitCode == 0 && !result.TimedOut && string(completed) == nonce+" 0"
+	for _, test := range report.Tests {
+		if !test.Passed && !test.Skipped {
+			report.Passed = false
+		}
+	}
+	return report, nil
+}
+
+// hiddenTests returns the package of the exercise's tests and the names of
+// their test functions
+func hiddenTests(ex *content.Exercise) (string, []string, error) {
+	var pkg string
+	var names []string
+	fset := token.NewFileSet()
+	for _, test := range ex.Tests {
+		f, err := parser.ParseFile(fset, test.Name, test.Source, 0)
+		if err != nil {
+			return "", nil, err
+		}
+		pkg = f.Name.Name
+		for _, decl := range f.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Recv == nil && strings.HasPrefix(fn.Name.Name, "Test") && fn.Name.Name != "TestMain" {
+				names = append(names, fn.Name.Name)
+			}
+		}
+	}
+	if len(names) == 0 {
+		return "", nil, errors.New("grader: exercise has no tests")
+	}
+	return pkg, names, nil
+}
+
+// onlyHidden keeps the results of the hidden tests and their subtests, and
+// adds a failed result for every hidden test that never reported one
+func onlyHidden(tests []TestResult, hidden []string) []TestResult {
+	seen := make(map[string]bool)
+	var kept []TestResult
+	for _, test := range tests {
+		top := strings.SplitN(test.Name, "/", 2)[0]
+		for _, name := range hidden {
+			if top == name {
+				kept = append(kept, test)
+				seen[test.Name] = true
+				break
+			}
+		}
+	}
+	for _, name := range hidden {
+		if !seen[name] {
+			kept = append(kept, TestResult{Name: name, Output: "test did not run to completion\n"})
+		}
+	}
+	return kept
+}
+
+// newNonce returns a random value that identifies one grading run
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// testEvent is one line of go test -json output (see go doc test2json)
+type testEvent struct {
+	Action  string
+	Test    string
+	Output  string
+	Elapsed float64
+}
+
+// parseTestJSON collects per-test results from go test -json output. Lines
+// that are not JSON, such as compiler errors from older toolchains, and
+// build-output events are gathered into BuildOutput.
+func parseTestJSON(r io.Reader) Report {
+	report := Report{Tests: []TestResult{}}
+	var build strings.Builder
+	index := make(map[string]int)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var event testEvent
+		if len(line) == 0 || line[0] != '{' || json.Unmarshal(line, &event) != nil {
+			build.Write(line)
+			build.WriteByte('\n')
+			continue
+		}
+
+		if event.Action == "build-output" {
+			build.WriteString(event.Output)
+			continue
+		}
+		if event.Test == "" {
+			continue
+		}
+
+		i, ok := index[event.Test]
+		if !ok {
+			i = len(report.Tests)
+			index[event.Test] = i
+			report.Tests = append(report.Tests, TestResult{Name: event.Test})
+		}
+		test := &report.Tests[i]
+		switch event.Action {
+		case "output":
+			test.Output += event.Output
+		case "pass":
+			test.Passed, test.Elapsed = true, event.Elapsed
+		case "fail":
+			test.Elapsed = event.Elapsed
+		case "skip":
+			test.Skipped, test.Elapsed = true, event.Elapsed
+		}
+	}
+	report.BuildOutput = build.String()
+	return report
+}
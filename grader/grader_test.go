@@ -0,0 +1,134 @@
+package grader
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/sandbox"
+)
+
+func TestParseTestJSON(t *testing.T) {
+	out := `{"Action":"run","Test":"TestA"}
+{"Action":"output","Test":"TestA","Output":"=== RUN   TestA\n"}
+{"Action":"pass","Test":"TestA","Elapsed":0.01}
+{"Action":"run","Test":"TestB"}
+{"Action":"output","Test":"TestB","Output":"    main_test.go:9: body = \"Hello!\"\n"}
+{"Action":"fail","Test":"TestB","Elapsed":0.02}
+{"Action":"output","Output":"FAIL\n"}
+{"Action":"fail","Elapsed":0.03}
+./main.go:3:2: "os" imported and not used
+`
+	report := parseTestJSON(strings.NewReader(out))
+	if len(report.Tests) != 2 || !report.Tests[0].Passed || report.Tests[1].Passed {
+		t.Fatalf("tests = %+v", report.Tests)
+	}
+	if !strings.Contains(report.Tests[1].Output, `body = "Hello!"`) {
+		t.Errorf("failure output = %q", report.Tests[1].Output)
+	}
+	if !strings.Contains(report.BuildOutput, "imported and not used") {
+		t.Errorf("build output = %q", report.BuildOutput)
+	}
+	if report.PassedCount() != 1 {
+		t.Errorf("passed count = %d", report.PassedCount())
+	}
+}
+
+// TestExercises checks every exercise's reference solution passes its hidden
+// tests and the starter code compiles but does not
+func TestExercises(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles Go code")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox requires Linux")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	sb := sandbox.New(sandbox.DefaultCacheDir(), 1)
+	if err := sb.Warm(context.Background(), sandbox.WarmImports); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, level := range content.GetLevels() {
+		for _, tutorial := range level.Tutorials {
+			ex := tutorial.Exercise
+			if ex == nil {
+				continue
+			}
+
+			report, err := Grade(context.Background(), sb, ex, ex.Solution)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !report.Passed {
+				t.Errorf("%s: solution failed: %+v", tutorial.ID, report)
+			}
+
+			report, err = Grade(context.Background(), sb, ex, ex.Starter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.Passed || report.BuildOutput != "" || len(report.Tests) == 0 {
+				t.Errorf("%s: starter should compile and fail: %+v", tutorial.ID, report)
+			}
+		}
+	}
+}
+
+// TestForgedResults checks a submission that prints passing test output and
+// exits before the tests run is not graded as passed
+func TestForgedResults(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles Go code")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox requires Linux")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	sb := sandbox.New(sandbox.DefaultCacheDir(), 1)
+	if err := sb.Warm(context.Background(), sandbox.WarmImports); err != nil {
+		t.Fatal(err)
+	}
+
+	tutorial, _, ok := content.FindTutorial("hello-world")
+	if !ok || tutorial.Exercise == nil {
+		t.Skip("no exercise to forge")
+	}
+	forged := `package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+func init() {
+	if strings.HasSuffix(os.Args[0], ".test") {
+		for _, name := range []string{"TestGreetName", "TestGreetDefault"} {
+			fmt.Printf("\x16=== RUN   %s\n\x16--- PASS: %s (0.00s)\n", name, name)
+		}
+		fmt.Println("\x16PASS")
+		os.Exit(0)
+	}
+}
+
+func main() {}
+
+func greet(w http.ResponseWriter, r *http.Request) { fmt.Fprint(w, "wrong") }
+`
+	report, err := Grade(context.Background(), sb, tutorial.Exercise, forged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed {
+		t.Errorf("forged results were graded as passed: %+v", report)
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/auth"
+)
+
+// localRedirect returns next if it is a path on this site, or fallback otherwise,
+// so login links cannot be used to send people to other sites
+func localRedirect(next, fallback string) string {
+	if strings.HasPrefix(next, "/") && !strings.HasPrefix(next, "//") && !strings.HasPrefix(next, "/\\") {
+		return next
+	}
+	return fallback
+}
+
+// RegisterHandler shows the sign-up form and creates new accounts
+func RegisterHandler(w http.ResponseWriter, r *http.Request) {
+	data := TemplateData{
+		Title:       "Create an Account",
+		ActiveNav:   "account",
+		CurrentYear: time.Now().Year(),
+		Form:        r.URL.Query(),
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		data.Form = r.PostForm
+
+		username := r.PostForm.Get("username")
+		password := r.PostForm.Get("password")
+		if password != r.PostForm.Get("confirm") {
+			data.Error = "The passwords do not match."
+		} else {
+			user, err := Accounts.Register(username, password)
+			switch {
+			case err == nil:
+				if _, err := Accounts.Login(w, r, user); err != nil {
+					log.Printf("login after registration failed: %v", err)
+					http.Error(w, "Could not start a session", http.StatusInternalServerError)
+					return
+				}
+				mergeAnonymousProgress(w, r, user)
+				http.Redirect(w, r, localRedirect(r.PostForm.Get("next"), "/"), http.StatusSeeOther)
+				return
+			case errors.Is(err, auth.ErrUserExists):
+				data.Error = "That username is already taken."
+			case errors.Is(err, auth.ErrInvalidUsername), errors.Is(err, auth.ErrWeakPassword):
+				data.Error = strings.ToUpper(err.Error()[:1]) + err.Error()[1:] + "."
+			default:
+				log.Printf("registration failed: %v", err)
+				data.Error = "Something went wrong, please try again."
+			}
+		}
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	} else if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parseTemplate(w, r, data, "templates/register.html")
+}
+
+// LoginHandler shows the login form and starts sessions
+func LoginHandler(w http.ResponseWriter, r *http.Request) {
+	data := TemplateData{
+		Title:       "Log In",
+		ActiveNav:   "account",
+		CurrentYear: time.Now().Year(),
+		Form:        r.URL.Query(),
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		data.Form = r.PostForm
+
+		user, err := Accounts.Authenticate(r.PostForm.Get("username"), r.PostForm.Get("password"))
+		if err == nil {
+			if _, err := Accounts.Login(w, r, user); err != nil {
+				log.Printf("login failed: %v", err)
+				http.Error(w, "Could not start a session", http.StatusInternalServerError)
+				return
+			}
+			mergeAnonymousProgress(w, r, user)
+			http.Redirect(w, r, localRedirect(r.PostForm.Get("next"), "/"), http.StatusSeeOther)
+			return
+		}
+
+		if !errors.Is(err, auth.ErrInvalidCredentials) {
+			log.Printf("authentication failed: %v", err)
+		}
+		data.Error = "Invalid username or password."
+		w.WriteHeader(http.StatusUnauthorized)
+	} else if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parseTemplate(w, r, data, "templates/login.html")
+}
+
+// LogoutHandler ends the current session. It only accepts POST so that
+// links and prefetching cannot log people out.
+func LogoutHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := Accounts.Logout(w, r); err != nil {
+		log.Printf("logout failed: %v", err)
+	}
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
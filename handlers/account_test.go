@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang-webserver-tutorial/auth"
+)
+
+func TestLocalRedirect(t *testing.T) {
+	tests := map[string]string{
+		"/basic":               "/basic",
+		"/basic?x=1":           "/basic?x=1",
+		"":                     "/",
+		"//evil.example":       "/",
+		"/\\evil.example":      "/",
+		"https://evil.example": "/",
+	}
+	for next, want := range tests {
+		if got := localRedirect(next, "/"); got != want {
+			t.Errorf("localRedirect(%q) = %q, want %q", next, got, want)
+		}
+	}
+}
+
+func TestLoginAndLogout(t *testing.T) {
+	Accounts = auth.NewManager(auth.NewMemoryUserStore(), auth.NewMemorySessionStore(),
+		auth.Config{Params: auth.ScryptParams{LogN: 4, R: 1, P: 1}})
+	if _, err := Accounts.Register("gopher", "password123"); err != nil {
+		t.Fatal(err)
+	}
+
+	form := url.Values{"username": {"gopher"}, "password": {"password123"}, "next": {"/basic"}}
+	rr := httptest.NewRecorder()
+	LoginHandler(rr, postForm("/login", form))
+
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/basic" {
+		t.Fatalf("login: status %d, location %q", rr.Code, rr.Header().Get("Location"))
+	}
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("login did not set an HttpOnly session cookie: %v", cookies)
+	}
+
+	// Logging out only works with POST
+	get := httptest.NewRequest("GET", "/logout", nil)
+	get.AddCookie(cookies[0])
+	rr = httptest.NewRecorder()
+	LogoutHandler(rr, get)
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET /logout: status %d", rr.Code)
+	}
+	if _, ok := Accounts.CurrentUser(get); !ok {
+		t.Fatal("GET /logout ended the session")
+	}
+
+	post := httptest.NewRequest("POST", "/logout", nil)
+	post.AddCookie(cookies[0])
+	LogoutHandler(httptest.NewRecorder(), post)
+	if _, ok := Accounts.CurrentUser(post); ok {
+		t.Error("session still valid after logout")
+	}
+}
@@ -0,0 +1,500 @@
+package handlers
+
+import (
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/authoring"
+	"golang-webserver-tutorial/content"
+)
+
+// Authoring stores tutorials and examples edited in the admin area. It
+// defaults to an in-memory store; main replaces it with a persistent one.
+// Admin routes are registered with PermAuthor, so the handlers here can
+// assume an author is logged in.
+var Authoring = authoring.NewMemoryStore()
+
+// examplesDir is where example downloads are generated
+var examplesDir = filepath.Join("static", "examples")
+
+// scheduleLayout is the format of datetime-local inputs
+const scheduleLayout = "2006-01-02T15:04"
+
+// adminEditor is the editor page for one tutorial or example
+type adminEditor struct {
+	Kind string
+	New  bool
+	Doc  authoring.Document
+
+	// Entry is the edit history, nil for content never edited here
+	Entry *authoring.Entry
+
+	// Revision is the earlier revision loaded into the form, if any
+	Revision int
+
+	// Required is how many approvals a draft needs to be published
+	Required int
+
+	// CanReplace is whether the user may discard the draft or revert over it
+	CanReplace bool
+
+	Errors    map[string]string
+	Levels    []content.Level
+	Languages []string
+}
+
+// adminBlock is a code block as shown in the editor form
+type adminBlock struct {
+	content.CodeBlock
+	Number   int
+	Callouts string
+	Error    string
+}
+
+// Blocks returns the document's code blocks, plus an empty one for adding a
+// new block
+func (e adminEditor) Blocks() []adminBlock {
+	var blocks []adminBlock
+	if e.Doc.Tutorial != nil {
+		for i, b := range e.Doc.Tutorial.Code {
+			var lines []string
+			for _, c := range b.Callouts {
+				lines = append(lines, strconv.Itoa(c.Line)+": "+string(c.Note))
+			}
+			blocks = append(blocks, adminBlock{
+				CodeBlock: b,
+				Number:    i + 1,
+				Callouts:  strings.Join(lines, "\n"),
+				Error:     e.Errors["code."+strconv.Itoa(i+1)],
+			})
+		}
+	}
+	return append(blocks, adminBlock{CodeBlock: content.CodeBlock{Language: "go"}, Number: len(blocks) + 1})
+}
+
+// URL returns the editor's address
+func (e adminEditor) URL() string {
+	if e.New {
+		return adminURL(e.Kind, "new")
+	}
+	return adminURL(e.Kind, e.Doc.ID())
+}
+
+// ReviewURL returns the address of the draft's review page
+func (e adminEditor) ReviewURL() string {
+	return reviewURL(e.Kind, e.Doc.ID())
+}
+
+// adminItem is a row on the admin dashboard
+type adminItem struct {
+	Kind  string
+	ID    string
+	Title string
+	Level string
+	Entry *authoring.Entry
+}
+
+// URL returns the item's editor address
+func (i adminItem) URL() string {
+	return adminURL(i.Kind, i.ID)
+}
+
+// adminURL returns the editor address for a tutorial or example
+func adminURL(kind, id string) string {
+	return "/admin/" + kind + "s/" + url.PathEscape(id)
+}
+
+// publishContent shows the published content on the site and regenerates
+// the example downloads
+func publishContent() {
+	for _, s := range content.SetPublished(Authoring.Published()) {
+		log.Printf("Sanitized published content: %s", s)
+	}
+	EnsureExamplesGenerated(examplesDir)
+}
+
+// PublishScheduled publishes the drafts scheduled for now or earlier
+func PublishScheduled(now time.Time) {
+	published, err := Authoring.PublishDue(now)
+	if err != nil {
+		log.Printf("scheduled publishing failed: %v", err)
+	}
+	if len(published) > 0 {
+		log.Printf("published %s", strings.Join(published, ", "))
+		publishContent()
+	}
+}
+
+// AdminHandler lists every tutorial and example with its editing status
+func AdminHandler(w http.ResponseWriter, r *http.Request) {
+	entries := make(map[string]authoring.Entry)
+	for _, e := range Authoring.Entries() {
+		entries[authoring.Key(e.Kind, e.ID)] = e
+	}
+	var items []adminItem
+	add := func(item adminItem) {
+		if e, ok := entries[authoring.Key(item.Kind, item.ID)]; ok {
+			item.Entry = &e
+			delete(entries, authoring.Key(item.Kind, item.ID))
+		}
+		items = append(items, item)
+	}
+	for _, level := range content.GetLevels() {
+		for _, t := range level.Tutorials {
+			add(adminItem{Kind: authoring.KindTutorial, ID: t.ID, Title: t.Title, Level: level.Title})
+		}
+	}
+	for _, e := range content.GetCodeExamples() {
+		add(adminItem{Kind: authoring.KindExample, ID: e.Filename, Title: e.Title})
+	}
+	// New content that has not been published yet
+	for _, e := range Authoring.Entries() {
+		if _, ok := entries[authoring.Key(e.Kind, e.ID)]; ok {
+			add(adminItem{Kind: e.Kind, ID: e.ID, Title: e.Latest().Doc.Title()})
+		}
+	}
+
+	data := TemplateData{
+		Title:       "Admin",
+		ActiveNav:   "admin",
+		CurrentYear: time.Now().Year(),
+		AdminItems:  items,
+	}
+	parseTemplate(w, r, data, "templates/admin.html")
+}
+
+// AdminEditHandler shows the editor for a tutorial or example at
+// /admin/tutorials/{id} or /admin/examples/{filename}, and saves, publishes,
+// discards and reverts drafts
+func AdminEditHandler(w http.ResponseWriter, r *http.Request) {
+	kind, id, ok := adminTarget(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	editor := adminEditor{
+		Kind:      kind,
+		New:       id == "new",
+		Required:  Authoring.RequiredApprovals,
+		Levels:    content.GetLevels(),
+		Languages: highlightLanguages,
+	}
+	switch {
+	case !editor.New:
+		if !loadEditor(&editor, id) {
+			http.NotFound(w, r)
+			return
+		}
+	case kind == authoring.KindTutorial:
+		editor.Doc = authoring.Document{Kind: kind, Level: "basic", Tutorial: &content.Tutorial{}}
+	default:
+		editor.Doc = authoring.Document{Kind: kind, Example: &content.CodeExample{}}
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		user, _ := Accounts.CurrentUser(r)
+		status, err := editContent(&editor, r.PostForm, user)
+		if err == nil {
+			target := editor.URL()
+			if _, ok := Authoring.Entry(kind, editor.Doc.ID()); !ok && !contentExists(kind, editor.Doc.ID()) {
+				// A discarded draft of new content is gone
+				target = "/admin"
+			}
+			http.Redirect(w, r, target, http.StatusSeeOther)
+			return
+		}
+		w.WriteHeader(status)
+		editor.Errors = map[string]string{"": err.Error()}
+		var invalid authoring.InvalidError
+		if errors.As(err, &invalid) {
+			editor.Errors = invalid
+		}
+	} else if rev, err := strconv.Atoi(r.URL.Query().Get("rev")); err == nil && editor.Entry != nil {
+		// Show an earlier revision so it can be reverted to or edited
+		if old, ok := editor.Entry.Revision(rev); ok {
+			editor.Doc, editor.Revision = old.Doc, rev
+		}
+	}
+
+	title := "New " + kind
+	if !editor.New {
+		title = "Edit " + editor.Doc.Title()
+	}
+	if user, ok := Accounts.CurrentUser(r); ok {
+		editor.CanReplace = mayReplaceDraft(kind, editor.Doc.ID(), user)
+	}
+	data := TemplateData{
+		Title:       title,
+		ActiveNav:   "admin",
+		CurrentYear: time.Now().Year(),
+		Editor:      &editor,
+	}
+	parseTemplate(w, r, data, "templates/admin_edit.html")
+}
+
+// highlightLanguages are the code block languages offered in the editor
+var highlightLanguages = []string{"go", "gohtml", "html", "json", "shell", "http"}
+
+// adminTarget splits an editor path into the kind and ID of its content
+func adminTarget(path string) (kind, id string, ok bool) {
+	rest := strings.TrimPrefix(path, "/admin/")
+	collection, id, _ := strings.Cut(rest, "/")
+	switch collection {
+	case "tutorials":
+		kind = authoring.KindTutorial
+	case "examples":
+		kind = authoring.KindExample
+	default:
+		return "", "", false
+	}
+	if id == "" || strings.Contains(id, "/") {
+		return "", "", false
+	}
+	return kind, id, true
+}
+
+// loadEditor fills the editor with the latest version of existing content
+func loadEditor(editor *adminEditor, id string) bool {
+	if e, ok := Authoring.Entry(editor.Kind, id); ok {
+		editor.Entry = &e
+		editor.Doc = e.Latest().Doc
+		return true
+	}
+	doc, ok := liveDocument(editor.Kind, id)
+	editor.Doc = doc
+	return ok
+}
+
+// liveDocument returns the tutorial or example visitors currently see. New
+// content gets an empty document.
+func liveDocument(kind, id string) (authoring.Document, bool) {
+	doc := authoring.Document{Kind: kind}
+	if kind == authoring.KindTutorial {
+		if t, level, ok := content.FindTutorial(id); ok {
+			doc.Level, doc.Tutorial = level.ID, &t
+			return doc, true
+		}
+		return doc, false
+	}
+	for _, e := range content.GetCodeExamples() {
+		if e.Filename == id {
+			doc.Example = &e
+			return doc, true
+		}
+	}
+	return doc, false
+}
+
+// editContent carries out the action posted from the editor and returns the
+// status to show the editor again with if it fails
+func editContent(editor *adminEditor, form url.Values, user auth.User) (int, error) {
+	id, author := editor.Doc.ID(), user.Username
+	switch form.Get("action") {
+	case "save":
+		doc := documentFromForm(editor.Kind, form)
+		if editor.New {
+			if _, exists := Authoring.Entry(editor.Kind, doc.ID()); exists || contentExists(editor.Kind, doc.ID()) {
+				editor.Doc = doc
+				return http.StatusConflict, authoring.InvalidError{"id": "is already used"}
+			}
+		} else {
+			// The ID is part of the address and cannot be edited
+			setDocumentID(&doc, id)
+		}
+		editor.Doc = doc
+		if _, err := Authoring.SaveDraft(doc, author, strings.TrimSpace(form.Get("note"))); err != nil {
+			return http.StatusUnprocessableEntity, err
+		}
+		editor.New = false
+		return 0, nil
+
+	case "publish":
+		if !user.Can(auth.PermPublish) {
+			return http.StatusForbidden, errors.New("only reviewers and admins can publish")
+		}
+		at := time.Now()
+		if v := form.Get("publish_at"); v != "" {
+			var err error
+			if at, err = time.ParseInLocation(scheduleLayout, v, time.UTC); err != nil {
+				return http.StatusUnprocessableEntity, authoring.InvalidError{"publish_at": "must be a date and time"}
+			}
+		}
+		if err := Authoring.Publish(editor.Kind, id, at, author); err != nil {
+			return http.StatusConflict, err
+		}
+		publishContent()
+		return 0, nil
+
+	case "discard":
+		if !mayReplaceDraft(editor.Kind, id, user) {
+			return http.StatusForbidden, errors.New("only the draft's author, reviewers and admins can discard it")
+		}
+		if err := Authoring.Discard(editor.Kind, id); err != nil {
+			return http.StatusConflict, err
+		}
+		return 0, nil
+
+	case "revert":
+		if !mayReplaceDraft(editor.Kind, id, user) {
+			return http.StatusForbidden, errors.New("only the draft's author, reviewers and admins can replace it")
+		}
+		rev, _ := strconv.Atoi(form.Get("rev"))
+		if _, err := Authoring.Revert(editor.Kind, id, rev, author); err != nil {
+			return http.StatusNotFound, err
+		}
+		return 0, nil
+	}
+	return http.StatusBadRequest, errors.New("unknown action")
+}
+
+// mayReplaceDraft reports whether user may discard or revert over the
+// pending draft of an entry: its author, or someone who can publish
+func mayReplaceDraft(kind, id string, user auth.User) bool {
+	e, ok := Authoring.Entry(kind, id)
+	if !ok || e.Draft == 0 || user.Can(auth.PermPublish) {
+		return true
+	}
+	draft, _ := e.Revision(e.Draft)
+	return draft.Author == user.Username
+}
+
+// contentExists reports whether there is a tutorial or example with the ID
+func contentExists(kind, id string) bool {
+	if kind == authoring.KindTutorial {
+		_, _, ok := content.FindTutorial(id)
+		return ok
+	}
+	for _, e := range content.GetCodeExamples() {
+		if e.Filename == id {
+			return true
+		}
+	}
+	return false
+}
+
+// setDocumentID sets the tutorial ID or example filename
+func setDocumentID(doc *authoring.Document, id string) {
+	switch {
+	case doc.Tutorial != nil:
+		doc.Tutorial.ID = id
+	case doc.Example != nil:
+		doc.Example.Filename = id
+	}
+}
+
+// documentFromForm builds a document from the editor form. Code blocks are
+// sent as parallel lists; blocks with neither a filename nor source are
+// dropped.
+func documentFromForm(kind string, form url.Values) authoring.Document {
+	trim := func(name string) string { return strings.TrimSpace(form.Get(name)) }
+	doc := authoring.Document{Kind: kind}
+	if kind == authoring.KindExample {
+		doc.Example = &content.CodeExample{
+			Filename:    trim("id"),
+			Title:       trim("title"),
+			Description: template.HTML(form.Get("description")),
+			Code:        form.Get("code"),
+			LivePath:    trim("live_path"),
+		}
+		return doc
+	}
+
+	doc.Level = trim("level")
+	t := &content.Tutorial{
+		ID:            trim("id"),
+		Title:         trim("title"),
+		Description:   template.HTML(form.Get("description")),
+		Explanation:   template.HTML(form.Get("explanation")),
+		Prerequisites: splitList(form.Get("prerequisites")),
+		Topics:        splitList(form.Get("topics")),
+	}
+	filenames, sources := form["filename"], form["source"]
+	for i := range filenames {
+		block := content.CodeBlock{
+			Filename:  strings.TrimSpace(filenames[i]),
+			Language:  formIndex(form, "language", i),
+			Highlight: strings.TrimSpace(formIndex(form, "highlight", i)),
+		}
+		if i < len(sources) {
+			block.Source = sources[i]
+		}
+		if block.Filename == "" && strings.TrimSpace(block.Source) == "" {
+			continue
+		}
+		block.Callouts = parseCallouts(formIndex(form, "callouts", i))
+		t.Code = append(t.Code, block)
+	}
+	doc.Tutorial = t
+	return doc
+}
+
+// formIndex returns the i-th value of a repeated form field
+func formIndex(form url.Values, name string, i int) string {
+	if values := form[name]; i < len(values) {
+		return values[i]
+	}
+	return ""
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseCallouts parses callouts written one per line as "line: note".
+// Lines without a number get line 0, which fails validation.
+func parseCallouts(s string) []content.Callout {
+	var callouts []content.Callout
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line == "" {
+			continue
+		}
+		number, note, _ := strings.Cut(line, ":")
+		n, _ := strconv.Atoi(strings.TrimSpace(number))
+		callouts = append(callouts, content.Callout{Line: n, Note: template.HTML(strings.TrimSpace(note))})
+	}
+	return callouts
+}
+
+// AdminPreviewHandler renders the posted editor form through the site's own
+// templates without saving it
+func AdminPreviewHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	r.ParseForm()
+
+	data := TemplateData{
+		Title:       "Preview",
+		CurrentYear: time.Now().Year(),
+	}
+	// Shown as it will be once published, with what the sanitizer removes
+	doc := documentFromForm(r.PostForm.Get("kind"), r.PostForm)
+	if doc.Example != nil {
+		example, stripped := content.ContentPolicy.SanitizeExample(*doc.Example)
+		data.Examples, data.Stripped = []content.CodeExample{example}, stripped
+		parseTemplate(w, r, data, "templates/examples.html")
+		return
+	}
+	tutorial, stripped := content.ContentPolicy.SanitizeTutorial(*doc.Tutorial)
+	data.Tutorials, data.Stripped = []content.Tutorial{tutorial}, stripped
+	parseTemplate(w, r, data, "templates/admin_preview.html")
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/authoring"
+	"golang-webserver-tutorial/content"
+)
+
+// login registers a user with the given roles and returns their session cookie
+func login(t *testing.T, username string, roles ...auth.Role) *http.Cookie {
+	t.Helper()
+	if _, err := Accounts.Register(username, "password123"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Accounts.SetRoles(username, roles); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	LoginHandler(rr, postForm("/login", url.Values{"username": {username}, "password": {"password123"}}))
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("login %s: cookies %v", username, cookies)
+	}
+	return cookies[0]
+}
+
+func TestAdmin(t *testing.T) {
+	Accounts = auth.NewManager(auth.NewMemoryUserStore(), auth.NewMemorySessionStore(),
+		auth.Config{Params: auth.ScryptParams{LogN: 4, R: 1, P: 1}})
+	Authoring = authoring.NewMemoryStore()
+	Authoring.RequiredApprovals = 0 // reviews are tested in review_test.go
+	examplesDir = t.TempDir()
+	t.Cleanup(func() { content.SetPublished(content.Published{}) })
+
+	author, reviewer := login(t, "Ada", auth.RoleAuthor), login(t, "Rex", auth.RoleReviewer)
+
+	// Authors write new tutorials and reviewers publish them
+	form := url.Values{
+		"action":        {"save"},
+		"id":            {"cookies"},
+		"title":         {"Reading Cookies"},
+		"level":         {"intermediate"},
+		"description":   {"<p>Cookies</p>"},
+		"prerequisites": {"handling-routes, html-templates"},
+		"filename":      {"main.go", ""},
+		"language":      {"go", "go"},
+		"highlight":     {"3", ""},
+		"source":        {"package main\n\nfunc main() {}\n", ""},
+		"callouts":      {"3: Starts here", ""},
+	}
+	rr := httptest.NewRecorder()
+	AdminEditHandler(rr, postForm("/admin/tutorials/new", form, author))
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/admin/tutorials/cookies" {
+		t.Fatalf("save: status %d, location %q", rr.Code, rr.Header().Get("Location"))
+	}
+	e, _ := Authoring.Entry(authoring.KindTutorial, "cookies")
+	doc := e.Latest().Doc
+	if e.History[0].Author != "Ada" || len(doc.Tutorial.Code) != 1 || doc.Tutorial.Code[0].Callouts[0].Note != "Starts here" || len(doc.Tutorial.Prerequisites) != 2 {
+		t.Errorf("saved draft = %+v", doc.Tutorial)
+	}
+	if _, _, ok := content.FindTutorial("cookies"); ok {
+		t.Error("draft is visible before publishing")
+	}
+
+	rr = httptest.NewRecorder()
+	AdminEditHandler(rr, postForm("/admin/tutorials/cookies", url.Values{"action": {"publish"}}, author))
+	if rr.Code != http.StatusForbidden {
+		t.Errorf("author publish: status %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	AdminEditHandler(rr, postForm("/admin/tutorials/cookies", url.Values{"action": {"publish"}}, reviewer))
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("publish: status %d", rr.Code)
+	}
+	if tutorial, level, ok := content.FindTutorial("cookies"); !ok || level.ID != "intermediate" || tutorial.Title != "Reading Cookies" {
+		t.Errorf("published tutorial = %q on %q, %v", tutorial.Title, level.ID, ok)
+	}
+	if level, _ := content.FindLevel("intermediate"); level.Tutorials[len(level.Tutorials)-1].ID != "cookies" {
+		t.Error("published tutorial is not on its level page")
+	}
+
+	// Editing a built-in example keeps its filename and regenerates the download
+	rr = httptest.NewRecorder()
+	AdminEditHandler(rr, postForm("/admin/examples/simple_server.go", url.Values{
+		"action": {"save"}, "id": {"renamed.go"}, "title": {"Simplest Server"}, "description": {"<p>Hi</p>"}, "code": {"package main // edited\n"},
+	}, author))
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/admin/examples/simple_server.go" {
+		t.Fatalf("save example: status %d, location %q", rr.Code, rr.Header().Get("Location"))
+	}
+	rr = httptest.NewRecorder()
+	AdminEditHandler(rr, postForm("/admin/examples/simple_server.go", url.Values{"action": {"publish"}}, reviewer))
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("publish example: status %d", rr.Code)
+	}
+	code, err := os.ReadFile(filepath.Join(examplesDir, "simple_server", "simple_server.go"))
+	if err != nil || !strings.Contains(string(code), "edited") {
+		t.Errorf("example download = %q, %v", code, err)
+	}
+
+	// Discarding the only draft of new content returns to the dashboard
+	form.Set("id", "scratch")
+	AdminEditHandler(httptest.NewRecorder(), postForm("/admin/tutorials/new", form, author))
+	rr = httptest.NewRecorder()
+	AdminEditHandler(rr, postForm("/admin/tutorials/scratch", url.Values{"action": {"discard"}}, author))
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/admin" {
+		t.Errorf("discard: status %d, location %q", rr.Code, rr.Header().Get("Location"))
+	}
+
+	req := httptest.NewRequest("GET", "/admin/widgets/x", nil)
+	req.AddCookie(author)
+	rr = httptest.NewRecorder()
+	AdminEditHandler(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown collection: status %d", rr.Code)
+	}
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/content"
+)
+
+// apiTutorial is the JSON representation of a tutorial
+type apiTutorial struct {
+	ID          string         `json:"id"`
+	Title       string         `json:"title"`
+	Level       string         `json:"level"`
+	URL         string         `json:"url"`
+	Description template.HTML  `json:"description"`
+	Explanation template.HTML  `json:"explanation"`
+	Code        []apiCodeBlock `json:"code"`
+	Published   time.Time      `json:"published"`
+	Updated     time.Time      `json:"updated"`
+}
+
+// apiCodeBlock is the JSON representation of a tutorial code block
+type apiCodeBlock struct {
+	Filename  string       `json:"filename"`
+	Language  string       `json:"language"`
+	Source    string       `json:"source"`
+	SourceURL string       `json:"source_url"`
+	Highlight string       `json:"highlight,omitempty"`
+	Callouts  []apiCallout `json:"callouts,omitempty"`
+}
+
+// apiCallout is the JSON representation of a line note
+type apiCallout struct {
+	Line int           `json:"line"`
+	Note template.HTML `json:"note"`
+}
+
+// newAPITutorial converts a tutorial into its JSON representation
+func newAPITutorial(t content.Tutorial, level content.Level) apiTutorial {
+	result := apiTutorial{
+		ID:          t.ID,
+		Title:       t.Title,
+		Level:       level.ID,
+		URL:         level.Path + "#" + t.ID,
+		Description: t.Description,
+		Explanation: t.Explanation,
+		Code:        []apiCodeBlock{},
+		Published:   t.Published,
+		Updated:     t.Updated,
+	}
+	for i, block := range t.Code {
+		apiBlock := apiCodeBlock{
+			Filename:  block.Filename,
+			Language:  block.Language,
+			Source:    block.Source,
+			SourceURL: t.CodeURL(i),
+			Highlight: block.Highlight,
+		}
+		for _, callout := range block.Callouts {
+			apiBlock.Callouts = append(apiBlock.Callouts, apiCallout{Line: callout.Line, Note: callout.Note})
+		}
+		result.Code = append(result.Code, apiBlock)
+	}
+	return result
+}
+
+// writeJSON encodes v as the JSON response body with the given status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
+
+// APITutorialsHandler serves tutorials as JSON. /api/tutorials lists every
+// tutorial in level order and /api/tutorials/{id} returns a single one.
+func APITutorialsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	id := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/tutorials"), "/")
+	if id == "" {
+		tutorials := []apiTutorial{}
+		for _, level := range content.GetLevels() {
+			for _, tutorial := range level.Tutorials {
+				tutorials = append(tutorials, newAPITutorial(tutorial, level))
+			}
+		}
+		writeJSON(w, http.StatusOK, tutorials)
+		return
+	}
+
+	tutorial, level, ok := content.FindTutorial(id)
+	if !ok {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "tutorial not found"})
+		return
+	}
+	writeJSON(w, http.StatusOK, newAPITutorial(tutorial, level))
+}
+
+// TutorialCodeHandler serves the exact source text of a tutorial code block
+// from URLs such as /tutorials/hello-world/code/1.go
+func TutorialCodeHandler(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/tutorials/"), "/")
+	if len(parts) != 3 || parts[1] != "code" {
+		http.NotFound(w, r)
+		return
+	}
+
+	tutorial, _, ok := content.FindTutorial(parts[0])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	// The file name is the 1-based block number followed by the block's extension
+	name := parts[2]
+	ext := path.Ext(name)
+	n, err := strconv.Atoi(strings.TrimSuffix(name, ext))
+	if err != nil || n < 1 || n > len(tutorial.Code) || tutorial.CodeURL(n-1) != r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+	block := tutorial.Code[n-1]
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if r.URL.Query().Get("download") != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", path.Base(block.Filename)))
+	}
+	w.Write([]byte(block.Source))
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang-webserver-tutorial/content"
+)
+
+func TestTutorialCodeHandler(t *testing.T) {
+	tutorial, _, _ := content.FindTutorial("html-templates")
+
+	tests := []struct {
+		path   string
+		status int
+		body   string
+	}{
+		{"/tutorials/html-templates/code/1.go", http.StatusOK, tutorial.Code[0].Source},
+		{"/tutorials/html-templates/code/2.html", http.StatusOK, tutorial.Code[1].Source},
+		{"/tutorials/html-templates/code/2.go", http.StatusNotFound, ""},
+		{"/tutorials/html-templates/code/3.go", http.StatusNotFound, ""},
+		{"/tutorials/missing/code/1.go", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		req, err := http.NewRequest("GET", tt.path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		http.HandlerFunc(TutorialCodeHandler).ServeHTTP(rr, req)
+
+		if rr.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.path, rr.Code, tt.status)
+			continue
+		}
+		if tt.status == http.StatusOK && rr.Body.String() != tt.body {
+			t.Errorf("%s: body does not match the exact source", tt.path)
+		}
+	}
+}
+
+func TestAPITutorialsHandler(t *testing.T) {
+	req, err := http.NewRequest("GET", "/api/tutorials/hello-world", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	http.HandlerFunc(APITutorialsHandler).ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rr.Code)
+	}
+
+	var got apiTutorial
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != "hello-world" || got.Level != "basic" {
+		t.Errorf("unexpected tutorial %s in level %s", got.ID, got.Level)
+	}
+	if len(got.Code) == 0 || got.Code[0].SourceURL != "/tutorials/hello-world/code/1.go" {
+		t.Errorf("code blocks should link to their plain source: %+v", got.Code)
+	}
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"golang-webserver-tutorial/bookshelf"
+)
+
+// Books hosts every learner's practice copy of the books API
+var Books = bookshelf.NewServer(bookshelf.Config{Prefix: "/sandbox"})
+
+// sandboxCookie remembers the learner's shelf for the browser session
+const sandboxCookie = "sandbox"
+
+// SandboxHandler serves the practice API page at /sandbox and each learner's
+// books API under /sandbox/{token}/api/books. POST /sandbox with
+// action=create or action=reset creates or resets the learner's shelf.
+func SandboxHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/sandbox" {
+		Books.ServeHTTP(w, r)
+		return
+	}
+
+	data := TemplateData{
+		Title:       "Practice API",
+		ActiveNav:   "restful",
+		CurrentYear: time.Now().Year(),
+		BaseURL:     baseURL(r),
+	}
+
+	var token string
+	if cookie, err := r.Cookie(sandboxCookie); err == nil {
+		token = cookie.Value
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		r.ParseForm()
+		switch r.PostForm.Get("action") {
+		case "create":
+			token, err := Books.Create(clientIP(r))
+			if err == nil {
+				http.SetCookie(w, &http.Cookie{
+					Name:     sandboxCookie,
+					Value:    token,
+					Path:     "/sandbox",
+					HttpOnly: true,
+					Secure:   r.TLS != nil,
+					SameSite: http.SameSiteLaxMode,
+				})
+				http.Redirect(w, r, "/sandbox", http.StatusSeeOther)
+				return
+			}
+			if errors.Is(err, bookshelf.ErrCreateLimit) {
+				data.Error = "You have created a lot of practice APIs recently; please try again later."
+				w.Header().Set("Retry-After", "600")
+				w.WriteHeader(http.StatusTooManyRequests)
+			} else {
+				log.Printf("creating practice API failed: %v", err)
+				data.Error = "Something went wrong, please try again."
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+		case "reset":
+			Books.Reset(token)
+			http.Redirect(w, r, "/sandbox", http.StatusSeeOther)
+			return
+		default:
+			http.Error(w, "Unknown action", http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if info, ok := Books.Info(token); ok {
+		data.Shelf = &info
+		data.ShelfURL = "/sandbox/" + token
+	}
+	parseTemplate(w, r, data, "templates/sandbox.html")
+}
+
+// clientIP identifies the client a request came from by its IP address
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
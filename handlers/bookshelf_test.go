@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSandboxHandler(t *testing.T) {
+	rr := httptest.NewRecorder()
+	SandboxHandler(rr, postForm("/sandbox", url.Values{"action": {"create"}}))
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("create: status %d", rr.Code)
+	}
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != sandboxCookie {
+		t.Fatalf("create: cookies %v", cookies)
+	}
+	books := "/sandbox/" + cookies[0].Value + "/api/books"
+
+	req := httptest.NewRequest("DELETE", books+"/1", nil)
+	rr = httptest.NewRecorder()
+	SandboxHandler(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("delete: status %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	SandboxHandler(rr, postForm("/sandbox", url.Values{"action": {"reset"}}, cookies[0]))
+	if rr.Code != http.StatusSeeOther {
+		t.Errorf("reset: status %d", rr.Code)
+	}
+	rr = httptest.NewRecorder()
+	SandboxHandler(rr, httptest.NewRequest("GET", books+"/1", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("book 1 after reset: status %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	SandboxHandler(rr, postForm("/sandbox", url.Values{"action": {"explode"}}))
+	if rr.Code != http.StatusBadRequest {
+		t.Errorf("unknown action: status %d", rr.Code)
+	}
+}
+
+func TestSandboxCreateLimit(t *testing.T) {
+	// Creating practice APIs is rate limited per client address
+	status := http.StatusSeeOther
+	for i := 0; i < 10 && status == http.StatusSeeOther; i++ {
+		req := postForm("/sandbox", url.Values{"action": {"create"}})
+		req.RemoteAddr = "198.51.100.7:4321"
+		rr := httptest.NewRecorder()
+		SandboxHandler(rr, req)
+		status = rr.Code
+		if status == http.StatusTooManyRequests && rr.Header().Get("Retry-After") == "" {
+			t.Error("429 without Retry-After")
+		}
+	}
+	if status != http.StatusTooManyRequests {
+		t.Errorf("creating shelves was not limited: last status %d", status)
+	}
+}
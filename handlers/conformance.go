@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/conformance"
+)
+
+// errConformanceTarget is returned for addresses that are not the learner's
+// own practice API or live example
+var errConformanceTarget = errors.New("conformance: check your practice API at /sandbox/{token} or a live example you started at /live/{example}")
+
+// ConformanceHandler shows the books API checker at /conformance. POST runs
+// every check against the logged in learner's practice API or a live example
+// they started, named by the url field.
+func ConformanceHandler(w http.ResponseWriter, r *http.Request) {
+	data := TemplateData{
+		Title:       "Check Your Books API",
+		ActiveNav:   "restful",
+		CurrentYear: time.Now().Year(),
+		Form:        r.URL.Query(),
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		user, ok := Accounts.CurrentUser(r)
+		if !ok {
+			http.Redirect(w, r, "/login?next="+url.QueryEscape("/conformance"), http.StatusSeeOther)
+			return
+		}
+		r.ParseForm()
+		data.Form = r.PostForm
+		base, target, err := conformanceTarget(user, r.PostForm.Get("url"))
+		if err != nil {
+			if wantsJSON(r) {
+				writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+				return
+			}
+			data.Error = "Enter the address of your practice API, as shown on the Practice API page, or of a live example you started."
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			break
+		}
+
+		// The checks are served in this process, so they can only reach
+		// the target and never another service on this machine
+		ctx, cancel := context.WithTimeout(r.Context(), time.Minute)
+		defer cancel()
+		report := conformance.Run(ctx, conformance.HandlerClient(target), base)
+		if wantsJSON(r) {
+			writeJSON(w, http.StatusOK, report)
+			return
+		}
+		data.Conformance = &report
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	parseTemplate(w, r, data, "templates/conformance.html")
+}
+
+// conformanceTarget accepts a base URL on this site that belongs to user:
+// a practice API, whose secret token only its learner knows, or a live
+// example the user started. It returns the base URL and the handler that
+// serves it. Only the path is matched: the checks never leave this process,
+// so the host is whatever the learner copied, such as SITE_URL or localhost.
+func conformanceTarget(user auth.User, raw string) (string, http.Handler, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil {
+		return "", nil, errConformanceTarget
+	}
+	u.RawQuery, u.Fragment = "", ""
+	base := strings.TrimRight(u.String(), "/")
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 2 {
+		return "", nil, errConformanceTarget
+	}
+	switch parts[0] {
+	case "sandbox":
+		if _, ok := Books.Info(parts[1]); ok {
+			return base, Books, nil
+		}
+	case "live":
+		if inst, ok := Live.Get(parts[1]); ok && inst.Owner() == user.Username {
+			return base, http.HandlerFunc(LiveProxyHandler), nil
+		}
+	}
+	return "", nil, errConformanceTarget
+}
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang-webserver-tutorial/conformance"
+)
+
+func TestConformanceHandler(t *testing.T) {
+	learner := login(t, "conformance-learner")
+	token, err := Books.Create("192.0.2.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := postForm("/conformance", url.Values{"url": {"http://localhost:8080/sandbox/" + token}}, learner)
+	req.Header.Set("Accept", "application/json")
+	rr := httptest.NewRecorder()
+	ConformanceHandler(rr, req)
+	var report conformance.Report
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil || !report.Passed {
+		t.Errorf("practice API did not pass: %d %s", rr.Code, rr.Body)
+	}
+
+	// The address shown on /sandbox uses SITE_URL on a deployed site
+	deployed, err := Books.Create("192.0.2.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = postForm("/conformance", url.Values{"url": {"https://go-tutorial.example/sandbox/" + deployed}}, learner)
+	req.Header.Set("Accept", "application/json")
+	rr = httptest.NewRecorder()
+	ConformanceHandler(rr, req)
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil || !report.Passed {
+		t.Errorf("practice API on SITE_URL did not pass: %d %s", rr.Code, rr.Body)
+	}
+
+	// Only the learner's own targets on this site can be checked
+	for _, target := range []string{"http://example.com", "http://localhost:8080", "http://127.0.0.1:6060/debug", "http://localhost:8080/sandbox/nope", "http://localhost:8080/live/rest_api", "ftp://localhost/sandbox/" + token, "http://user@localhost/sandbox/" + token} {
+		req = postForm("/conformance", url.Values{"url": {target}}, learner)
+		req.Header.Set("Accept", "application/json")
+		rr = httptest.NewRecorder()
+		ConformanceHandler(rr, req)
+		if rr.Code != http.StatusUnprocessableEntity {
+			t.Errorf("%s: status %d", target, rr.Code)
+		}
+	}
+
+	// Visitors have to log in first
+	rr = httptest.NewRecorder()
+	ConformanceHandler(rr, postForm("/conformance", url.Values{"url": {"http://localhost:8080/sandbox/" + token}}))
+	if rr.Code != http.StatusSeeOther {
+		t.Errorf("anonymous check: status %d", rr.Code)
+	}
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/grader"
+	"golang-webserver-tutorial/progress"
+	"golang-webserver-tutorial/sandbox"
+)
+
+// Sandbox compiles and runs learners' code
+var Sandbox = sandbox.New(sandbox.DefaultCacheDir(), 4)
+
+// ExerciseHandler shows a tutorial's exercise at /exercise/{tutorial-id} and
+// grades submissions posted to the same URL. Submissions are kept in the
+// learner's history; JSON clients get the report as JSON.
+func ExerciseHandler(w http.ResponseWriter, r *http.Request) {
+	tutorial, level, ok := content.FindTutorial(strings.TrimPrefix(r.URL.Path, "/exercise/"))
+	if !ok || tutorial.Exercise == nil {
+		http.NotFound(w, r)
+		return
+	}
+	exercise := tutorial.Exercise
+
+	data := TemplateData{
+		Title:       "Exercise: " + tutorial.Title,
+		ActiveNav:   level.ID,
+		CurrentYear: time.Now().Year(),
+		Tutorial:    &tutorial,
+		Level:       &level,
+		Source:      exercise.Starter,
+	}
+	key, haveKey := learnerKey(r)
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		r.Body = http.MaxBytesReader(w, r.Body, grader.MaxSourceSize+4096)
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Submission is too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		data.Source = r.PostForm.Get("source")
+
+		ctx, cancel := context.WithTimeout(r.Context(), time.Minute)
+		defer cancel()
+		report, err := grader.Grade(ctx, Sandbox, exercise, data.Source)
+		if err != nil {
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, sandbox.ErrBusy):
+				status = http.StatusServiceUnavailable
+				data.Error = "The grader is busy right now; please try again in a few seconds."
+			case errors.Is(err, grader.ErrTooLarge):
+				status = http.StatusRequestEntityTooLarge
+				data.Error = "Your submission is too large."
+			default:
+				log.Printf("grading %s failed: %v", tutorial.ID, err)
+				data.Error = "Your code could not be graded."
+			}
+			if wantsJSON(r) {
+				writeJSON(w, status, map[string]string{"error": data.Error})
+				return
+			}
+			w.WriteHeader(status)
+			break
+		}
+		data.Report = &report
+
+		key, err = ensureLearner(w, r)
+		if haveKey = err == nil; haveKey {
+			err = Progress.AddSubmission(key, tutorial.ID, progress.Submission{
+				Source:    data.Source,
+				Passed:    report.Passed,
+				TestsPass: report.PassedCount(),
+				Tests:     len(report.Tests),
+			})
+		}
+		if err != nil {
+			log.Printf("saving submission failed: %v", err)
+		}
+
+		if wantsJSON(r) {
+			writeJSON(w, http.StatusOK, report)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	// Show past submissions, newest first, and resume from the latest one
+	if haveKey {
+		if record, err := Progress.Get(key); err == nil {
+			history := record.Submissions[tutorial.ID]
+			for i := len(history) - 1; i >= 0; i-- {
+				data.Submissions = append(data.Submissions, history[i])
+			}
+		}
+	}
+	if r.Method != http.MethodPost && len(data.Submissions) > 0 {
+		data.Source = data.Submissions[0].Source
+	}
+
+	parseTemplate(w, r, data, "templates/exercise.html")
+}
+
+// wantsJSON reports whether the client asked for a JSON response
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/grader"
+)
+
+func TestExerciseHandlerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"no exercise", httptest.NewRequest("GET", "/exercise/serve-html", nil), http.StatusNotFound},
+		{"unknown tutorial", httptest.NewRequest("GET", "/exercise/missing", nil), http.StatusNotFound},
+		{"wrong method", httptest.NewRequest("PUT", "/exercise/hello-world", nil), http.StatusMethodNotAllowed},
+		{"too large", postForm("/exercise/hello-world", url.Values{
+			"source": {strings.Repeat("x", grader.MaxSourceSize+1)},
+		}), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		tt.req.Header.Set("Accept", "application/json")
+		rr := httptest.NewRecorder()
+		ExerciseHandler(rr, tt.req)
+		if rr.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, rr.Code, tt.status)
+		}
+	}
+}
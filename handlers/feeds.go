@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/feed"
+	"golang-webserver-tutorial/highlight"
+)
+
+// feedTagPrefix makes entry IDs stable regardless of the host serving the site
+const feedTagPrefix = "tag:go-web-server-tutorial,2025:"
+
+// SiteURL is the site's canonical origin, such as https://go-tutorial.example,
+// used for absolute URLs in feeds, the sitemap, robots.txt and pages; main
+// sets it from the SITE_URL environment variable. When it is empty, as in
+// development, the origin the request was made to is used, which the client
+// controls.
+var SiteURL string
+
+// ParseSiteURL checks that raw is an http or https origin and returns it
+// without a trailing slash
+func ParseSiteURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", fmt.Errorf("site URL %q must be an origin such as https://go-tutorial.example", raw)
+	}
+	return strings.TrimRight(u.String(), "/"), nil
+}
+
+// baseURL returns SiteURL, or the scheme and host the request was made to
+// when it is not set
+func baseURL(r *http.Request) string {
+	if SiteURL != "" {
+		return SiteURL
+	}
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// buildFeed collects every tutorial and example into a feed sorted by update time
+func buildFeed(r *http.Request, self string) feed.Feed {
+	base := baseURL(r)
+	f := feed.Feed{
+		ID:          feedTagPrefix + "feed",
+		Title:       "Go Web Server Tutorial",
+		Description: "New and updated tutorials and examples for building web servers in Go",
+		Link:        base + "/",
+		Self:        base + self,
+		Author:      "Go Web Server Tutorial",
+	}
+
+	for _, level := range content.GetLevels() {
+		for _, tutorial := range level.Tutorials {
+			f.Entries = append(f.Entries, feed.Entry{
+				ID:        feedTagPrefix + "tutorial/" + tutorial.ID,
+				Title:     tutorial.Title,
+				Link:      base + level.Path + "#" + tutorial.ID,
+				Summary:   strings.TrimSpace(string(tutorial.Description)),
+				Content:   string(tutorial.Description) + feedCode(tutorial) + string(tutorial.Explanation),
+				Published: tutorial.Published,
+				Updated:   tutorial.Updated,
+			})
+		}
+	}
+
+	for _, example := range content.GetCodeExamples() {
+		f.Entries = append(f.Entries, feed.Entry{
+			ID:        feedTagPrefix + "example/" + example.Filename,
+			Title:     example.Title,
+			Link:      base + "/examples#" + example.Filename,
+			Summary:   string(example.Description),
+			Published: example.Published,
+			Updated:   example.Updated,
+		})
+	}
+
+	f.SortByUpdated()
+	return f
+}
+
+// feedCode highlights a tutorial's code blocks with inline styles since feed
+// readers do not load the site stylesheet
+func feedCode(t content.Tutorial) string {
+	var b strings.Builder
+	for _, block := range t.Code {
+		opts := highlight.Options{Language: block.Language, Inline: true}
+		src, lines := highlight.Prepare(block.Source, block.Highlight)
+		opts.Highlight = lines
+		fmt.Fprintf(&b, "<p><strong>%s</strong></p>", html.EscapeString(block.Filename))
+		b.WriteString(highlight.Render(src, opts))
+	}
+	return b.String()
+}
+
+// AtomFeedHandler serves new and updated content as an Atom feed
+func AtomFeedHandler(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := feed.WriteAtom(&buf, buildFeed(r, "/feed.atom")); err != nil {
+		http.Error(w, "Error generating feed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// RSSFeedHandler serves new and updated content as an RSS feed
+func RSSFeedHandler(w http.ResponseWriter, r *http.Request) {
+	var buf bytes.Buffer
+	if err := feed.WriteRSS(&buf, buildFeed(r, "/feed.rss")); err != nil {
+		http.Error(w, "Error generating feed: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// SitemapHandler lists every page route with its last modification date
+func SitemapHandler(w http.ResponseWriter, r *http.Request) {
+	base := baseURL(r)
+	var urls []feed.URL
+	for _, route := range Routes() {
+		if !route.Page {
+			continue
+		}
+		u := feed.URL{Loc: base + route.Pattern}
+		if route.LastMod != nil {
+			u.LastMod = route.LastMod()
+		}
+		urls = append(urls, u)
+	}
+
+	var buf bytes.Buffer
+	if err := feed.WriteSitemap(&buf, urls); err != nil {
+		http.Error(w, "Error generating sitemap: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Write(buf.Bytes())
+}
+
+// RobotsHandler serves robots.txt built from the registered routes
+func RobotsHandler(w http.ResponseWriter, r *http.Request) {
+	var disallow []string
+	for _, route := range Routes() {
+		if route.NoIndex {
+			disallow = append(disallow, route.Pattern)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	feed.WriteRobots(w, baseURL(r)+"/sitemap.xml", disallow)
+}
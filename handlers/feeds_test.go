@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSiteURL(t *testing.T) {
+	SiteURL = "https://go-tutorial.example"
+	defer func() { SiteURL = "" }()
+
+	// A forged Host header does not end up in the sitemap or feeds
+	for _, path := range []string{"/sitemap.xml", "/feed.atom", "/robots.txt"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Host = "evil.example"
+		rr := httptest.NewRecorder()
+		for _, route := range Routes() {
+			if route.Pattern == path {
+				route.Handler.ServeHTTP(rr, req)
+			}
+		}
+		body := rr.Body.String()
+		if strings.Contains(body, "evil.example") || !strings.Contains(body, "https://go-tutorial.example/") {
+			t.Errorf("%s uses the wrong origin:\n%s", path, body)
+		}
+	}
+
+	for raw, want := range map[string]string{
+		"https://go-tutorial.example/": "https://go-tutorial.example",
+		"http://localhost:5000":        "http://localhost:5000",
+		"go-tutorial.example":          "",
+		"ftp://go-tutorial.example":    "",
+		"https://user@example.com":     "",
+	} {
+		got, err := ParseSiteURL(raw)
+		if got != want || (err != nil) != (want == "") {
+			t.Errorf("ParseSiteURL(%q) = %q, %v", raw, got, err)
+		}
+	}
+}
+
+func TestAPINoIndex(t *testing.T) {
+	// JSON is for programs, so crawlers are asked to skip every API route
+	for _, route := range Routes() {
+		if strings.HasPrefix(route.Pattern, "/api/") && !route.NoIndex {
+			t.Errorf("%s is missing NoIndex", route.Pattern)
+		}
+	}
+}
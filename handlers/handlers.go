@@ -1,15 +1,27 @@
 package handlers
 
 import (
+        "bytes"
         "fmt"
         "html/template"
         "net/http"
+        "net/url"
         "os"
         "path/filepath"
         "strings"
         "time"
 
+        "golang-webserver-tutorial/auth"
+        "golang-webserver-tutorial/bookshelf"
+        "golang-webserver-tutorial/conformance"
         "golang-webserver-tutorial/content"
+        "golang-webserver-tutorial/export"
+        "golang-webserver-tutorial/grader"
+        "golang-webserver-tutorial/highlight"
+        "golang-webserver-tutorial/i18n"
+        "golang-webserver-tutorial/inspect"
+        "golang-webserver-tutorial/live"
+        "golang-webserver-tutorial/progress"
 )
 
 // TemplateData holds all data that will be passed to templates
@@ -17,22 +29,117 @@ type TemplateData struct {
         Title       string
         Content     template.HTML
         Tutorials   []content.Tutorial
+        Levels      []content.Level
         Examples    []content.CodeExample
         ActiveNav   string
         CurrentYear int
+        User        *auth.User
+        Error       string
+        Form        url.Values
+        Progress    progress.Summary
+        Tutorial    *content.Tutorial
+        Level       *content.Level
+        QuizResult  *content.QuizResult
+        Report      *grader.Report
+        Source      string
+        Submissions []progress.Submission
+        Example     *content.CodeExample
+        Live        *live.Status
+        BaseURL     string
+        Shelf       *bookshelf.Info
+        ShelfURL    string
+        Conformance *conformance.Report
+        Inspect     *inspect.Request
+        Map         [][]progress.Node
+        Paths       []progress.PathProgress
+        Locale      string
+        Locales     []i18n.Locale
+        Path        string
+        AdminItems  []adminItem
+        Editor      *adminEditor
+        Review      *adminReview
+        HotReload   bool
+        Users       []auth.User
+        Roles       []auth.Role
+        Stripped    []content.Stripped
+        Nonce       string
 }
 
-// parseTemplate parses the given template files and executes them with the provided data
-func parseTemplate(w http.ResponseWriter, data TemplateData, templateFiles ...string) {
-        // Add layout template to the list of templates
-        files := append([]string{"templates/layout.html"}, templateFiles...)
+// Accounts manages users and login sessions. It defaults to in-memory stores;
+// main replaces it with persistent ones.
+var Accounts = auth.NewManager(auth.NewMemoryUserStore(), auth.NewMemorySessionStore(), auth.Config{})
+
+// templateFuncs are the helper functions available to every template
+var templateFuncs = template.FuncMap{
+        "codeBlock":     renderCodeBlock,
+        "prerequisites": prerequisites,
+        "localURL":      localURL,
+        "tutorialPath":  tutorialPath,
+        "join":          strings.Join,
+        "can":           can,
+}
+
+// renderCodeBlock renders the code block at index i of a tutorial with syntax
+// highlighting, line numbers and per-line anchors
+func renderCodeBlock(t content.Tutorial, i int) template.HTML {
+        block := t.Code[i]
+        opts := highlight.Options{
+                Language:     block.Language,
+                LineNumbers:  true,
+                Callouts:     make(highlight.Lines),
+                AnchorPrefix: t.CodeAnchor(i),
+        }
+        
+        src, lines := highlight.Prepare(block.Source, block.Highlight)
+        opts.Highlight = lines
+        for _, callout := range block.Callouts {
+                opts.Callouts[callout.Line] = true
+        }
+        
+        return template.HTML(highlight.Render(src, opts))
+}
+
+// parseTemplate executes the given page templates with the provided data
+func parseTemplate(w http.ResponseWriter, r *http.Request, data TemplateData, templateFiles ...string) {
+        // Show the logged in user in the navigation
+        if user, ok := Accounts.CurrentUser(r); ok {
+                data.User = &user
+        }
+        
+        // Show which tutorials the learner has completed
+        data.Progress = progressSummary(r)
+        
+        // Show translated tutorials and interface strings where available
+        data.Locale = localeFor(r)
+        data.Locales = i18n.Locales
+        data.Path = r.URL.Path
+        if data.BaseURL == "" {
+                data.BaseURL = baseURL(r)
+        }
+        data.HotReload = HotReload
+        data.Nonce = Nonce(r)
+        data.Tutorials = content.LocalizeAll(data.Tutorials, data.Locale)
+        if data.Tutorial != nil {
+                tutorial := content.Localize(*data.Tutorial, data.Locale)
+                data.Tutorial = &tutorial
+        }
+        levels := make([]content.Level, len(data.Levels))
+        for i, level := range data.Levels {
+                level.Tutorials = content.LocalizeAll(level.Tutorials, data.Locale)
+                levels[i] = level
+        }
+        data.Levels = levels
         
-        // Parse templates
-        tmpl, err := template.ParseFiles(files...)
+        // Use the parsed templates, bound to this request's helpers
+        tmpl, err := pageTemplate(templateFiles...)
+        if err == nil {
+                tmpl, err = tmpl.Clone()
+        }
         if err != nil {
                 http.Error(w, "Error parsing template: "+err.Error(), http.StatusInternalServerError)
                 return
         }
+        tmpl.Funcs(requestFuncs(data))
         
         // Execute template
         err = tmpl.ExecuteTemplate(w, "layout", data)
@@ -54,63 +161,67 @@ func HomeHandler(w http.ResponseWriter, r *http.Request) {
                 CurrentYear: time.Now().Year(),
         }
         
-        parseTemplate(w, data, "templates/home.html")
+        parseTemplate(w, r, data, "templates/home.html")
 }
 
 // BasicHandler displays the basic concepts page
 func BasicHandler(w http.ResponseWriter, r *http.Request) {
-        tutorials := content.GetBasicTutorials()
+        level, _ := content.FindLevel("basic")
+        tutorials := level.Tutorials
         
         data := TemplateData{
-                Title:       "Basic Web Server Concepts",
+                Title:       i18n.T(localeFor(r), "basic.title"),
                 Tutorials:   tutorials,
                 ActiveNav:   "basic",
                 CurrentYear: time.Now().Year(),
         }
         
-        parseTemplate(w, data, "templates/basic.html")
+        parseTemplate(w, r, data, "templates/basic.html")
 }
 
 // IntermediateHandler displays the intermediate concepts page
 func IntermediateHandler(w http.ResponseWriter, r *http.Request) {
-        tutorials := content.GetIntermediateTutorials()
+        level, _ := content.FindLevel("intermediate")
+        tutorials := level.Tutorials
         
         data := TemplateData{
-                Title:       "Intermediate Web Server Concepts",
+                Title:       i18n.T(localeFor(r), "intermediate.title"),
                 Tutorials:   tutorials,
                 ActiveNav:   "intermediate",
                 CurrentYear: time.Now().Year(),
         }
         
-        parseTemplate(w, data, "templates/intermediate.html")
+        parseTemplate(w, r, data, "templates/intermediate.html")
 }
 
 // AdvancedHandler displays the advanced concepts page
 func AdvancedHandler(w http.ResponseWriter, r *http.Request) {
-        tutorials := content.GetAdvancedTutorials()
+        level, _ := content.FindLevel("advanced")
+        tutorials := level.Tutorials
         
         data := TemplateData{
-                Title:       "Advanced Web Server Concepts",
+                Title:       i18n.T(localeFor(r), "advanced.title"),
                 Tutorials:   tutorials,
                 ActiveNav:   "advanced",
                 CurrentYear: time.Now().Year(),
         }
         
-        parseTemplate(w, data, "templates/advanced.html")
+        parseTemplate(w, r, data, "templates/advanced.html")
 }
 
 // RestfulHandler displays the RESTful API concepts page
 func RestfulHandler(w http.ResponseWriter, r *http.Request) {
-        tutorials := content.GetRestfulTutorials()
+        level, _ := content.FindLevel("restful")
+        tutorials := level.Tutorials
         
         data := TemplateData{
-                Title:       "RESTful API Development",
+                Title:       i18n.T(localeFor(r), "restful.title"),
                 Tutorials:   tutorials,
                 ActiveNav:   "restful",
                 CurrentYear: time.Now().Year(),
         }
         
-        parseTemplate(w, data, "templates/restful.html")
+        parseTemplate(w, r, data, "templates/restful.html")
 }
 
 // ExamplesHandler displays the code examples page
@@ -118,13 +229,41 @@ func ExamplesHandler(w http.ResponseWriter, r *http.Request) {
         examples := content.GetCodeExamples()
         
         data := TemplateData{
-                Title:       "Code Examples",
+                Title:       i18n.T(localeFor(r), "examples.title"),
                 Examples:    examples,
                 ActiveNav:   "examples",
                 CurrentYear: time.Now().Year(),
         }
         
-        parseTemplate(w, data, "templates/examples.html")
+        parseTemplate(w, r, data, "templates/examples.html")
+}
+
+// BookHandler displays every tutorial on a single printable page
+func BookHandler(w http.ResponseWriter, r *http.Request) {
+        data := TemplateData{
+                Title:       "The Complete Book",
+                Levels:      content.GetLevels(),
+                ActiveNav:   "book",
+                CurrentYear: time.Now().Year(),
+        }
+        
+        parseTemplate(w, r, data, "templates/book.html")
+}
+
+// EPUBHandler provides all tutorials as a downloadable EPUB book
+func EPUBHandler(w http.ResponseWriter, r *http.Request) {
+        book := export.NewBook(content.GetLevels())
+        
+        // Render into a buffer so errors can still be reported with a proper status
+        var buf bytes.Buffer
+        if err := book.WriteEPUB(&buf); err != nil {
+                http.Error(w, "Error generating EPUB: "+err.Error(), http.StatusInternalServerError)
+                return
+        }
+        
+        w.Header().Set("Content-Type", "application/epub+zip")
+        w.Header().Set("Content-Disposition", "attachment; filename=go-web-server-tutorial.epub")
+        w.Write(buf.Bytes())
 }
 
 // DownloadHandler provides downloadable code examples
@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/i18n"
+)
+
+// langCookie remembers the language a visitor chose with the switcher
+const langCookie = "lang"
+
+// Localize serves pages in the visitor's language. A language prefix such as
+// /es/basic picks the language, is remembered in a cookie and is removed
+// before routing, so every page is also available under each prefix.
+func Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tag, rest, ok := i18n.SplitPath(r.URL.Path)
+		if !ok {
+			// The page depends on the negotiated language
+			w.Header().Add("Vary", "Accept-Language")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		http.SetCookie(w, &http.Cookie{
+			Name:     langCookie,
+			Value:    tag,
+			Path:     "/",
+			MaxAge:   365 * 24 * 60 * 60,
+			HttpOnly: true,
+			Secure:   r.TLS != nil,
+			SameSite: http.SameSiteLaxMode,
+		})
+
+		r2 := r.WithContext(i18n.WithLocale(r.Context(), tag))
+		r2.URL = new(url.URL)
+		*r2.URL = *r.URL
+		r2.URL.Path = rest
+		r2.URL.RawPath = strings.TrimPrefix(r.URL.RawPath, "/"+tag)
+		next.ServeHTTP(w, r2)
+	})
+}
+
+// localeFor returns the language for the request: a URL prefix, then the
+// switcher's cookie, then the Accept-Language header
+func localeFor(r *http.Request) string {
+	if tag, ok := i18n.FromContext(r.Context()); ok {
+		return tag
+	}
+	if cookie, err := r.Cookie(langCookie); err == nil && i18n.Supported(cookie.Value) {
+		return cookie.Value
+	}
+	return i18n.Negotiate(r.Header.Get("Accept-Language"))
+}
+
+// localURL returns the path of a page in the given language
+func localURL(tag, path string) string {
+	return "/" + tag + path
+}
+
+// tutorialPath returns the path of a tutorial on its level page
+func tutorialPath(id string) string {
+	_, level, ok := content.FindTutorial(id)
+	if !ok {
+		return "/"
+	}
+	return level.Path + "#" + id
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocalize(t *testing.T) {
+	var gotPath, gotLocale string
+	handler := Localize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotLocale = r.URL.Path, localeFor(r)
+	}))
+
+	for _, tt := range []struct {
+		path, accept, cookie string
+		wantPath, wantLocale string
+		setsCookie           bool
+	}{
+		{"/basic", "", "", "/basic", "en", false},
+		{"/basic", "de-DE,de;q=0.9", "", "/basic", "de", false},
+		{"/basic", "de", "es", "/basic", "es", false},
+		{"/basic", "", "fr", "/basic", "en", false},
+		{"/es/basic", "de", "de", "/basic", "es", true},
+		{"/de", "", "", "/", "de", true},
+		{"/fr/basic", "", "", "/fr/basic", "en", false},
+	} {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept-Language", tt.accept)
+		}
+		if tt.cookie != "" {
+			req.AddCookie(&http.Cookie{Name: langCookie, Value: tt.cookie})
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if gotPath != tt.wantPath || gotLocale != tt.wantLocale {
+			t.Errorf("%s (accept %q, cookie %q): path %q locale %q, want %q %q", tt.path, tt.accept, tt.cookie, gotPath, gotLocale, tt.wantPath, tt.wantLocale)
+		}
+		cookies := rec.Result().Cookies()
+		if tt.setsCookie != (len(cookies) == 1 && cookies[0].Name == langCookie && cookies[0].Value == tt.wantLocale) {
+			t.Errorf("%s: cookies %v", tt.path, cookies)
+		}
+		if !tt.setsCookie && rec.Header().Get("Vary") != "Accept-Language" {
+			t.Errorf("%s: Vary = %q", tt.path, rec.Header().Get("Vary"))
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/inspect"
+)
+
+// InspectHandler echoes any request to /inspect or /inspect/... back to the
+// client. Browsers get a page with a request builder; other clients, such as
+// curl, get JSON. The site's own cookies are hidden.
+func InspectHandler(w http.ResponseWriter, r *http.Request) {
+	req := inspect.Capture(r, inspect.MaxBodySize)
+	req.Redact(Accounts.CookieName(), learnerCookie, sandboxCookie)
+
+	if !strings.Contains(r.Header.Get("Accept"), "text/html") {
+		writeJSON(w, http.StatusOK, req)
+		return
+	}
+
+	data := TemplateData{
+		Title:       "Request Inspector",
+		CurrentYear: time.Now().Year(),
+		Inspect:     &req,
+	}
+	parseTemplate(w, r, data, "templates/inspect.html")
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/inspect"
+)
+
+func TestInspectHandler(t *testing.T) {
+	req := httptest.NewRequest("PATCH", "/inspect/books/1?fields=title", strings.NewReader(`{"title": "Go"}`))
+	req.AddCookie(&http.Cookie{Name: learnerCookie, Value: "secret"})
+	rr := httptest.NewRecorder()
+	InspectHandler(rr, req)
+
+	var got inspect.Request
+	if err := json.Unmarshal(rr.Body.Bytes(), &got); err != nil {
+		t.Fatalf("response is not JSON: %s", rr.Body)
+	}
+	if got.Method != "PATCH" || got.URL.Path != "/inspect/books/1" || got.Body.Text != `{"title": "Go"}` {
+		t.Errorf("echoed request = %+v", got)
+	}
+	if strings.Contains(rr.Body.String(), "secret") {
+		t.Errorf("learner cookie was not hidden: %s", rr.Body)
+	}
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/live"
+)
+
+// Live runs example servers that learners can try in the browser
+var Live = live.NewManager(Sandbox, live.DefaultBuildDir(), live.Config{})
+
+// liveExample returns the example named by the first path segment after
+// prefix, if it can run live, and the rest of the path
+func liveExample(path, prefix string) (content.CodeExample, string, bool) {
+	name, rest := strings.TrimPrefix(path, prefix), ""
+	if i := strings.IndexByte(name, '/'); i >= 0 {
+		name, rest = name[:i], name[i:]
+	}
+	example, ok := content.FindExample(name)
+	if !ok || !example.CanRunLive() {
+		return content.CodeExample{}, "", false
+	}
+	return example, rest, true
+}
+
+// LiveProxyHandler forwards /live/{example}/... to the running example server
+func LiveProxyHandler(w http.ResponseWriter, r *http.Request) {
+	example, rest, ok := liveExample(r.URL.Path, "/live/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	prefix := "/live/" + example.Name()
+	if rest == "" {
+		http.Redirect(w, r, prefix+"/", http.StatusMovedPermanently)
+		return
+	}
+
+	inst, ok := Live.Get(example.Name())
+	if !ok {
+		http.Error(w, "This example is not running. Start it at /examples/live/"+example.Name(), http.StatusServiceUnavailable)
+		return
+	}
+	http.StripPrefix(prefix, inst).ServeHTTP(w, r)
+}
+
+// LiveExampleHandler shows the control page for a live example at
+// /examples/live/{example}. POST with action=start or action=stop starts or
+// stops it; JSON clients get the example's status and log.
+func LiveExampleHandler(w http.ResponseWriter, r *http.Request) {
+	example, rest, ok := liveExample(r.URL.Path, "/examples/live/")
+	if !ok || rest != "" {
+		http.NotFound(w, r)
+		return
+	}
+	name := example.Name()
+	page := "/examples/live/" + name
+
+	data := TemplateData{
+		Title:       "Live: " + example.Title,
+		ActiveNav:   "examples",
+		CurrentYear: time.Now().Year(),
+		Example:     &example,
+		BaseURL:     baseURL(r),
+	}
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+	case http.MethodPost:
+		// Each example runs as a process of its own, so only people with an
+		// account may start or stop them, and each may run one at a time
+		user, ok := Accounts.CurrentUser(r)
+		if !ok {
+			http.Redirect(w, r, "/login?next="+url.QueryEscape(page), http.StatusSeeOther)
+			return
+		}
+		r.ParseForm()
+		switch r.PostForm.Get("action") {
+		case "start":
+			ctx, cancel := context.WithTimeout(r.Context(), time.Minute)
+			defer cancel()
+			_, err := Live.Start(ctx, user.Username, name, example.Code, "/live/"+name)
+			if err == nil {
+				http.Redirect(w, r, page, http.StatusSeeOther)
+				return
+			}
+			status := http.StatusInternalServerError
+			switch {
+			case errors.Is(err, live.ErrTooMany):
+				status = http.StatusTooManyRequests
+				w.Header().Set("Retry-After", "60")
+				data.Error = "Too many examples are running right now; please try again in a few minutes."
+			case errors.Is(err, live.ErrOwnerLimit):
+				status = http.StatusTooManyRequests
+				data.Error = "You already have an example running. Stop it before starting another."
+			default:
+				log.Printf("starting live example %s failed: %v", name, err)
+				data.Error = "The example could not be started."
+			}
+			w.WriteHeader(status)
+		case "stop":
+			if inst, ok := Live.Get(name); ok && !mayStop(user, inst) {
+				http.Error(w, "Only the person who started this example can stop it", http.StatusForbidden)
+				return
+			}
+			Live.Stop(name)
+			http.Redirect(w, r, page, http.StatusSeeOther)
+			return
+		default:
+			http.Error(w, "Unknown action", http.StatusBadRequest)
+			return
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if inst, ok := Live.Get(name); ok {
+		status := inst.Status()
+		data.Live = &status
+	}
+
+	if wantsJSON(r) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"running": data.Live != nil,
+			"log":     liveLog(data.Live),
+		})
+		return
+	}
+	parseTemplate(w, r, data, "templates/live.html")
+}
+
+// mayStop reports whether user may stop inst: whoever started it, or an admin
+func mayStop(user auth.User, inst *live.Instance) bool {
+	return inst.Owner() == user.Username || user.Can(auth.PermManageUsers)
+}
+
+// liveLog returns the recent output of a running example
+func liveLog(status *live.Status) []string {
+	if status == nil {
+		return []string{}
+	}
+	return status.Log
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/content"
+)
+
+func TestLiveHandlers(t *testing.T) {
+	learner := login(t, "live-learner")
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		req     *http.Request
+		status  int
+	}{
+		{"unknown example", LiveProxyHandler, httptest.NewRequest("GET", "/live/nope/", nil), http.StatusNotFound},
+		{"example that cannot run live", LiveProxyHandler, httptest.NewRequest("GET", "/live/static_server/", nil), http.StatusNotFound},
+		{"missing slash", LiveProxyHandler, httptest.NewRequest("GET", "/live/rest_api", nil), http.StatusMovedPermanently},
+		{"not running", LiveProxyHandler, httptest.NewRequest("GET", "/live/rest_api/api/books", nil), http.StatusServiceUnavailable},
+		{"unknown control page", LiveExampleHandler, httptest.NewRequest("GET", "/examples/live/nope", nil), http.StatusNotFound},
+		{"unknown action", LiveExampleHandler, postForm("/examples/live/rest_api", url.Values{"action": {"explode"}}, learner), http.StatusBadRequest},
+		{"stop when stopped", LiveExampleHandler, postForm("/examples/live/rest_api", url.Values{"action": {"stop"}}, learner), http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		tt.handler(rr, tt.req)
+		if rr.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, rr.Code, tt.status)
+		}
+	}
+
+	// Visitors have to log in before starting an example
+	rr := httptest.NewRecorder()
+	LiveExampleHandler(rr, postForm("/examples/live/rest_api", url.Values{"action": {"start"}}))
+	if loc := rr.Header().Get("Location"); rr.Code != http.StatusSeeOther || !strings.HasPrefix(loc, "/login?next=") {
+		t.Errorf("anonymous start: %d to %q", rr.Code, loc)
+	}
+
+	req := httptest.NewRequest("GET", "/examples/live/rest_api", nil)
+	req.Header.Set("Accept", "application/json")
+	rr = httptest.NewRecorder()
+	LiveExampleHandler(rr, req)
+	if body := rr.Body.String(); !strings.Contains(body, `"running": false`) {
+		t.Errorf("status JSON = %s", body)
+	}
+}
+
+func TestLiveStopOwner(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles Go code")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox requires Linux")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	owner := login(t, "live-owner")
+	other := login(t, "live-other")
+	admin := login(t, "live-admin", auth.RoleAdmin)
+
+	example, _ := content.FindExample("rest_api")
+	if _, err := Live.Start(context.Background(), "live-owner", "rest_api", example.Code, "/live/rest_api"); err != nil {
+		t.Fatal(err)
+	}
+	defer Live.Stop("rest_api")
+
+	tests := []struct {
+		name    string
+		cookie  *http.Cookie
+		status  int
+		running bool
+	}{
+		{"someone else", other, http.StatusForbidden, true},
+		{"admin", admin, http.StatusSeeOther, false},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		LiveExampleHandler(rr, postForm("/examples/live/rest_api", url.Values{"action": {"stop"}}, tt.cookie))
+		if _, running := Live.Get("rest_api"); rr.Code != tt.status || running != tt.running {
+			t.Errorf("%s: status %d, running %v", tt.name, rr.Code, running)
+		}
+	}
+
+	if _, err := Live.Start(context.Background(), "live-owner", "rest_api", example.Code, "/live/rest_api"); err != nil {
+		t.Fatal(err)
+	}
+	rr := httptest.NewRecorder()
+	LiveExampleHandler(rr, postForm("/examples/live/rest_api", url.Values{"action": {"stop"}}, owner))
+	if _, running := Live.Get("rest_api"); rr.Code != http.StatusSeeOther || running {
+		t.Errorf("owner: status %d, running %v", rr.Code, running)
+	}
+}
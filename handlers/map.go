@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"golang-webserver-tutorial/content"
+)
+
+// MapHandler shows the prerequisite map of every tutorial and the learning
+// paths, marking which tutorials the learner has unlocked
+func MapHandler(w http.ResponseWriter, r *http.Request) {
+	graph, err := content.LoadGraph()
+	if err != nil {
+		log.Printf("loading the prerequisite graph failed: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	summary := progressSummary(r)
+	data := TemplateData{
+		Title:       "Learning Map",
+		ActiveNav:   "map",
+		CurrentYear: time.Now().Year(),
+		Map:         summary.Map(graph),
+	}
+	for _, p := range content.GetPaths() {
+		path, err := summary.Path(graph, p)
+		if err != nil {
+			log.Printf("learning path %s: %v", p.ID, err)
+			continue
+		}
+		data.Paths = append(data.Paths, path)
+	}
+
+	parseTemplate(w, r, data, "templates/map.html")
+}
+
+// prerequisiteLink is a link to a tutorial's prerequisite
+type prerequisiteLink struct {
+	ID    string
+	Title string
+	URL   string
+}
+
+// prerequisites returns links to the tutorials t depends on
+func prerequisites(t content.Tutorial) []prerequisiteLink {
+	var links []prerequisiteLink
+	for _, id := range t.Prerequisites {
+		if p, level, ok := content.FindTutorial(id); ok {
+			links = append(links, prerequisiteLink{ID: p.ID, Title: p.Title, URL: level.Path + "#" + p.ID})
+		}
+	}
+	return links
+}
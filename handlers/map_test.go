@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"testing"
+
+	"golang-webserver-tutorial/content"
+)
+
+func TestPrerequisites(t *testing.T) {
+	tutorial, _, _ := content.FindTutorial("html-templates")
+	links := prerequisites(tutorial)
+	if len(links) != 2 || links[0].URL != "/basic#serve-html" || links[1].Title != "Handling Different URL Routes" {
+		t.Errorf("prerequisites = %+v", links)
+	}
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+
+	"golang-webserver-tutorial/playground"
+	"golang-webserver-tutorial/sandbox"
+)
+
+// Playground runs code edited in the browser
+var Playground = playground.New(Sandbox)
+
+// runRequest is the body accepted by /api/run
+type runRequest struct {
+	Source string `json:"source"`
+}
+
+// RunHandler builds and runs a Go program posted as {"source": "..."} and
+// streams its output as newline-delimited JSON events, ending with an "exit"
+// event. Errors before any output are returned as a JSON error object.
+func RunHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	var req runRequest
+	r.Body = http.MaxBytesReader(w, r.Body, playground.MaxSourceSize+1024)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "request body must be JSON with a source field"})
+		return
+	}
+	if len(req.Source) > playground.MaxSourceSize {
+		writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "program is too large"})
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+	var mu sync.Mutex
+	started := false
+	emit := func(event playground.Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !started {
+			started = true
+			w.Header().Set("Content-Type", "application/x-ndjson")
+			w.Header().Set("Cache-Control", "no-store")
+			w.Header().Set("X-Content-Type-Options", "nosniff")
+		}
+		enc.Encode(event)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	err := Playground.Execute(r.Context(), req.Source, emit)
+	if err == nil {
+		return
+	}
+
+	message, status := "the program could not be run", http.StatusInternalServerError
+	if errors.Is(err, sandbox.ErrBusy) {
+		message, status = "too many programs are running, try again shortly", http.StatusServiceUnavailable
+	} else {
+		log.Printf("playground run failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if started {
+		// Headers are already sent, so report the failure in the stream
+		enc.Encode(playground.Event{Kind: playground.KindExit, Data: message})
+		return
+	}
+	writeJSON(w, status, map[string]string{"error": message})
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/playground"
+)
+
+func TestRunHandlerErrors(t *testing.T) {
+	large, _ := json.Marshal(runRequest{Source: strings.Repeat("x", playground.MaxSourceSize+1)})
+
+	tests := []struct {
+		name   string
+		req    *http.Request
+		status int
+	}{
+		{"wrong method", httptest.NewRequest("GET", "/api/run", nil), http.StatusMethodNotAllowed},
+		{"not JSON", httptest.NewRequest("POST", "/api/run", strings.NewReader("package main")), http.StatusBadRequest},
+		{"too large", httptest.NewRequest("POST", "/api/run", strings.NewReader(string(large))), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		RunHandler(rr, tt.req)
+		if rr.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.name, rr.Code, tt.status)
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || body["error"] == "" {
+			t.Errorf("%s: body is not a JSON error: %s", tt.name, rr.Body.String())
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/progress"
+)
+
+// Progress stores which tutorials each learner has completed. It defaults to
+// an in-memory store; main replaces it with a persistent one.
+var Progress progress.Store = progress.NewMemoryStore()
+
+// learnerCookie identifies visitors who track progress without an account
+const learnerCookie = "learner"
+
+// learnerKey returns the progress key for the request: the logged in user, or
+// the anonymous learner cookie. ok is false when the visitor has neither.
+func learnerKey(r *http.Request) (key string, ok bool) {
+	if user, ok := Accounts.CurrentUser(r); ok {
+		return "user:" + user.ID, true
+	}
+	if id, ok := anonymousID(r); ok {
+		return "anon:" + id, true
+	}
+	return "", false
+}
+
+// anonymousID returns the learner cookie value if it looks like one we issued
+func anonymousID(r *http.Request) (string, bool) {
+	cookie, err := r.Cookie(learnerCookie)
+	if err != nil || len(cookie.Value) != 43 || strings.Trim(cookie.Value, "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_") != "" {
+		return "", false
+	}
+	return cookie.Value, true
+}
+
+// setLearnerCookie sets (or, with a negative maxAge, clears) the anonymous learner cookie
+func setLearnerCookie(w http.ResponseWriter, r *http.Request, value string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     learnerCookie,
+		Value:    value,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// ensureLearner returns the request's progress key, giving visitors without an
+// account or learner cookie a new anonymous learner cookie
+func ensureLearner(w http.ResponseWriter, r *http.Request) (string, error) {
+	if key, ok := learnerKey(r); ok {
+		return key, nil
+	}
+	id, err := auth.RandomID()
+	if err != nil {
+		return "", err
+	}
+	setLearnerCookie(w, r, id, 365*24*60*60)
+	return "anon:" + id, nil
+}
+
+// mergeAnonymousProgress moves progress recorded before logging in onto the
+// user's account and forgets the anonymous cookie
+func mergeAnonymousProgress(w http.ResponseWriter, r *http.Request, user auth.User) {
+	id, ok := anonymousID(r)
+	if !ok {
+		return
+	}
+	if err := Progress.Merge("anon:"+id, "user:"+user.ID); err != nil {
+		log.Printf("merging progress failed: %v", err)
+		return
+	}
+	setLearnerCookie(w, r, "", -1)
+}
+
+// progressSummary returns the learner's progress through every level
+func progressSummary(r *http.Request) progress.Summary {
+	var record progress.Record
+	if key, ok := learnerKey(r); ok {
+		var err error
+		if record, err = Progress.Get(key); err != nil {
+			log.Printf("loading progress failed: %v", err)
+		}
+	}
+	return progress.Summarize(content.GetLevels(), record)
+}
+
+// ProgressHandler marks a tutorial as complete (done=1) or not complete (done=0).
+// Visitors without an account are given an anonymous learner cookie so their
+// progress can be merged into an account later.
+func ProgressHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.ParseForm()
+	tutorial, level, ok := content.FindTutorial(r.PostForm.Get("tutorial"))
+	if !ok {
+		http.Error(w, "Unknown tutorial", http.StatusNotFound)
+		return
+	}
+
+	key, err := ensureLearner(w, r)
+	if err != nil {
+		http.Error(w, "Could not record progress", http.StatusInternalServerError)
+		return
+	}
+
+	if err := Progress.SetComplete(key, tutorial.ID, r.PostForm.Get("done") == "1"); err != nil {
+		log.Printf("saving progress failed: %v", err)
+		http.Error(w, "Could not record progress", http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, localRedirect(r.PostForm.Get("next"), level.Path+"#"+tutorial.ID), http.StatusSeeOther)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/progress"
+)
+
+// postForm builds a form POST carrying the given cookies
+func postForm(path string, form url.Values, cookies ...*http.Cookie) *http.Request {
+	req := httptest.NewRequest("POST", path, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	return req
+}
+
+func TestProgressHandler(t *testing.T) {
+	Accounts = auth.NewManager(auth.NewMemoryUserStore(), auth.NewMemorySessionStore(),
+		auth.Config{Params: auth.ScryptParams{LogN: 4, R: 1, P: 1}})
+	Progress = progress.NewMemoryStore()
+
+	rr := httptest.NewRecorder()
+	ProgressHandler(rr, postForm("/progress", url.Values{"tutorial": {"missing"}, "done": {"1"}}))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("unknown tutorial: status %d", rr.Code)
+	}
+
+	// Anonymous visitors get a learner cookie
+	rr = httptest.NewRecorder()
+	ProgressHandler(rr, postForm("/progress", url.Values{"tutorial": {"hello-world"}, "done": {"1"}}))
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/basic#hello-world" {
+		t.Fatalf("mark complete: status %d, location %q", rr.Code, rr.Header().Get("Location"))
+	}
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != learnerCookie {
+		t.Fatalf("no learner cookie set: %v", cookies)
+	}
+
+	// Registering moves anonymous progress onto the account
+	rr = httptest.NewRecorder()
+	RegisterHandler(rr, postForm("/register", url.Values{
+		"username": {"gopher"}, "password": {"password123"}, "confirm": {"password123"},
+	}, cookies[0]))
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("register: status %d", rr.Code)
+	}
+
+	user, err := Accounts.Users.ByUsername("gopher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, _ := Progress.Get("user:" + user.ID)
+	if _, ok := record.Completed["hello-world"]; !ok {
+		t.Error("anonymous progress was not merged into the account")
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/content"
+)
+
+// QuizHandler grades a tutorial quiz posted to /quiz/{tutorial-id}, records
+// the score against the learner and shows feedback for every answer
+func QuizHandler(w http.ResponseWriter, r *http.Request) {
+	tutorial, level, ok := content.FindTutorial(strings.TrimPrefix(r.URL.Path, "/quiz/"))
+	if !ok || tutorial.Quiz == nil {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodPost {
+		// The quiz itself is shown on the tutorial's level page
+		http.Redirect(w, r, level.Path+"#"+tutorial.ID+"-quiz", http.StatusSeeOther)
+		return
+	}
+
+	r.ParseForm()
+	result := tutorial.Quiz.Grade(r.PostForm)
+
+	key, err := ensureLearner(w, r)
+	if err == nil {
+		err = Progress.RecordQuiz(key, tutorial.ID, result.Score, result.Total)
+	}
+	if err != nil {
+		// Still show the feedback even if the score could not be kept
+		log.Printf("recording quiz score failed: %v", err)
+	}
+
+	data := TemplateData{
+		Title:       "Quiz: " + tutorial.Title,
+		ActiveNav:   level.ID,
+		CurrentYear: time.Now().Year(),
+		Tutorial:    &tutorial,
+		Level:       &level,
+		QuizResult:  &result,
+	}
+
+	parseTemplate(w, r, data, "templates/quiz.html")
+}
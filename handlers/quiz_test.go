@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang-webserver-tutorial/progress"
+)
+
+func TestQuizHandler(t *testing.T) {
+	Progress = progress.NewMemoryStore()
+
+	rr := httptest.NewRecorder()
+	QuizHandler(rr, postForm("/quiz/serve-html", url.Values{}))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("tutorial without a quiz: status %d", rr.Code)
+	}
+
+	rr = httptest.NewRecorder()
+	QuizHandler(rr, httptest.NewRequest("GET", "/quiz/hello-world", nil))
+	if rr.Code != http.StatusSeeOther || rr.Header().Get("Location") != "/basic#hello-world-quiz" {
+		t.Errorf("GET: status %d, location %q", rr.Code, rr.Header().Get("Location"))
+	}
+
+	// The score is recorded against the anonymous learner even though the
+	// results page cannot be rendered without the real templates
+	rr = httptest.NewRecorder()
+	QuizHandler(rr, postForm("/quiz/hello-world", url.Values{"handlefunc": {"b"}, "listen": {"ListenAndServe"}}))
+	cookies := rr.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != learnerCookie {
+		t.Fatalf("no learner cookie set: %v", cookies)
+	}
+	record, _ := Progress.Get("anon:" + cookies[0].Value)
+	if quiz := record.Quizzes["hello-world"]; quiz.Last != 2 || quiz.Total != 3 {
+		t.Errorf("recorded score = %+v, want 2 of 3", quiz)
+	}
+}
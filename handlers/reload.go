@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/reload"
+)
+
+// Reloads tells open pages when the files they are built from change
+var Reloads = reload.NewHub()
+
+// HotReload adds a script to every page that reloads it when files change;
+// main turns it on with the HOT_RELOAD environment variable
+var HotReload bool
+
+// ApplyChanges reloads whatever the changed files affect and tells open
+// pages about it. When templates or content fail to load, the last good
+// version stays in use and pages are told why instead. Only templates,
+// static files and the edited content in data/ can be reloaded; the built-in
+// content in the content package is compiled in, so other paths are ignored.
+func ApplyChanges(paths []string) {
+	var templates, edits, static bool
+	var problems []string
+	for _, path := range paths {
+		path = filepath.ToSlash(path)
+		switch {
+		case strings.HasPrefix(path, "templates/"):
+			templates = true
+		case strings.HasPrefix(path, "static/"):
+			// Served straight from disk, so pages only need reloading
+			static = true
+		case strings.HasPrefix(path, "data/"):
+			edits = true
+		}
+	}
+
+	changed := static
+	if templates {
+		if err := ReloadTemplates(); err != nil {
+			problems = append(problems, err.Error())
+		} else {
+			changed = true
+		}
+	}
+	if edits {
+		// The store saves the file itself after every edit made on the site,
+		// in which case nothing has changed
+		reloaded, err := Authoring.Reload()
+		if err != nil {
+			problems = append(problems, err.Error())
+		} else if reloaded {
+			publishContent()
+			changed = true
+		}
+	}
+
+	event := reload.Event{Paths: paths}
+	switch {
+	case len(problems) > 0:
+		event.Error = strings.Join(problems, "; ")
+		log.Printf("Reloading %s: %s", strings.Join(paths, ", "), event.Error)
+	case changed:
+		log.Printf("Reloaded %s", strings.Join(paths, ", "))
+	default:
+		return
+	}
+	Reloads.Publish(event)
+}
+
+// ReloadEventsHandler streams a Server-Sent Event to the page each time files
+// change: "reload" when the changes were applied and "reload-failed" when the
+// last good version is still being served. Connections are closed by the
+// server's write timeout and the browser reconnects.
+func ReloadEventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	events := Reloads.Subscribe()
+	defer Reloads.Unsubscribe(events)
+
+	// Reconnect quickly so no change is missed for long
+	fmt.Fprint(w, "retry: 1000\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case event := <-events:
+			name := "reload"
+			if event.Error != "" {
+				name = "reload-failed"
+			}
+			data, _ := json.Marshal(event)
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bufio"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/reload"
+)
+
+// renderPage executes a cached page template and returns its output
+func renderPage(t *testing.T, file string) string {
+	t.Helper()
+	tmpl, err := pageTemplate(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := tmpl.ExecuteTemplate(&out, "layout", TemplateData{}); err != nil {
+		t.Fatal(err)
+	}
+	return out.String()
+}
+
+func TestApplyChanges(t *testing.T) {
+	wd, _ := os.Getwd()
+	dir := t.TempDir()
+	os.Mkdir(filepath.Join(dir, "templates"), 0755)
+	os.Chdir(dir)
+	t.Cleanup(func() {
+		os.Chdir(wd)
+		templateCache.pages = make(map[string]*template.Template)
+	})
+	write := func(name, text string) {
+		if err := os.WriteFile(filepath.Join("templates", name), []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("layout.html", `{{define "layout"}}[{{template "content" .}}]{{end}}`)
+	write("tutorial.html", `{{define "tutorial"}}{{end}}`)
+	write("page.html", `{{define "content"}}one{{end}}`)
+	if err := ReloadTemplates(); err != nil {
+		t.Fatal(err)
+	}
+	if got := renderPage(t, "templates/page.html"); got != "[one]" {
+		t.Fatalf("page = %q", got)
+	}
+
+	events := Reloads.Subscribe()
+	defer Reloads.Unsubscribe(events)
+
+	// A broken template keeps the last good version
+	write("page.html", `{{define "content"}}two{{end}`)
+	ApplyChanges([]string{"templates/page.html"})
+	if e := <-events; e.Error == "" {
+		t.Errorf("no error for a broken template: %+v", e)
+	}
+	if got := renderPage(t, "templates/page.html"); got != "[one]" {
+		t.Errorf("page = %q after a broken edit", got)
+	}
+
+	write("page.html", `{{define "content"}}two{{end}}`)
+	ApplyChanges([]string{"templates/page.html", "static/css/style.css"})
+	if e := <-events; e.Error != "" || len(e.Paths) != 2 {
+		t.Errorf("event = %+v", e)
+	}
+	if got := renderPage(t, "templates/page.html"); got != "[two]" {
+		t.Errorf("page = %q after fixing it", got)
+	}
+
+	// Go source is compiled in, so changes to it are not reported
+	ApplyChanges([]string{"content/tutorials.go"})
+	select {
+	case e := <-events:
+		t.Errorf("event for Go source = %+v", e)
+	default:
+	}
+}
+
+func TestReloadEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(ReloadEventsHandler))
+	defer srv.Close()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	next := func() string {
+		if !lines.Scan() {
+			t.Fatal("stream ended")
+		}
+		return lines.Text()
+	}
+	if line := next(); line != "retry: 1000" {
+		t.Fatalf("first line = %q", line)
+	}
+	next()
+
+	Reloads.Publish(reload.Event{Paths: []string{"static/css/style.css"}})
+	if line := next(); line != "event: reload" {
+		t.Errorf("event line = %q", line)
+	}
+	if line := next(); line != `data: {"paths":["static/css/style.css"]}` {
+		t.Errorf("data line = %q", line)
+	}
+	next()
+
+	Reloads.Publish(reload.Event{Paths: []string{"templates/home.html"}, Error: "bad"})
+	if line := next(); line != "event: reload-failed" {
+		t.Errorf("event line = %q", line)
+	}
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/authoring"
+)
+
+// adminReview is the review page for the draft of one tutorial or example
+type adminReview struct {
+	Kind     string
+	Entry    authoring.Entry
+	Draft    authoring.Revision
+	Diffs    []authoring.FieldDiff
+	State    authoring.ReviewState
+	Required int
+
+	// Field and Line are the line picked for a new comment
+	Field string
+	Line  int
+
+	Errors map[string]string
+}
+
+// URL returns the review page address
+func (r adminReview) URL() string {
+	return reviewURL(r.Kind, r.Entry.ID)
+}
+
+// EditorURL returns the address of the draft's editor
+func (r adminReview) EditorURL() string {
+	return adminURL(r.Kind, r.Entry.ID)
+}
+
+// CommentsOn returns the comments on a line of the draft; line zero returns
+// the comments on the field as a whole
+func (r adminReview) CommentsOn(field string, line int) []authoring.Comment {
+	var comments []authoring.Comment
+	for _, c := range r.Entry.DraftComments() {
+		if c.Field == field && c.Line == line {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// Earlier returns the comments left on earlier drafts
+func (r adminReview) Earlier() []authoring.Comment {
+	var comments []authoring.Comment
+	for _, c := range r.Entry.Comments {
+		if c.Revision != r.Draft.Number {
+			comments = append(comments, c)
+		}
+	}
+	return comments
+}
+
+// reviewURL returns the review page address for a tutorial or example
+func reviewURL(kind, id string) string {
+	return "/admin/review/" + kind + "s/" + url.PathEscape(id)
+}
+
+// AdminReviewHandler shows the draft of a tutorial or example as a diff of
+// each field against the published version at /admin/review/{tutorials|examples}/{id},
+// and takes comments and reviewers' verdicts
+func AdminReviewHandler(w http.ResponseWriter, r *http.Request) {
+	kind, id, ok := adminTarget("/admin/" + strings.TrimPrefix(r.URL.Path, "/admin/review/"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	entry, ok := Authoring.Entry(kind, id)
+	if !ok || entry.Draft == 0 {
+		http.NotFound(w, r)
+		return
+	}
+
+	review := adminReview{Kind: kind, Required: Authoring.RequiredApprovals}
+	review.Field = r.URL.Query().Get("field")
+	review.Line, _ = strconv.Atoi(r.URL.Query().Get("line"))
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		user, _ := Accounts.CurrentUser(r)
+		status, err := reviewDraft(kind, id, r.PostForm, user)
+		if err == nil {
+			http.Redirect(w, r, reviewURL(kind, id), http.StatusSeeOther)
+			return
+		}
+		w.WriteHeader(status)
+		review.Errors = map[string]string{"": err.Error()}
+		var invalid authoring.InvalidError
+		if errors.As(err, &invalid) {
+			review.Errors = invalid
+		}
+		review.Field = r.PostForm.Get("field")
+		review.Line, _ = strconv.Atoi(r.PostForm.Get("line"))
+		entry, _ = Authoring.Entry(kind, id)
+	}
+
+	review.Entry = entry
+	review.Draft, _ = entry.Revision(entry.Draft)
+	review.State = entry.ReviewState()
+	live, _ := liveDocument(kind, id)
+	review.Diffs = authoring.Diff(live, review.Draft.Doc)
+
+	data := TemplateData{
+		Title:       "Review " + review.Draft.Doc.Title(),
+		ActiveNav:   "admin",
+		CurrentYear: time.Now().Year(),
+		Review:      &review,
+	}
+	parseTemplate(w, r, data, "templates/admin_review.html")
+}
+
+// reviewDraft leaves the comment or verdict posted from the review page and
+// returns the status to show the page again with if it fails
+func reviewDraft(kind, id string, form url.Values, user auth.User) (int, error) {
+	switch form.Get("action") {
+	case "comment":
+		if !user.Can(auth.PermComment) {
+			return http.StatusForbidden, errors.New("you cannot comment")
+		}
+		line, _ := strconv.Atoi(form.Get("line"))
+		_, err := Authoring.AddComment(kind, id, authoring.Comment{
+			Author: user.Username,
+			Field:  form.Get("field"),
+			Line:   line,
+			Body:   form.Get("body"),
+		})
+		return reviewStatus(err), err
+
+	case "review":
+		if !user.Can(auth.PermReview) {
+			return http.StatusForbidden, errors.New("only reviewers and admins can approve drafts")
+		}
+		_, err := Authoring.AddReview(kind, id, user.Username, form.Get("verdict"), form.Get("body"))
+		return reviewStatus(err), err
+	}
+	return http.StatusBadRequest, errors.New("unknown action")
+}
+
+// reviewStatus returns the status for an error from leaving a review
+func reviewStatus(err error) int {
+	var invalid authoring.InvalidError
+	switch {
+	case err == nil:
+		return http.StatusOK
+	case errors.As(err, &invalid):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, authoring.ErrOwnDraft):
+		return http.StatusForbidden
+	}
+	return http.StatusConflict
+}
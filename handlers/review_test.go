@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/authoring"
+	"golang-webserver-tutorial/content"
+)
+
+func TestAdminReview(t *testing.T) {
+	Accounts = auth.NewManager(auth.NewMemoryUserStore(), auth.NewMemorySessionStore(),
+		auth.Config{Params: auth.ScryptParams{LogN: 4, R: 1, P: 1}})
+	Authoring = authoring.NewMemoryStore()
+	examplesDir = t.TempDir()
+	t.Cleanup(func() { content.SetPublished(content.Published{}) })
+
+	author, reviewer := login(t, "Ada", auth.RoleAuthor), login(t, "Rex", auth.RoleReviewer)
+	hello, _, _ := content.FindTutorial("hello-world")
+	hello.Title = "Hello, Web"
+	if _, err := Authoring.SaveDraft(authoring.Document{Kind: authoring.KindTutorial, Level: "basic", Tutorial: &hello}, "Ada", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	post := func(path string, form url.Values, cookie *http.Cookie) int {
+		rr := httptest.NewRecorder()
+		if path == "/admin/tutorials/hello-world" {
+			AdminEditHandler(rr, postForm(path, form, cookie))
+		} else {
+			AdminReviewHandler(rr, postForm(path, form, cookie))
+		}
+		return rr.Code
+	}
+	const review = "/admin/review/tutorials/hello-world"
+	tests := []struct {
+		name   string
+		path   string
+		form   url.Values
+		cookie *http.Cookie
+		want   int
+	}{
+		{"publish unreviewed", "/admin/tutorials/hello-world", url.Values{"action": {"publish"}}, reviewer, http.StatusConflict},
+		{"author comments", review, url.Values{"action": {"comment"}, "field": {"code.1"}, "line": {"5"}, "body": {"Why 8080?"}}, author, http.StatusSeeOther},
+		{"comment on a missing field", review, url.Values{"action": {"comment"}, "field": {"quiz"}, "body": {"x"}}, reviewer, http.StatusUnprocessableEntity},
+		{"author approves", review, url.Values{"action": {"review"}, "verdict": {"approve"}}, author, http.StatusForbidden},
+		{"reviewer asks for changes", review, url.Values{"action": {"review"}, "verdict": {"changes"}}, reviewer, http.StatusSeeOther},
+		{"publish with changes requested", "/admin/tutorials/hello-world", url.Values{"action": {"publish"}}, reviewer, http.StatusConflict},
+		{"reviewer approves", review, url.Values{"action": {"review"}, "verdict": {"approve"}}, reviewer, http.StatusSeeOther},
+		{"publish approved", "/admin/tutorials/hello-world", url.Values{"action": {"publish"}}, reviewer, http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		if got := post(tt.path, tt.form, tt.cookie); got != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	if tutorial, _, _ := content.FindTutorial("hello-world"); tutorial.Title != "Hello, Web" {
+		t.Errorf("title = %q after publishing", tutorial.Title)
+	}
+	e, _ := Authoring.Entry(authoring.KindTutorial, "hello-world")
+	if len(e.Comments) != 1 || e.Comments[0].Author != "Ada" || e.Comments[0].Line != 5 {
+		t.Errorf("comments = %+v", e.Comments)
+	}
+
+	// There is nothing to review once the draft is published
+	req := httptest.NewRequest("GET", review, nil)
+	req.AddCookie(reviewer)
+	rr := httptest.NewRecorder()
+	AdminReviewHandler(rr, req)
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("review without a draft: status %d", rr.Code)
+	}
+}
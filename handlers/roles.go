@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang-webserver-tutorial/auth"
+)
+
+// Register adds routes to mux, wrapping each handler so only users with the
+// route's permission can reach it and every response has security headers
+func Register(mux *http.ServeMux, routes []Route) {
+	for _, route := range routes {
+		mux.Handle(route.Pattern, Secure(route.Security, Require(route.Permission, route.Handler)))
+	}
+}
+
+// Require only lets users with permission p through to next. Visitors who
+// are not logged in are sent to the login page and logged in users without
+// the permission get 403 Forbidden. Anyone may read.
+func Require(p auth.Permission, next http.Handler) http.Handler {
+	if p == "" || p == auth.PermRead {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, ok := Accounts.CurrentUser(r)
+		if !ok {
+			http.Redirect(w, r, "/login?next="+url.QueryEscape(r.URL.RequestURI()), http.StatusSeeOther)
+			return
+		}
+		if !user.Can(p) {
+			http.Error(w, "You do not have permission to do that", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// can reports whether the user may do p; visitors who are not logged in
+// may only read
+func can(user *auth.User, p auth.Permission) bool {
+	if user == nil {
+		return p == auth.PermRead
+	}
+	return user.Can(p)
+}
+
+// AdminUsersHandler lists users and lets admins change their roles
+func AdminUsersHandler(w http.ResponseWriter, r *http.Request) {
+	data := TemplateData{
+		Title:       "Users",
+		ActiveNav:   "admin",
+		CurrentYear: time.Now().Year(),
+		Roles:       auth.AllRoles[1:],
+	}
+
+	if r.Method == http.MethodPost {
+		r.ParseForm()
+		current, _ := Accounts.CurrentUser(r)
+		err := setRoles(current, r.PostForm)
+		if err == nil {
+			http.Redirect(w, r, "/admin/users", http.StatusSeeOther)
+			return
+		}
+		switch {
+		case errors.Is(err, auth.ErrUserNotFound):
+			w.WriteHeader(http.StatusNotFound)
+			data.Error = "There is no user with that name."
+		case errors.Is(err, auth.ErrUnknownRole), errors.Is(err, errOwnAdmin):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			data.Error = err.Error()
+		default:
+			log.Printf("changing roles failed: %v", err)
+			http.Error(w, "Could not change roles", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	users, err := Accounts.ListUsers()
+	if err != nil {
+		log.Printf("listing users failed: %v", err)
+		http.Error(w, "Could not list users", http.StatusInternalServerError)
+		return
+	}
+	data.Users = users
+	parseTemplate(w, r, data, "templates/admin_users.html")
+}
+
+// errOwnAdmin stops admins from locking themselves out
+var errOwnAdmin = errors.New("you cannot remove your own admin role")
+
+// setRoles gives the user named in the form the roles ticked in it
+func setRoles(current auth.User, form url.Values) error {
+	var roles []auth.Role
+	for _, role := range form["role"] {
+		roles = append(roles, auth.Role(role))
+	}
+	username := form.Get("username")
+	if username == current.Username {
+		keepsAdmin := false
+		for _, role := range roles {
+			keepsAdmin = keepsAdmin || role == auth.RoleAdmin
+		}
+		if !keepsAdmin {
+			return errOwnAdmin
+		}
+	}
+	_, err := Accounts.SetRoles(username, roles)
+	return err
+}
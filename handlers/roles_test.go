@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/authoring"
+)
+
+func TestRequire(t *testing.T) {
+	Accounts = auth.NewManager(auth.NewMemoryUserStore(), auth.NewMemorySessionStore(),
+		auth.Config{Params: auth.ScryptParams{LogN: 4, R: 1, P: 1}})
+	Authoring = authoring.NewMemoryStore()
+	cookies := make(map[auth.Role]*http.Cookie)
+	for _, role := range auth.AllRoles {
+		cookies[role] = login(t, "user-"+string(role), role)
+	}
+	mux := http.NewServeMux()
+	Register(mux, Routes())
+
+	protected := 0
+	for _, route := range Routes() {
+		if route.Permission == "" {
+			continue
+		}
+		protected++
+		path := route.Pattern
+		if strings.HasSuffix(path, "/") {
+			path += "missing"
+		}
+
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest("GET", path, nil))
+		if rr.Code != http.StatusSeeOther || !strings.HasPrefix(rr.Header().Get("Location"), "/login?next=") {
+			t.Errorf("%s as a visitor: status %d, location %q", path, rr.Code, rr.Header().Get("Location"))
+		}
+
+		for _, role := range auth.AllRoles {
+			req := httptest.NewRequest("GET", path, nil)
+			req.AddCookie(cookies[role])
+			rr := httptest.NewRecorder()
+			mux.ServeHTTP(rr, req)
+			if forbidden := rr.Code == http.StatusForbidden; forbidden == role.Can(route.Permission) {
+				t.Errorf("%s as %s: status %d", path, role, rr.Code)
+			}
+		}
+	}
+	if protected < 5 {
+		t.Errorf("only %d protected routes", protected)
+	}
+}
+
+func TestAdminUsers(t *testing.T) {
+	Accounts = auth.NewManager(auth.NewMemoryUserStore(), auth.NewMemorySessionStore(),
+		auth.Config{Params: auth.ScryptParams{LogN: 4, R: 1, P: 1}, Admins: []string{"root"}})
+	admin := login(t, "root")
+	login(t, "gopher")
+
+	rr := httptest.NewRecorder()
+	AdminUsersHandler(rr, postForm("/admin/users", url.Values{"username": {"gopher"}, "role": {"author", "reviewer"}}, admin))
+	if rr.Code != http.StatusSeeOther {
+		t.Fatalf("status %d", rr.Code)
+	}
+	if user, _ := Accounts.Users.ByUsername("gopher"); len(user.Roles) != 2 || !user.Can(auth.PermPublish) {
+		t.Errorf("roles = %v", user.Roles)
+	}
+
+	tests := []struct {
+		form url.Values
+		want int
+	}{
+		{url.Values{"username": {"nobody"}, "role": {"author"}}, http.StatusNotFound},
+		{url.Values{"username": {"gopher"}, "role": {"wizard"}}, http.StatusUnprocessableEntity},
+		{url.Values{"username": {"root"}, "role": {"author"}}, http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		AdminUsersHandler(rr, postForm("/admin/users", tt.form, admin))
+		if rr.Code != tt.want {
+			t.Errorf("%v: status %d, want %d", tt.form, rr.Code, tt.want)
+		}
+	}
+}
+
+func TestDraftOwnership(t *testing.T) {
+	Accounts = auth.NewManager(auth.NewMemoryUserStore(), auth.NewMemorySessionStore(),
+		auth.Config{Params: auth.ScryptParams{LogN: 4, R: 1, P: 1}})
+	Authoring = authoring.NewMemoryStore()
+	owner, other, reviewer := login(t, "Ada", auth.RoleAuthor), login(t, "Bob", auth.RoleAuthor), login(t, "Rex", auth.RoleReviewer)
+
+	doc, _ := liveDocument(authoring.KindTutorial, "hello-world")
+	if _, err := Authoring.SaveDraft(doc, "Ada", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the draft's author and those who can publish may drop or replace it
+	tests := []struct {
+		name   string
+		form   url.Values
+		cookie *http.Cookie
+		want   int
+	}{
+		{"another author discards", url.Values{"action": {"discard"}}, other, http.StatusForbidden},
+		{"another author reverts", url.Values{"action": {"revert"}, "rev": {"1"}}, other, http.StatusForbidden},
+		{"author reverts", url.Values{"action": {"revert"}, "rev": {"1"}}, owner, http.StatusSeeOther},
+		{"reviewer discards", url.Values{"action": {"discard"}}, reviewer, http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		rr := httptest.NewRecorder()
+		AdminEditHandler(rr, postForm("/admin/tutorials/hello-world", tt.form, tt.cookie))
+		if rr.Code != tt.want {
+			t.Errorf("%s: status %d, want %d", tt.name, rr.Code, tt.want)
+		}
+	}
+	if _, ok := Authoring.Entry(authoring.KindTutorial, "hello-world"); ok {
+		t.Error("draft was not discarded")
+	}
+}
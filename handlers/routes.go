@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"golang-webserver-tutorial/auth"
+	"golang-webserver-tutorial/content"
+)
+
+// Route describes a URL pattern served by the tutorial site
+type Route struct {
+	Pattern string
+	Handler http.Handler
+
+	// Page marks routes that render an HTML page listed in the sitemap
+	Page bool
+
+	// LastMod reports when the content behind a page last changed
+	LastMod func() time.Time
+
+	// NoIndex asks crawlers to skip the route in robots.txt
+	NoIndex bool
+
+	// Permission is needed to use the route; empty means anyone may
+	Permission auth.Permission
+
+	// Security adjusts the security headers sent with the route's responses;
+	// nil sends Security as it is
+	Security func(SecurityHeaders) SecurityHeaders
+}
+
+// Routes returns every route served by the site in registration order
+func Routes() []Route {
+	return []Route{
+		{Pattern: "/static/", Handler: http.StripPrefix("/static/", http.FileServer(http.Dir("static")))},
+		{Pattern: "/", Handler: http.HandlerFunc(HomeHandler), Page: true, LastMod: content.LastUpdated},
+		{Pattern: "/basic", Handler: http.HandlerFunc(BasicHandler), Page: true, LastMod: levelUpdated("basic")},
+		{Pattern: "/intermediate", Handler: http.HandlerFunc(IntermediateHandler), Page: true, LastMod: levelUpdated("intermediate")},
+		{Pattern: "/advanced", Handler: http.HandlerFunc(AdvancedHandler), Page: true, LastMod: levelUpdated("advanced")},
+		{Pattern: "/restful", Handler: http.HandlerFunc(RestfulHandler), Page: true, LastMod: levelUpdated("restful")},
+		{Pattern: "/map", Handler: http.HandlerFunc(MapHandler), Page: true, LastMod: content.LastUpdated},
+		{Pattern: "/examples", Handler: http.HandlerFunc(ExamplesHandler), Page: true, LastMod: examplesUpdated},
+		{Pattern: "/examples/live/", Handler: http.HandlerFunc(LiveExampleHandler), NoIndex: true},
+		{Pattern: "/live/", Handler: http.HandlerFunc(LiveProxyHandler), NoIndex: true, Security: withoutCSP},
+		{Pattern: "/download/", Handler: http.HandlerFunc(DownloadHandler), NoIndex: true},
+		{Pattern: "/book", Handler: http.HandlerFunc(BookHandler), Page: true, LastMod: content.LastUpdated},
+		{Pattern: "/book.epub", Handler: http.HandlerFunc(EPUBHandler), NoIndex: true},
+		{Pattern: "/tutorials/", Handler: http.HandlerFunc(TutorialCodeHandler), NoIndex: true},
+		{Pattern: "/api/tutorials", Handler: http.HandlerFunc(APITutorialsHandler), NoIndex: true},
+		{Pattern: "/api/tutorials/", Handler: http.HandlerFunc(APITutorialsHandler), NoIndex: true},
+		{Pattern: "/login", Handler: http.HandlerFunc(LoginHandler), NoIndex: true},
+		{Pattern: "/register", Handler: http.HandlerFunc(RegisterHandler), NoIndex: true},
+		{Pattern: "/logout", Handler: http.HandlerFunc(LogoutHandler), NoIndex: true},
+		{Pattern: "/progress", Handler: http.HandlerFunc(ProgressHandler), NoIndex: true},
+		{Pattern: "/quiz/", Handler: http.HandlerFunc(QuizHandler), NoIndex: true},
+		{Pattern: "/exercise/", Handler: http.HandlerFunc(ExerciseHandler), NoIndex: true},
+		{Pattern: "/api/run", Handler: http.HandlerFunc(RunHandler), NoIndex: true},
+		{Pattern: "/sandbox", Handler: http.HandlerFunc(SandboxHandler), Page: true, LastMod: levelUpdated("restful")},
+		{Pattern: "/sandbox/", Handler: http.HandlerFunc(SandboxHandler), NoIndex: true},
+		{Pattern: "/conformance", Handler: http.HandlerFunc(ConformanceHandler), Page: true, LastMod: levelUpdated("restful")},
+		{Pattern: "/inspect", Handler: http.HandlerFunc(InspectHandler), Page: true},
+		{Pattern: "/inspect/", Handler: http.HandlerFunc(InspectHandler), NoIndex: true},
+		{Pattern: "/admin", Handler: http.HandlerFunc(AdminHandler), NoIndex: true, Permission: auth.PermAuthor},
+		{Pattern: "/admin/tutorials/", Handler: http.HandlerFunc(AdminEditHandler), NoIndex: true, Permission: auth.PermAuthor},
+		{Pattern: "/admin/examples/", Handler: http.HandlerFunc(AdminEditHandler), NoIndex: true, Permission: auth.PermAuthor},
+		{Pattern: "/admin/review/", Handler: http.HandlerFunc(AdminReviewHandler), NoIndex: true, Permission: auth.PermAuthor},
+		{Pattern: "/admin/preview", Handler: http.HandlerFunc(AdminPreviewHandler), NoIndex: true, Permission: auth.PermAuthor},
+		{Pattern: "/admin/users", Handler: http.HandlerFunc(AdminUsersHandler), NoIndex: true, Permission: auth.PermManageUsers},
+		{Pattern: "/events/reload", Handler: http.HandlerFunc(ReloadEventsHandler), NoIndex: true},
+		{Pattern: "/csp-report", Handler: http.HandlerFunc(CSPReportHandler), NoIndex: true},
+		{Pattern: "/feed.atom", Handler: http.HandlerFunc(AtomFeedHandler)},
+		{Pattern: "/feed.rss", Handler: http.HandlerFunc(RSSFeedHandler)},
+		{Pattern: "/sitemap.xml", Handler: http.HandlerFunc(SitemapHandler)},
+		{Pattern: "/robots.txt", Handler: http.HandlerFunc(RobotsHandler)},
+	}
+}
+
+// levelUpdated returns a LastMod function for the level with the given ID
+func levelUpdated(id string) func() time.Time {
+	return func() time.Time {
+		for _, level := range content.GetLevels() {
+			if level.ID == id {
+				return level.Updated()
+			}
+		}
+		return time.Time{}
+	}
+}
+
+// examplesUpdated returns the most recent update time of any code example
+func examplesUpdated() time.Time {
+	var latest time.Time
+	for _, example := range content.GetCodeExamples() {
+		if example.Updated.After(latest) {
+			latest = example.Updated
+		}
+	}
+	return latest
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SecurityHeaders are the security headers sent with a response
+type SecurityHeaders struct {
+	// CSP lists the Content-Security-Policy directives. "{nonce}" is replaced
+	// by the request's nonce. No policy is sent when it is empty.
+	CSP []string
+
+	// ReportOnly sends the policy as Content-Security-Policy-Report-Only, so
+	// browsers report violations without blocking anything
+	ReportOnly bool
+
+	// ReportURI receives the violation reports
+	ReportURI string
+
+	// FrameAncestors says which pages may frame the response, such as
+	// 'none' or 'self'. It is also sent as X-Frame-Options, which browsers
+	// enforce even when the policy is report-only.
+	FrameAncestors string
+
+	ReferrerPolicy    string
+	PermissionsPolicy string
+
+	// HSTSMaxAge is sent as Strict-Transport-Security on TLS connections
+	HSTSMaxAge time.Duration
+}
+
+// DefaultSecurity only lets pages load scripts, styles and connections from
+// the site itself. The layout has no inline scripts; any that are added need
+// the request's nonce.
+func DefaultSecurity() SecurityHeaders {
+	return SecurityHeaders{
+		CSP: []string{
+			"default-src 'self'",
+			"script-src 'self' 'nonce-{nonce}'",
+			"style-src 'self'",
+			"img-src 'self' https: data:",
+			"connect-src 'self'",
+			"object-src 'none'",
+			"base-uri 'self'",
+			"form-action 'self'",
+		},
+		ReportURI:         "/csp-report",
+		FrameAncestors:    "'none'",
+		ReferrerPolicy:    "strict-origin-when-cross-origin",
+		PermissionsPolicy: "camera=(), microphone=(), geolocation=(), payment=(), usb=()",
+		HSTSMaxAge:        365 * 24 * time.Hour,
+	}
+}
+
+// Security is sent with every response, adjusted by each route's Security
+// function. main sends the policy report-only with CSP_REPORT_ONLY=1.
+var Security = DefaultSecurity()
+
+// withoutCSP sends no Content-Security-Policy, for pages the site does not
+// write itself
+func withoutCSP(h SecurityHeaders) SecurityHeaders {
+	h.CSP = nil
+	return h
+}
+
+type nonceKey struct{}
+
+// Secure sets the security headers on every response from next, adjusted for
+// the route by adjust if it is not nil, and gives each request a fresh nonce
+func Secure(adjust func(SecurityHeaders) SecurityHeaders, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := Security
+		if adjust != nil {
+			h = adjust(h)
+		}
+		nonce := newNonce()
+		h.write(w.Header(), r, nonce)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), nonceKey{}, nonce)))
+	})
+}
+
+// Nonce returns the request's Content-Security-Policy nonce, for inline
+// scripts and the script tags in the layout
+func Nonce(r *http.Request) string {
+	nonce, _ := r.Context().Value(nonceKey{}).(string)
+	return nonce
+}
+
+// newNonce returns 128 random bits for a nonce
+func newNonce() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("security: no randomness for a nonce: " + err.Error())
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// write sets the headers for a response to r
+func (h SecurityHeaders) write(header http.Header, r *http.Request, nonce string) {
+	header.Set("X-Content-Type-Options", "nosniff")
+	if h.ReferrerPolicy != "" {
+		header.Set("Referrer-Policy", h.ReferrerPolicy)
+	}
+	if h.PermissionsPolicy != "" {
+		header.Set("Permissions-Policy", h.PermissionsPolicy)
+	}
+	switch h.FrameAncestors {
+	case "'none'":
+		header.Set("X-Frame-Options", "DENY")
+	case "'self'":
+		header.Set("X-Frame-Options", "SAMEORIGIN")
+	}
+	if h.HSTSMaxAge > 0 && r.TLS != nil {
+		header.Set("Strict-Transport-Security", "max-age="+strconv.Itoa(int(h.HSTSMaxAge.Seconds())))
+	}
+
+	if len(h.CSP) == 0 {
+		return
+	}
+	directives := make([]string, 0, len(h.CSP)+3)
+	for _, d := range h.CSP {
+		directives = append(directives, strings.ReplaceAll(d, "{nonce}", nonce))
+	}
+	if h.FrameAncestors != "" {
+		directives = append(directives, "frame-ancestors "+h.FrameAncestors)
+	}
+	if h.ReportURI != "" {
+		directives = append(directives, "report-uri "+h.ReportURI, "report-to csp")
+		header.Set("Reporting-Endpoints", `csp="`+h.ReportURI+`"`)
+	}
+	name := "Content-Security-Policy"
+	if h.ReportOnly {
+		name += "-Report-Only"
+	}
+	header.Set(name, strings.Join(directives, "; "))
+}
+
+// cspViolation is the part of a violation report that is logged. Browsers
+// send report-uri reports with hyphenated names and Reporting API reports
+// in camel case.
+type cspViolation struct {
+	DocumentURI        string `json:"document-uri"`
+	BlockedURI         string `json:"blocked-uri"`
+	ViolatedDirective  string `json:"violated-directive"`
+	DocumentURL        string `json:"documentURL"`
+	BlockedURL         string `json:"blockedURL"`
+	EffectiveDirective string `json:"effectiveDirective"`
+	Disposition        string `json:"disposition"`
+}
+
+// String describes the violation on one line
+func (v cspViolation) String() string {
+	document, blocked, directive := v.DocumentURI, v.BlockedURI, v.ViolatedDirective
+	if document == "" {
+		document, blocked, directive = v.DocumentURL, v.BlockedURL, v.EffectiveDirective
+	}
+	if blocked == "" {
+		blocked = "inline"
+	}
+	s := blocked + " on " + document + " violates " + directive
+	if v.Disposition == "report" {
+		s += " (report only)"
+	}
+	return s
+}
+
+// maxReportSize limits the size of a violation report
+const maxReportSize = 64 << 10
+
+// CSPReportHandler logs the Content-Security-Policy violations that browsers
+// report to /csp-report, in either the report-uri or Reporting API format
+func CSPReportHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxReportSize))
+	if err != nil {
+		http.Error(w, "Could not read the report", http.StatusBadRequest)
+		return
+	}
+
+	var violations []cspViolation
+	var single struct {
+		Report *cspViolation `json:"csp-report"`
+	}
+	var batch []struct {
+		Type string       `json:"type"`
+		Body cspViolation `json:"body"`
+	}
+	switch {
+	case json.Unmarshal(body, &single) == nil && single.Report != nil:
+		violations = append(violations, *single.Report)
+	case json.Unmarshal(body, &batch) == nil:
+		for _, report := range batch {
+			if report.Type == "csp-violation" {
+				violations = append(violations, report.Body)
+			}
+		}
+	default:
+		http.Error(w, "Not a violation report", http.StatusBadRequest)
+		return
+	}
+	for _, v := range violations {
+		log.Printf("CSP violation: %s", v)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"bytes"
+	"crypto/tls"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSecure(t *testing.T) {
+	var seen string
+	page := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = Nonce(r)
+	})
+
+	rr := httptest.NewRecorder()
+	Secure(nil, page).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	h := rr.Header()
+	csp := h.Get("Content-Security-Policy")
+	if seen == "" || !strings.Contains(csp, "script-src 'self' 'nonce-"+seen+"'") ||
+		!strings.Contains(csp, "frame-ancestors 'none'") || !strings.Contains(csp, "report-uri /csp-report") {
+		t.Errorf("nonce %q, policy %q", seen, csp)
+	}
+	for name, want := range map[string]string{
+		"X-Content-Type-Options":    "nosniff",
+		"X-Frame-Options":           "DENY",
+		"Referrer-Policy":           "strict-origin-when-cross-origin",
+		"Strict-Transport-Security": "",
+	} {
+		if got := h.Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+	if !strings.Contains(h.Get("Permissions-Policy"), "camera=()") {
+		t.Errorf("Permissions-Policy = %q", h.Get("Permissions-Policy"))
+	}
+
+	// Each request gets its own nonce
+	first := seen
+	Secure(nil, page).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if seen == first {
+		t.Error("nonce was reused")
+	}
+
+	// HSTS is only sent over TLS
+	req := httptest.NewRequest("GET", "/", nil)
+	req.TLS = &tls.ConnectionState{}
+	rr = httptest.NewRecorder()
+	Secure(nil, page).ServeHTTP(rr, req)
+	if got := rr.Header().Get("Strict-Transport-Security"); got != "max-age=31536000" {
+		t.Errorf("HSTS = %q", got)
+	}
+
+	// Routes adjust the headers, and report-only mode blocks nothing
+	defer func(s SecurityHeaders) { Security = s }(Security)
+	Security.ReportOnly = true
+	rr = httptest.NewRecorder()
+	Secure(nil, page).ServeHTTP(rr, httptest.NewRequest("GET", "/", nil))
+	if rr.Header().Get("Content-Security-Policy") != "" || rr.Header().Get("Content-Security-Policy-Report-Only") == "" {
+		t.Errorf("report-only headers: %v", rr.Header())
+	}
+	rr = httptest.NewRecorder()
+	Secure(withoutCSP, page).ServeHTTP(rr, httptest.NewRequest("GET", "/live/simple_server/", nil))
+	if rr.Header().Get("Content-Security-Policy-Report-Only") != "" || rr.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Errorf("headers without a policy: %v", rr.Header())
+	}
+}
+
+func TestCSPReport(t *testing.T) {
+	var logged bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&logged)
+
+	for _, tc := range []struct {
+		body   string
+		status int
+		log    string
+	}{
+		{`{"csp-report": {"document-uri": "http://localhost:5000/basic", "blocked-uri": "inline", "violated-directive": "script-src-elem"}}`,
+			http.StatusNoContent, "CSP violation: inline on http://localhost:5000/basic violates script-src-elem"},
+		{`[{"type": "csp-violation", "body": {"documentURL": "http://localhost:5000/", "blockedURL": "https://evil.example/x.js", "effectiveDirective": "script-src-elem", "disposition": "report"}}]`,
+			http.StatusNoContent, "CSP violation: https://evil.example/x.js on http://localhost:5000/ violates script-src-elem (report only)"},
+		{`not json`, http.StatusBadRequest, ""},
+	} {
+		logged.Reset()
+		rr := httptest.NewRecorder()
+		CSPReportHandler(rr, httptest.NewRequest("POST", "/csp-report", strings.NewReader(tc.body)))
+		if rr.Code != tc.status || !strings.Contains(logged.String(), tc.log) {
+			t.Errorf("report %s: status %d, logged %q", tc.body, rr.Code, logged.String())
+		}
+	}
+
+	rr := httptest.NewRecorder()
+	CSPReportHandler(rr, httptest.NewRequest("GET", "/csp-report", nil))
+	if rr.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET: status %d", rr.Code)
+	}
+}
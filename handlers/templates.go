@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"html/template"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/i18n"
+)
+
+// sharedTemplates are parsed along with every page
+var sharedTemplates = []string{"templates/layout.html", "templates/tutorial.html"}
+
+// templateCache holds the parsed page templates, keyed by their files. Pages
+// are parsed the first time they are shown and all of them again when the
+// templates change on disk.
+var templateCache = struct {
+	sync.RWMutex
+	pages map[string]*template.Template
+}{pages: make(map[string]*template.Template)}
+
+// pageTemplate returns the parsed layout and shared templates with the given
+// page templates. The result must be cloned before its functions are bound
+// to a request.
+func pageTemplate(files ...string) (*template.Template, error) {
+	key := strings.Join(files, ",")
+	templateCache.RLock()
+	tmpl, ok := templateCache.pages[key]
+	templateCache.RUnlock()
+	if ok {
+		return tmpl, nil
+	}
+
+	tmpl, err := parsePage(files)
+	if err != nil {
+		return nil, err
+	}
+	templateCache.Lock()
+	templateCache.pages[key] = tmpl
+	templateCache.Unlock()
+	return tmpl, nil
+}
+
+// parsePage parses a page with the layout and shared templates
+func parsePage(files []string) (*template.Template, error) {
+	return template.New("layout.html").
+		Funcs(templateFuncs).
+		Funcs(requestFuncs(TemplateData{})).
+		ParseFiles(append(append([]string(nil), sharedTemplates...), files...)...)
+}
+
+// requestFuncs are the template functions that depend on the request being
+// served
+func requestFuncs(data TemplateData) template.FuncMap {
+	return template.FuncMap{
+		"completed": data.Progress.Completed,
+		"quizScore": data.Progress.Quiz,
+		"t": func(key string, args ...interface{}) string {
+			return i18n.T(data.Locale, key, args...)
+		},
+		"title": func(id string) string {
+			tutorial, _, _ := content.FindTutorial(id)
+			return content.Localize(tutorial, data.Locale).Title
+		},
+	}
+}
+
+// ReloadTemplates parses every page in the templates directory again and
+// swaps them in together. If any page fails to parse, the pages already
+// parsed are kept and the error is returned.
+func ReloadTemplates() error {
+	files, err := filepath.Glob("templates/*.html")
+	if err != nil {
+		return err
+	}
+	pages := make(map[string]*template.Template)
+	for _, file := range files {
+		page := filepath.ToSlash(file)
+		if isShared(page) {
+			continue
+		}
+		tmpl, err := parsePage([]string{page})
+		if err != nil {
+			return err
+		}
+		pages[page] = tmpl
+	}
+
+	templateCache.Lock()
+	templateCache.pages = pages
+	templateCache.Unlock()
+	return nil
+}
+
+// isShared reports whether a template file is parsed with every page
+func isShared(file string) bool {
+	for _, shared := range sharedTemplates {
+		if file == shared {
+			return true
+		}
+	}
+	return false
+}
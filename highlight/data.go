@@ -0,0 +1,169 @@
+package highlight
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	jsonString  = regexp.MustCompile(`^"(?:[^"\\\n]|\\.)*"?`)
+	jsonNumber  = regexp.MustCompile(`^-?[0-9]+(?:\.[0-9]+)?(?:[eE][+-]?[0-9]+)?`)
+	jsonLiteral = regexp.MustCompile(`^(?:true|false|null)\b`)
+	shellVar    = regexp.MustCompile(`^\$(?:\{[^}]*\}|[A-Za-z_][A-Za-z0-9_]*|[0-9?$#@*!-])`)
+	shellWord   = regexp.MustCompile(`^[^\s|&;<>()"'$#]+`)
+	httpRequest = regexp.MustCompile(`^([A-Z]+)( +)(\S+)( +)(HTTP/[0-9.]+)`)
+	httpStatus  = regexp.MustCompile(`^(HTTP/[0-9.]+)( +)([0-9]{3})(.*)`)
+	httpHeader  = regexp.MustCompile(`^([A-Za-z0-9-]+)(:)(.*)`)
+)
+
+// lexJSON tokenizes JSON documents, marking object keys as properties
+func lexJSON(src string) []Token {
+	var l tokenList
+	for len(src) > 0 {
+		var kind Kind
+		var text string
+		switch {
+		case src[0] == '"':
+			text = jsonString.FindString(src)
+			kind = String
+			// A string followed by a colon is an object key
+			if strings.HasPrefix(strings.TrimLeft(src[len(text):], " \t\r\n"), ":") {
+				kind = Property
+			}
+		case jsonLiteral.MatchString(src):
+			text, kind = jsonLiteral.FindString(src), Keyword
+		case jsonNumber.MatchString(src):
+			text, kind = jsonNumber.FindString(src), Number
+		case strings.ContainsRune("{}[],:", rune(src[0])):
+			text, kind = src[:1], Punctuation
+		default:
+			text, kind = src[:1], Plain
+		}
+		l.add(kind, text)
+		src = src[len(text):]
+	}
+	return l
+}
+
+// lexShell tokenizes shell commands such as curl invocations
+func lexShell(src string) []Token {
+	var l tokenList
+	commandStart := true
+	for len(src) > 0 {
+		c := src[0]
+		switch {
+		case c == '\n':
+			l.add(Plain, "\n")
+			src = src[1:]
+			commandStart = true
+		case c == ' ' || c == '\t':
+			l.add(Plain, src[:1])
+			src = src[1:]
+		case c == '\\' && len(src) > 1 && src[1] == '\n':
+			// Line continuations keep the current command going
+			l.add(Operator, "\\")
+			src = src[1:]
+			l.add(Plain, "\n")
+			src = src[1:]
+		case c == '#':
+			end := strings.IndexByte(src, '\n')
+			if end < 0 {
+				end = len(src)
+			}
+			l.add(Comment, src[:end])
+			src = src[end:]
+		case c == '$' && commandStart && len(src) > 1 && src[1] == ' ':
+			// A "$ " prompt at the start of a line is not part of the command
+			l.add(Punctuation, "$")
+			src = src[1:]
+		case c == '$':
+			v := shellVar.FindString(src)
+			if v == "" {
+				v = "$"
+			}
+			l.add(Variable, v)
+			src = src[len(v):]
+			commandStart = false
+		case c == '"' || c == '\'':
+			end := strings.IndexByte(src[1:], c)
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += 2
+			}
+			l.add(String, src[:end])
+			src = src[end:]
+			commandStart = false
+		case strings.ContainsRune("|&;<>()", rune(c)):
+			l.add(Operator, src[:1])
+			src = src[1:]
+			commandStart = c != '<' && c != '>'
+		default:
+			word := shellWord.FindString(src)
+			if word == "" {
+				word = src[:1]
+			}
+			switch {
+			case commandStart:
+				l.add(Function, word)
+			case strings.HasPrefix(word, "-"):
+				l.add(AttrName, word)
+			default:
+				l.add(Plain, word)
+			}
+			src = src[len(word):]
+			commandStart = false
+		}
+	}
+	return l
+}
+
+// lexHTTP tokenizes raw HTTP requests and responses, highlighting a JSON body
+func lexHTTP(src string) []Token {
+	var l tokenList
+	inBody := false
+	lines := strings.SplitAfter(src, "\n")
+	for i, line := range lines {
+		if inBody {
+			body := strings.Join(lines[i:], "")
+			if trimmed := strings.TrimSpace(body); strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+				for _, tok := range lexJSON(body) {
+					l.add(tok.Kind, tok.Text)
+				}
+			} else {
+				l.add(Plain, body)
+			}
+			break
+		}
+
+		content := strings.TrimRight(line, "\r\n")
+		newline := line[len(content):]
+		switch {
+		case i == 0 && httpRequest.MatchString(content):
+			m := httpRequest.FindStringSubmatch(content)
+			l.add(Keyword, m[1])
+			l.add(Plain, m[2])
+			l.add(String, m[3])
+			l.add(Plain, m[4])
+			l.add(Builtin, m[5])
+			l.add(Plain, content[len(m[0]):])
+		case i == 0 && httpStatus.MatchString(content):
+			m := httpStatus.FindStringSubmatch(content)
+			l.add(Builtin, m[1])
+			l.add(Plain, m[2])
+			l.add(Number, m[3])
+			l.add(Plain, m[4])
+		case content == "":
+			inBody = true
+		case httpHeader.MatchString(content):
+			m := httpHeader.FindStringSubmatch(content)
+			l.add(Property, m[1])
+			l.add(Punctuation, m[2])
+			l.add(Plain, m[3])
+		default:
+			l.add(Plain, content)
+		}
+		l.add(Plain, newline)
+	}
+	return l
+}
@@ -0,0 +1,120 @@
+package highlight
+
+import (
+	"go/scanner"
+	"go/token"
+)
+
+// goBuiltins lists the predeclared identifiers of the Go universe block
+var goBuiltins = map[string]bool{
+	"bool": true, "byte": true, "complex64": true, "complex128": true, "error": true,
+	"float32": true, "float64": true, "int": true, "int8": true, "int16": true,
+	"int32": true, "int64": true, "rune": true, "string": true, "uint": true,
+	"uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"any": true, "comparable": true,
+	"true": true, "false": true, "iota": true, "nil": true,
+	"append": true, "cap": true, "close": true, "complex": true, "copy": true,
+	"delete": true, "imag": true, "len": true, "make": true, "new": true,
+	"panic": true, "print": true, "println": true, "real": true, "recover": true,
+}
+
+// lexGo tokenizes Go source using the standard library scanner
+func lexGo(src string) []Token {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var s scanner.Scanner
+	// Snippets are often incomplete programs, so scan errors are ignored
+	s.Init(file, []byte(src), func(token.Position, string) {}, scanner.ScanComments)
+
+	var tokens []Token
+	offset := 0
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		// Skip semicolons the scanner inserts automatically at line ends
+		if tok == token.SEMICOLON && lit != ";" {
+			continue
+		}
+
+		start := file.Offset(pos)
+		if start < offset {
+			continue
+		}
+		text := lit
+		if text == "" {
+			text = tok.String()
+		}
+		end := start + len(text)
+		if end > len(src) {
+			end = len(src)
+		}
+
+		// Whitespace between tokens is kept as plain text
+		if start > offset {
+			tokens = append(tokens, Token{Kind: Plain, Text: src[offset:start]})
+		}
+		tokens = append(tokens, Token{Kind: goKind(tok, lit), Text: src[start:end]})
+		offset = end
+	}
+	if offset < len(src) {
+		tokens = append(tokens, Token{Kind: Plain, Text: src[offset:]})
+	}
+
+	markCalls(tokens)
+	return tokens
+}
+
+// goKind classifies a single Go token
+func goKind(tok token.Token, lit string) Kind {
+	switch {
+	case tok.IsKeyword():
+		return Keyword
+	case tok == token.IDENT:
+		if goBuiltins[lit] {
+			return Builtin
+		}
+		return Plain
+	case tok == token.STRING || tok == token.CHAR:
+		return String
+	case tok == token.INT || tok == token.FLOAT || tok == token.IMAG:
+		return Number
+	case tok == token.COMMENT:
+		return Comment
+	case tok == token.ILLEGAL:
+		return Plain
+	}
+
+	switch tok {
+	case token.LPAREN, token.RPAREN, token.LBRACE, token.RBRACE, token.LBRACK, token.RBRACK,
+		token.COMMA, token.SEMICOLON, token.PERIOD, token.COLON:
+		return Punctuation
+	}
+	return Operator
+}
+
+// markCalls marks identifiers directly followed by an opening parenthesis as
+// function names
+func markCalls(tokens []Token) {
+	for i := 0; i+1 < len(tokens); i++ {
+		if tokens[i].Kind == Plain && isIdent(tokens[i].Text) && tokens[i+1].Text == "(" {
+			tokens[i].Kind = Function
+		}
+	}
+}
+
+// isIdent reports whether s looks like a Go identifier
+func isIdent(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r > 127
+		if !letter && (i == 0 || r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,221 @@
+package highlight
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// Kind classifies a token so it can be styled
+type Kind int
+
+// Token kinds shared by all lexers
+const (
+	Plain Kind = iota
+	Keyword
+	Builtin
+	Function
+	String
+	Number
+	Comment
+	Operator
+	Punctuation
+	Tag
+	AttrName
+	AttrValue
+	Action
+	Variable
+	Property
+)
+
+// kindClasses maps each kind to the CSS class emitted around it
+var kindClasses = map[Kind]string{
+	Keyword:     "tok-keyword",
+	Builtin:     "tok-builtin",
+	Function:    "tok-function",
+	String:      "tok-string",
+	Number:      "tok-number",
+	Comment:     "tok-comment",
+	Operator:    "tok-operator",
+	Punctuation: "tok-punctuation",
+	Tag:         "tok-tag",
+	AttrName:    "tok-attr-name",
+	AttrValue:   "tok-attr-value",
+	Action:      "tok-action",
+	Variable:    "tok-variable",
+	Property:    "tok-property",
+}
+
+// kindStyles mirrors the site stylesheet for output that cannot load CSS, such as feeds
+var kindStyles = map[Kind]string{
+	Keyword:     "color:#c678dd",
+	Builtin:     "color:#e5c07b",
+	Function:    "color:#61afef",
+	String:      "color:#98c379",
+	Number:      "color:#d19a66",
+	Comment:     "color:#5c6370;font-style:italic",
+	Operator:    "color:#56b6c2",
+	Punctuation: "color:#abb2bf",
+	Tag:         "color:#e06c75",
+	AttrName:    "color:#d19a66",
+	AttrValue:   "color:#98c379",
+	Action:      "color:#c678dd",
+	Variable:    "color:#e06c75",
+	Property:    "color:#e06c75",
+}
+
+// Token is a run of source text with a single kind
+type Token struct {
+	Kind Kind
+	Text string
+}
+
+// lexer splits source text into tokens whose texts concatenate to the source
+type lexer func(src string) []Token
+
+// lexers maps language names, including common aliases, to their lexer
+var lexers = map[string]lexer{
+	"go":     lexGo,
+	"golang": lexGo,
+	"html":   lexHTML,
+	"gohtml": lexHTML,
+	"tmpl":   lexHTML,
+	"json":   lexJSON,
+	"shell":  lexShell,
+	"bash":   lexShell,
+	"sh":     lexShell,
+	"http":   lexHTTP,
+}
+
+// Supported reports whether the language has a lexer
+func Supported(language string) bool {
+	_, ok := lexers[strings.ToLower(language)]
+	return ok
+}
+
+// Tokenize splits src into tokens for the given language; unknown languages
+// produce a single plain token
+func Tokenize(language, src string) []Token {
+	if lex, ok := lexers[strings.ToLower(language)]; ok {
+		return lex(src)
+	}
+	return []Token{{Kind: Plain, Text: src}}
+}
+
+// Options controls how a snippet is rendered
+type Options struct {
+	// Language selects the lexer, e.g. "go", "html", "json", "shell" or "http"
+	Language string
+
+	// LineNumbers adds a data-line attribute to each line for the stylesheet to display
+	LineNumbers bool
+
+	// Highlight marks lines that should stand out
+	Highlight Lines
+
+	// Callouts marks lines that have an accompanying note
+	Callouts Lines
+
+	// AnchorPrefix, when set, gives every line an id of the form prefix-L12
+	AnchorPrefix string
+
+	// Inline emits style attributes instead of classes
+	Inline bool
+}
+
+// Render returns src as a highlighted <pre><code> block
+func Render(src string, opts Options) string {
+	lines := splitLines(Tokenize(opts.Language, src))
+
+	var b strings.Builder
+	preClass := "code-block"
+	if opts.LineNumbers {
+		preClass += " line-numbers"
+	}
+	if opts.Inline {
+		b.WriteString(`<pre style="background:#282c34;color:#abb2bf;padding:1em;overflow:auto">`)
+	} else {
+		fmt.Fprintf(&b, `<pre class="%s">`, preClass)
+	}
+	if opts.Language != "" {
+		fmt.Fprintf(&b, `<code class="language-%s">`, html.EscapeString(strings.ToLower(opts.Language)))
+	} else {
+		b.WriteString("<code>")
+	}
+
+	for i, line := range lines {
+		n := i + 1
+		if i > 0 {
+			b.WriteString("\n")
+		}
+
+		b.WriteString(`<span`)
+		if opts.Inline {
+			if opts.Highlight[n] {
+				b.WriteString(` style="background:#3a3f4b"`)
+			}
+		} else {
+			class := "line"
+			if opts.Highlight[n] {
+				class += " highlighted"
+			}
+			if opts.Callouts[n] {
+				class += " callout"
+			}
+			fmt.Fprintf(&b, ` class="%s"`, class)
+			if opts.LineNumbers {
+				fmt.Fprintf(&b, ` data-line="%d"`, n)
+			}
+		}
+		if opts.AnchorPrefix != "" {
+			fmt.Fprintf(&b, ` id="%s-L%d"`, html.EscapeString(opts.AnchorPrefix), n)
+		}
+		b.WriteString(">")
+
+		for _, tok := range line {
+			writeToken(&b, tok, opts.Inline)
+		}
+		b.WriteString("</span>")
+	}
+
+	b.WriteString("</code></pre>")
+	return b.String()
+}
+
+// writeToken writes a single escaped token wrapped in a styled span
+func writeToken(b *strings.Builder, tok Token, inline bool) {
+	text := html.EscapeString(tok.Text)
+	if tok.Kind == Plain {
+		b.WriteString(text)
+		return
+	}
+	if inline {
+		fmt.Fprintf(b, `<span style="%s">%s</span>`, kindStyles[tok.Kind], text)
+		return
+	}
+	fmt.Fprintf(b, `<span class="%s">%s</span>`, kindClasses[tok.Kind], text)
+}
+
+// splitLines breaks tokens at newlines so every line can be wrapped separately
+func splitLines(tokens []Token) [][]Token {
+	lines := [][]Token{nil}
+	for _, tok := range tokens {
+		parts := strings.Split(tok.Text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, nil)
+			}
+			if part != "" {
+				last := len(lines) - 1
+				lines[last] = append(lines[last], Token{Kind: tok.Kind, Text: part})
+			}
+		}
+	}
+	return lines
+}
+
+// TrimSource removes the blank lines that surround snippets embedded in markup
+func TrimSource(src string) string {
+	src = strings.TrimLeft(src, "\r\n")
+	return strings.TrimRight(src, " \t\r\n")
+}
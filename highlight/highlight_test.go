@@ -0,0 +1,118 @@
+package highlight
+
+import (
+	"strings"
+	"testing"
+)
+
+var samples = map[string]string{
+	"go":    "package main\n\nimport \"fmt\"\n\n/* block\ncomment */\nfunc main() {\n\tx := `raw\nstring` // note\n\tfmt.Println(len(x), 3.5, 'c', nil)\n}\n",
+	"html":  "<!DOCTYPE html>\n<!-- comment -->\n<ul class=\"items\">{{range .Items}}<li>{{.}}</li>{{end}}</ul>\n<p>1 < 2 { ok }</p>",
+	"json":  `{"id": 1, "title": "Go \"in\" Action", "tags": ["a", "b"], "ok": true, "n": null}`,
+	"shell": "$ curl -X POST \\\n  -H 'Content-Type: application/json' \\\n  -d \"{}\" $URL/api/books # create\nls | wc -l",
+	"http":  "POST /api/books HTTP/1.1\r\nHost: localhost\r\nContent-Type: application/json\r\n\r\n{\"title\": \"Go\"}",
+}
+
+func TestTokenizePreservesSource(t *testing.T) {
+	for lang, src := range samples {
+		var b strings.Builder
+		for _, tok := range Tokenize(lang, src) {
+			b.WriteString(tok.Text)
+		}
+		if b.String() != src {
+			t.Errorf("%s: tokens do not reproduce the source\n got: %q\nwant: %q", lang, b.String(), src)
+		}
+	}
+}
+
+func kindsOf(tokens []Token) map[string]Kind {
+	kinds := make(map[string]Kind)
+	for _, tok := range tokens {
+		kinds[tok.Text] = tok.Kind
+	}
+	return kinds
+}
+
+func TestTokenizeGo(t *testing.T) {
+	kinds := kindsOf(Tokenize("go", samples["go"]))
+	want := map[string]Kind{
+		"package":       Keyword,
+		"func":          Keyword,
+		`"fmt"`:         String,
+		"Println":       Function,
+		"len":           Builtin,
+		"nil":           Builtin,
+		"3.5":           Number,
+		"// note":       Comment,
+		"`raw\nstring`": String,
+	}
+	for text, kind := range want {
+		if kinds[text] != kind {
+			t.Errorf("%q: kind %d, want %d", text, kinds[text], kind)
+		}
+	}
+}
+
+func TestTokenizeMarkupAndData(t *testing.T) {
+	tests := []struct {
+		lang string
+		text string
+		kind Kind
+	}{
+		{"html", "ul", Tag},
+		{"html", "class", AttrName},
+		{"html", `"items"`, AttrValue},
+		{"html", "range", Keyword},
+		{"html", ".Items", Variable},
+		{"json", `"title"`, Property},
+		{"json", `"Go \"in\" Action"`, String},
+		{"json", "true", Keyword},
+		{"shell", "curl", Function},
+		{"shell", "-X", AttrName},
+		{"shell", "$URL", Variable},
+		{"shell", "# create", Comment},
+		{"http", "POST", Keyword},
+		{"http", "Content-Type", Property},
+	}
+	for _, tt := range tests {
+		kinds := kindsOf(Tokenize(tt.lang, samples[tt.lang]))
+		if kinds[tt.text] != tt.kind {
+			t.Errorf("%s %q: kind %d, want %d", tt.lang, tt.text, kinds[tt.text], tt.kind)
+		}
+	}
+}
+
+func TestParseLines(t *testing.T) {
+	lines, err := ParseLines("3-5, 9", 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []int{3, 4, 5, 9} {
+		if !lines[n] {
+			t.Errorf("line %d not included", n)
+		}
+	}
+	if lines[6] {
+		t.Error("line 6 should not be included")
+	}
+	if got := lines.String(); got != "3-5,9" {
+		t.Errorf("String() = %q, want 3-5,9", got)
+	}
+
+	for _, bad := range []string{"x", "5-3", "0", "2-", "9-11", "1-2000000000"} {
+		if _, err := ParseLines(bad, 10); err == nil {
+			t.Errorf("ParseLines(%q) should fail", bad)
+		}
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	// The range is checked against the trimmed source, not the raw one
+	src, lines := Prepare("\n\none\ntwo\n\n", "2")
+	if src != "one\ntwo" || lines.String() != "2" {
+		t.Errorf("Prepare = %q, %v", src, lines)
+	}
+	if _, lines := Prepare("one\ntwo", "3"); lines != nil {
+		t.Errorf("out of range lines = %v", lines)
+	}
+}
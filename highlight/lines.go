@@ -0,0 +1,93 @@
+package highlight
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Lines is a set of 1-based line numbers
+type Lines map[int]bool
+
+// ParseLines parses a line specification such as "3-5,9" into a set. Every
+// line must be within the count lines of the code it applies to, which also
+// bounds the size of the set.
+func ParseLines(spec string, count int) (Lines, error) {
+	lines := make(Lines)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		from, to := part, part
+		if i := strings.Index(part, "-"); i >= 0 {
+			from, to = part[:i], part[i+1:]
+		}
+
+		start, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil {
+			return nil, fmt.Errorf("invalid line range %q", part)
+		}
+		end, err := strconv.Atoi(strings.TrimSpace(to))
+		if err != nil {
+			return nil, fmt.Errorf("invalid line range %q", part)
+		}
+		if start < 1 || end < start {
+			return nil, fmt.Errorf("invalid line range %q", part)
+		}
+		if end > count {
+			return nil, fmt.Errorf("line range %q is past the last line, %d", part, count)
+		}
+
+		for n := start; n <= end; n++ {
+			lines[n] = true
+		}
+	}
+	return lines, nil
+}
+
+// LineCount returns the number of lines in src, which should already have
+// been trimmed with TrimSource
+func LineCount(src string) int {
+	return strings.Count(src, "\n") + 1
+}
+
+// Prepare trims src with TrimSource and parses spec against the trimmed
+// lines, as every renderer of a code block does. Content is checked before
+// it is published, so a malformed spec only drops the emphasis.
+func Prepare(src, spec string) (string, Lines) {
+	src = TrimSource(src)
+	lines, err := ParseLines(spec, LineCount(src))
+	if err != nil {
+		return src, nil
+	}
+	return src, lines
+}
+
+// String formats the set back into its compact range form
+func (l Lines) String() string {
+	numbers := make([]int, 0, len(l))
+	for n, ok := range l {
+		if ok {
+			numbers = append(numbers, n)
+		}
+	}
+	sort.Ints(numbers)
+
+	var parts []string
+	for i := 0; i < len(numbers); {
+		j := i
+		for j+1 < len(numbers) && numbers[j+1] == numbers[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, strconv.Itoa(numbers[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", numbers[i], numbers[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
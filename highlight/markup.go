@@ -0,0 +1,157 @@
+package highlight
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tokenList accumulates tokens and merges adjacent runs of the same kind
+type tokenList []Token
+
+func (l *tokenList) add(kind Kind, text string) {
+	if text == "" {
+		return
+	}
+	if n := len(*l); n > 0 && (*l)[n-1].Kind == kind {
+		(*l)[n-1].Text += text
+		return
+	}
+	*l = append(*l, Token{Kind: kind, Text: text})
+}
+
+var (
+	tagName      = regexp.MustCompile(`^[A-Za-z!][A-Za-z0-9:-]*`)
+	attrName     = regexp.MustCompile(`^[^\s"'<>/=]+`)
+	actionWord   = regexp.MustCompile(`^(?:"(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `|\$?\.?[A-Za-z_][A-Za-z0-9_.]*|\.|\$|[0-9]+|\s+|.)`)
+	templateKeys = map[string]bool{
+		"if": true, "else": true, "end": true, "range": true, "with": true, "define": true,
+		"template": true, "block": true, "break": true, "continue": true, "nil": true,
+	}
+)
+
+// lexHTML tokenizes HTML, including Go template actions such as {{.Title}}
+func lexHTML(src string) []Token {
+	var l tokenList
+	for len(src) > 0 {
+		switch {
+		case strings.HasPrefix(src, "{{"):
+			src = lexAction(&l, src)
+		case strings.HasPrefix(src, "<!--"):
+			end := strings.Index(src, "-->")
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += len("-->")
+			}
+			l.add(Comment, src[:end])
+			src = src[end:]
+		case strings.HasPrefix(src, "<") && len(src) > 1 && (src[1] == '/' || src[1] == '!' || isLetter(src[1])):
+			src = lexTag(&l, src)
+		default:
+			// Plain text runs until the next tag or template action
+			end := len(src)
+			if i := strings.IndexAny(src[1:], "<{"); i >= 0 {
+				end = i + 1
+			}
+			l.add(Plain, src[:end])
+			src = src[end:]
+		}
+	}
+	return l
+}
+
+// lexTag consumes a single start or end tag
+func lexTag(l *tokenList, src string) string {
+	if strings.HasPrefix(src, "</") {
+		l.add(Punctuation, "</")
+		src = src[2:]
+	} else {
+		l.add(Punctuation, "<")
+		src = src[1:]
+	}
+	name := tagName.FindString(src)
+	l.add(Tag, name)
+	src = src[len(name):]
+
+	for len(src) > 0 {
+		switch {
+		case strings.HasPrefix(src, "/>"):
+			l.add(Punctuation, "/>")
+			return src[2:]
+		case src[0] == '>':
+			l.add(Punctuation, ">")
+			return src[1:]
+		case strings.HasPrefix(src, "{{"):
+			src = lexAction(l, src)
+		case src[0] == ' ' || src[0] == '\t' || src[0] == '\n' || src[0] == '\r':
+			l.add(Plain, src[:1])
+			src = src[1:]
+		case src[0] == '=':
+			l.add(Operator, "=")
+			src = src[1:]
+		case src[0] == '"' || src[0] == '\'':
+			end := strings.IndexByte(src[1:], src[0])
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += 2
+			}
+			l.add(AttrValue, src[:end])
+			src = src[end:]
+		default:
+			name := attrName.FindString(src)
+			if name == "" {
+				name = src[:1]
+			}
+			l.add(AttrName, name)
+			src = src[len(name):]
+		}
+	}
+	return src
+}
+
+// lexAction consumes a {{ ... }} template action
+func lexAction(l *tokenList, src string) string {
+	end := strings.Index(src, "}}")
+	if end < 0 {
+		end = len(src)
+	} else {
+		end += len("}}")
+	}
+	action := src[:end]
+	rest := src[end:]
+
+	l.add(Action, "{{")
+	inner := strings.TrimPrefix(action, "{{")
+	closing := ""
+	if strings.HasSuffix(inner, "}}") {
+		inner = strings.TrimSuffix(inner, "}}")
+		closing = "}}"
+	}
+
+	for len(inner) > 0 {
+		word := actionWord.FindString(inner)
+		switch {
+		case strings.HasPrefix(word, `"`) || strings.HasPrefix(word, "`"):
+			l.add(String, word)
+		case strings.HasPrefix(word, ".") || strings.HasPrefix(word, "$"):
+			l.add(Variable, word)
+		case templateKeys[word]:
+			l.add(Keyword, word)
+		case word != "" && word[0] >= '0' && word[0] <= '9':
+			l.add(Number, word)
+		case isIdent(word):
+			l.add(Function, word)
+		default:
+			l.add(Plain, word)
+		}
+		inner = inner[len(word):]
+	}
+	l.add(Action, closing)
+	return rest
+}
+
+// isLetter reports whether b is an ASCII letter
+func isLetter(b byte) bool {
+	return (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z')
+}
@@ -0,0 +1,135 @@
+// Package htmltag parses HTML tags the way browsers do, for code that walks
+// through content HTML and writes it out again
+package htmltag
+
+import (
+	"html"
+	"strings"
+)
+
+// Tag is a start or end tag
+type Tag struct {
+	Name        string
+	End         bool
+	SelfClosing bool
+	Attrs       []Attribute
+}
+
+// Attribute is an attribute of a tag with its value unescaped
+type Attribute struct {
+	Name     string
+	Value    string
+	HasValue bool
+}
+
+// Parse parses the tag at the start of s and returns its length. It returns
+// false if s does not start with a complete tag.
+func Parse(s string) (Tag, int, bool) {
+	var t Tag
+	i := 1
+	if i < len(s) && s[i] == '/' {
+		t.End = true
+		i++
+	}
+	if i >= len(s) || !IsLetter(s[i]) {
+		return t, 0, false
+	}
+	start := i
+	for i < len(s) && !IsSpace(s[i]) && s[i] != '/' && s[i] != '>' {
+		i++
+	}
+	t.Name = strings.ToLower(s[start:i])
+
+	for {
+		for i < len(s) && (IsSpace(s[i]) || s[i] == '/') {
+			t.SelfClosing = s[i] == '/'
+			i++
+		}
+		if i >= len(s) {
+			return t, 0, false
+		}
+		if s[i] == '>' {
+			return t, i + 1, true
+		}
+		t.SelfClosing = false
+
+		// The first character of a name may be '='
+		start := i
+		i++
+		for i < len(s) && !IsSpace(s[i]) && s[i] != '/' && s[i] != '>' && s[i] != '=' {
+			i++
+		}
+		a := Attribute{Name: strings.ToLower(s[start:i])}
+		j := i
+		for j < len(s) && IsSpace(s[j]) {
+			j++
+		}
+		if j < len(s) && s[j] == '=' {
+			i = j + 1
+			for i < len(s) && IsSpace(s[i]) {
+				i++
+			}
+			if i >= len(s) {
+				return t, 0, false
+			}
+			a.HasValue = true
+			if q := s[i]; q == '"' || q == '\'' {
+				end := strings.IndexByte(s[i+1:], q)
+				if end < 0 {
+					return t, 0, false
+				}
+				a.Value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				start := i
+				for i < len(s) && !IsSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				a.Value = s[start:i]
+			}
+			a.Value = html.UnescapeString(a.Value)
+		}
+		t.Attrs = append(t.Attrs, a)
+	}
+}
+
+// SkipComment returns s after the comment at its start, and false if s does
+// not start with a comment. An unterminated comment runs to the end.
+func SkipComment(s string) (string, bool) {
+	if !strings.HasPrefix(s, "<!--") {
+		return s, false
+	}
+	end := strings.Index(s[4:], "-->")
+	if end < 0 {
+		return "", true
+	}
+	return s[4+end+3:], true
+}
+
+// SkipRawText returns s after the end tag of the named element
+func SkipRawText(s, name string) string {
+	for i := 0; ; i++ {
+		next := strings.Index(s[i:], "</")
+		if next < 0 {
+			return ""
+		}
+		i += next + 2
+		end := i + len(name)
+		if end <= len(s) && strings.EqualFold(s[i:end], name) && (end == len(s) || IsSpace(s[end]) || s[end] == '/' || s[end] == '>') {
+			if close := strings.IndexByte(s[end:], '>'); close >= 0 {
+				return s[end+close+1:]
+			}
+			return ""
+		}
+	}
+}
+
+// IsLetter reports whether c is an ASCII letter
+func IsLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// IsSpace reports whether c is HTML whitespace
+func IsSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
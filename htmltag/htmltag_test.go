@@ -0,0 +1,39 @@
+package htmltag
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want Tag
+		n    int
+	}{
+		{`<P Class='a' open data-x=1>rest`, Tag{Name: "p", Attrs: []Attribute{
+			{Name: "class", Value: "a", HasValue: true}, {Name: "open"}, {Name: "data-x", Value: "1", HasValue: true}}}, 27},
+		{`</li >`, Tag{Name: "li", End: true}, 6},
+		{`<br/>`, Tag{Name: "br", SelfClosing: true}, 5},
+		{`<a title="&lt;b&gt;">`, Tag{Name: "a", Attrs: []Attribute{{Name: "title", Value: "<b>", HasValue: true}}}, 21},
+	} {
+		got, n, ok := Parse(tc.in)
+		if !ok || n != tc.n || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Parse(%q) = %+v, %d, %v; want %+v, %d", tc.in, got, n, ok, tc.want, tc.n)
+		}
+	}
+	for _, in := range []string{`< p>`, `<-ch`, `<a href="x`, `<p`} {
+		if _, _, ok := Parse(in); ok {
+			t.Errorf("Parse(%q) found a tag", in)
+		}
+	}
+}
+
+func TestSkip(t *testing.T) {
+	if rest, ok := SkipComment("<!-- a -->b"); !ok || rest != "b" {
+		t.Errorf("SkipComment = %q, %v", rest, ok)
+	}
+	if rest := SkipRawText(`x("</p>")</SCRIPT >after`, "script"); rest != "after" {
+		t.Errorf("SkipRawText = %q", rest)
+	}
+}
@@ -0,0 +1,113 @@
+// Package i18n picks the language for a request and translates the site's
+// interface strings. English is the source language: missing translations
+// fall back to it.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Default is the source language of the site
+const Default = "en"
+
+// Locale is a language the site is available in
+type Locale struct {
+	Tag  string // such as "es"
+	Name string // the language's own name, such as "Español"
+}
+
+// Locales lists the supported languages, English first
+var Locales = []Locale{
+	{Tag: "en", Name: "English"},
+	{Tag: "es", Name: "Español"},
+	{Tag: "de", Name: "Deutsch"},
+}
+
+// Supported reports whether tag is one of Locales
+func Supported(tag string) bool {
+	for _, l := range Locales {
+		if l.Tag == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// T translates the message with the given key into the language tag,
+// formatting it with args as fmt.Sprintf does. Keys missing from the
+// language fall back to English, and unknown keys are returned as they are.
+func T(tag, key string, args ...interface{}) string {
+	msg, ok := messages[tag][key]
+	if !ok {
+		if msg, ok = messages[Default][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(msg, args...)
+	}
+	return msg
+}
+
+// Missing returns the keys of English messages that have no translation in
+// the language tag, sorted
+func Missing(tag string) []string {
+	var keys []string
+	for key := range messages[Default] {
+		if _, ok := messages[tag][key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Negotiate picks the best supported language for an Accept-Language
+// header, such as "de-CH, de;q=0.9, en;q=0.5", or Default if none match
+func Negotiate(header string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			var err error
+			if q, err = strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64); err != nil {
+				continue
+			}
+		}
+		// Match on the primary language, so "es-MX" picks Spanish
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if Supported(base) && q > bestQ {
+			best, bestQ = base, q
+		}
+	}
+	return best
+}
+
+// SplitPath separates a language prefix from a URL path, so "/es/basic"
+// gives "es" and "/basic". Paths without a supported prefix return false.
+func SplitPath(path string) (tag, rest string, ok bool) {
+	trimmed := strings.TrimPrefix(path, "/")
+	tag, rest, _ = strings.Cut(trimmed, "/")
+	if !Supported(tag) {
+		return "", path, false
+	}
+	return tag, "/" + rest, true
+}
+
+type contextKey struct{}
+
+// WithLocale returns a context carrying the request's language
+func WithLocale(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, contextKey{}, tag)
+}
+
+// FromContext returns the language stored by WithLocale
+func FromContext(ctx context.Context) (string, bool) {
+	tag, ok := ctx.Value(contextKey{}).(string)
+	return tag, ok
+}
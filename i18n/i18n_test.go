@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestT(t *testing.T) {
+	for _, tt := range []struct {
+		tag, key string
+		args     []interface{}
+		want     string
+	}{
+		{"en", "nav.home", nil, "Home"},
+		{"es", "nav.home", nil, "Inicio"},
+		{"fr", "nav.home", nil, "Home"},
+		{"de", "progress.count", []interface{}{2, 6}, "2 von 6 Tutorials"},
+		{"es", "no.such.key", nil, "no.such.key"},
+	} {
+		if got := T(tt.tag, tt.key, tt.args...); got != tt.want {
+			t.Errorf("T(%q, %q) = %q, want %q", tt.tag, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestCatalogs(t *testing.T) {
+	for _, l := range Locales {
+		if _, ok := messages[l.Tag]; !ok {
+			t.Errorf("%s: no messages", l.Tag)
+		}
+		if missing := Missing(l.Tag); len(missing) > 0 {
+			t.Errorf("%s: missing %s", l.Tag, strings.Join(missing, ", "))
+		}
+		for key, msg := range messages[l.Tag] {
+			en, ok := messages[Default][key]
+			if !ok {
+				t.Errorf("%s: %q is not an English message", l.Tag, key)
+			}
+			if strings.Count(msg, "%") != strings.Count(en, "%") {
+				t.Errorf("%s: %q has different format verbs from English", l.Tag, key)
+			}
+		}
+	}
+	if got := Missing("fr"); len(got) != len(messages[Default]) {
+		t.Errorf("Missing(fr) has %d keys", len(got))
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	for header, want := range map[string]string{
+		"":                          "en",
+		"es":                        "es",
+		"es-MX,es;q=0.9":            "es",
+		"fr-CH, fr;q=0.9, de;q=0.5": "de",
+		"de;q=0.5, es;q=0.8":        "es",
+		"EN-gb":                     "en",
+		"fr, ja":                    "en",
+		"de;q=abc, es;q=0.1":        "es",
+		"en;q=0.2, de-AT;q=0.7, *":  "de",
+	} {
+		if got := Negotiate(header); got != want {
+			t.Errorf("Negotiate(%q) = %q, want %q", header, got, want)
+		}
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	for _, tt := range []struct {
+		path, tag, rest string
+		ok              bool
+	}{
+		{"/es/basic", "es", "/basic", true},
+		{"/de", "de", "/", true},
+		{"/de/", "de", "/", true},
+		{"/basic", "", "/basic", false},
+		{"/fr/basic", "", "/fr/basic", false},
+		{"/", "", "/", false},
+	} {
+		tag, rest, ok := SplitPath(tt.path)
+		if tag != tt.tag || rest != tt.rest || ok != tt.ok {
+			t.Errorf("SplitPath(%q) = %q, %q, %v", tt.path, tag, rest, ok)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	if _, ok := FromContext(context.Background()); ok {
+		t.Error("empty context has a locale")
+	}
+	if tag, ok := FromContext(WithLocale(context.Background(), "de")); !ok || tag != "de" {
+		t.Errorf("FromContext = %q, %v", tag, ok)
+	}
+}
@@ -0,0 +1,232 @@
+package i18n
+
+// messages holds the interface strings for each language. English is
+// complete; other languages fall back to it for missing keys. Values are
+// fmt formats when the template passes arguments.
+var messages = map[string]map[string]string{
+	"en": {
+		"site.name":    "Go Web Server Tutorial",
+		"site.tagline": "Created for educational purposes.",
+
+		"nav.home":         "Home",
+		"nav.basic":        "Basic Concepts",
+		"nav.intermediate": "Intermediate",
+		"nav.advanced":     "Advanced",
+		"nav.restful":      "RESTful APIs",
+		"nav.examples":     "Examples",
+		"nav.map":          "Map",
+		"nav.login":        "Log in",
+		"nav.signup":       "Sign up",
+		"nav.logout":       "Log out",
+		"nav.language":     "Language",
+
+		"footer.book":    "Printable book",
+		"footer.epub":    "EPUB download",
+		"footer.atom":    "Atom feed",
+		"footer.rss":     "RSS feed",
+		"footer.inspect": "Request inspector",
+
+		"progress.yours":    "Your progress",
+		"progress.count":    "%d of %d tutorials",
+		"progress.continue": "Continue where you left off: %s",
+		"progress.all_done": "All tutorials complete!",
+		"progress.level":    "%d of %d tutorials complete",
+
+		"level.beginner":     "Beginner",
+		"level.intermediate": "Intermediate",
+		"level.advanced":     "Advanced",
+
+		"link.basic":        "Basic Concepts",
+		"link.intermediate": "Intermediate Concepts",
+		"link.advanced":     "Advanced Concepts",
+		"link.restful":      "RESTful APIs",
+		"link.examples":     "Code Examples",
+
+		"basic.title":        "Basic Web Server Concepts",
+		"basic.lead":         `Learn the fundamentals of building web servers in Go, from a simple "Hello World" server to handling routes and serving static files.`,
+		"basic.next":         "Now that you understand the basics, move on to:",
+		"intermediate.title": "Intermediate Web Server Concepts",
+		"intermediate.lead":  "Build on your knowledge with more advanced techniques like HTML templates, form handling, and middleware.",
+		"advanced.title":     "Advanced Web Server Concepts",
+		"advanced.lead":      "Master sophisticated techniques for building production-ready web servers, including JSON APIs, context handling, and graceful shutdown.",
+		"restful.title":      "RESTful API Development",
+		"restful.lead":       "Learn how to design and implement RESTful APIs with Go, including best practices for routing, data formats, and versioning.",
+		"examples.title":     "Code Examples",
+		"examples.lead":      "Download and study complete, working web server examples that demonstrate the concepts covered in the tutorials.",
+
+		"restful.practice_title":   "Practice With curl",
+		"restful.practice_text":    "Get your own copy of the books API to create, read, update and delete books with curl or any HTTP client, then reset it whenever you like.",
+		"restful.practice_button":  "Open the practice API",
+		"restful.conformance_lead": "Built the API yourself?",
+		"restful.conformance_link": "Check it against the conformance suite",
+
+		"tutorial.builds_on":      "Builds on",
+		"tutorial.see_map":        "See the map",
+		"tutorial.outdated":       "This translation was made from an older version of the tutorial, so it may be out of date.",
+		"tutorial.read_english":   "Read the English version",
+		"tutorial.example_code":   "Example Code",
+		"tutorial.quiz":           "Check Your Understanding",
+		"tutorial.quiz_score":     "Your best score is %d of %d; last attempt %d of %d.",
+		"tutorial.check_answers":  "Check answers",
+		"tutorial.exercise":       "Try It Yourself",
+		"tutorial.start_exercise": "Start the exercise",
+		"tutorial.completed":      "✓ Completed",
+		"tutorial.mark_complete":  "Mark as complete",
+		"tutorial.mark_undone":    "Mark as not complete",
+
+		"code.view_source": "View source",
+		"code.download":    "Download %s",
+		"code.run":         "Run",
+		"code.line":        "Line %d",
+	},
+	"es": {
+		"site.name":    "Tutorial de servidores web en Go",
+		"site.tagline": "Creado con fines educativos.",
+
+		"nav.home":         "Inicio",
+		"nav.basic":        "Conceptos básicos",
+		"nav.intermediate": "Intermedio",
+		"nav.advanced":     "Avanzado",
+		"nav.restful":      "APIs RESTful",
+		"nav.examples":     "Ejemplos",
+		"nav.map":          "Mapa",
+		"nav.login":        "Iniciar sesión",
+		"nav.signup":       "Registrarse",
+		"nav.logout":       "Cerrar sesión",
+		"nav.language":     "Idioma",
+
+		"footer.book":    "Libro imprimible",
+		"footer.epub":    "Descargar EPUB",
+		"footer.atom":    "Feed Atom",
+		"footer.rss":     "Feed RSS",
+		"footer.inspect": "Inspector de peticiones",
+
+		"progress.yours":    "Tu progreso",
+		"progress.count":    "%d de %d tutoriales",
+		"progress.continue": "Continúa donde lo dejaste: %s",
+		"progress.all_done": "¡Has completado todos los tutoriales!",
+		"progress.level":    "%d de %d tutoriales completados",
+
+		"level.beginner":     "Principiante",
+		"level.intermediate": "Intermedio",
+		"level.advanced":     "Avanzado",
+
+		"link.basic":        "Conceptos básicos",
+		"link.intermediate": "Conceptos intermedios",
+		"link.advanced":     "Conceptos avanzados",
+		"link.restful":      "APIs RESTful",
+		"link.examples":     "Ejemplos de código",
+
+		"basic.title":        "Conceptos básicos de servidores web",
+		"basic.lead":         `Aprende los fundamentos para crear servidores web en Go, desde un sencillo servidor "Hola, mundo" hasta el manejo de rutas y el servicio de archivos estáticos.`,
+		"basic.next":         "Ahora que conoces lo básico, continúa con:",
+		"intermediate.title": "Conceptos intermedios de servidores web",
+		"intermediate.lead":  "Amplía tus conocimientos con técnicas más avanzadas como plantillas HTML, manejo de formularios y middleware.",
+		"advanced.title":     "Conceptos avanzados de servidores web",
+		"advanced.lead":      "Domina técnicas sofisticadas para crear servidores web listos para producción, como APIs JSON, el uso de context y el apagado ordenado.",
+		"restful.title":      "Desarrollo de APIs RESTful",
+		"restful.lead":       "Aprende a diseñar e implementar APIs RESTful con Go, con buenas prácticas de enrutamiento, formatos de datos y versionado.",
+		"examples.title":     "Ejemplos de código",
+		"examples.lead":      "Descarga y estudia ejemplos completos de servidores web que funcionan y muestran los conceptos de los tutoriales.",
+
+		"restful.practice_title":   "Practica con curl",
+		"restful.practice_text":    "Obtén tu propia copia de la API de libros para crear, leer, actualizar y borrar libros con curl o cualquier cliente HTTP, y restablécela cuando quieras.",
+		"restful.practice_button":  "Abrir la API de práctica",
+		"restful.conformance_lead": "¿Has creado la API tú mismo?",
+		"restful.conformance_link": "Compruébala con la suite de conformidad",
+
+		"tutorial.builds_on":      "Se basa en",
+		"tutorial.see_map":        "Ver el mapa",
+		"tutorial.outdated":       "Esta traducción se hizo a partir de una versión anterior del tutorial y puede estar desactualizada.",
+		"tutorial.read_english":   "Leer la versión en inglés",
+		"tutorial.example_code":   "Código de ejemplo",
+		"tutorial.quiz":           "Comprueba lo que has aprendido",
+		"tutorial.quiz_score":     "Tu mejor puntuación es %d de %d; último intento %d de %d.",
+		"tutorial.check_answers":  "Comprobar respuestas",
+		"tutorial.exercise":       "Pruébalo tú mismo",
+		"tutorial.start_exercise": "Empezar el ejercicio",
+		"tutorial.completed":      "✓ Completado",
+		"tutorial.mark_complete":  "Marcar como completado",
+		"tutorial.mark_undone":    "Marcar como no completado",
+
+		"code.view_source": "Ver código fuente",
+		"code.download":    "Descargar %s",
+		"code.run":         "Ejecutar",
+		"code.line":        "Línea %d",
+	},
+	"de": {
+		"site.name":    "Go-Webserver-Tutorial",
+		"site.tagline": "Für Lernzwecke erstellt.",
+
+		"nav.home":         "Start",
+		"nav.basic":        "Grundlagen",
+		"nav.intermediate": "Fortgeschritten",
+		"nav.advanced":     "Experten",
+		"nav.restful":      "REST-APIs",
+		"nav.examples":     "Beispiele",
+		"nav.map":          "Übersicht",
+		"nav.login":        "Anmelden",
+		"nav.signup":       "Registrieren",
+		"nav.logout":       "Abmelden",
+		"nav.language":     "Sprache",
+
+		"footer.book":    "Druckversion",
+		"footer.epub":    "EPUB herunterladen",
+		"footer.atom":    "Atom-Feed",
+		"footer.rss":     "RSS-Feed",
+		"footer.inspect": "Request-Inspektor",
+
+		"progress.yours":    "Dein Fortschritt",
+		"progress.count":    "%d von %d Tutorials",
+		"progress.continue": "Weitermachen, wo du aufgehört hast: %s",
+		"progress.all_done": "Alle Tutorials abgeschlossen!",
+		"progress.level":    "%d von %d Tutorials abgeschlossen",
+
+		"level.beginner":     "Einsteiger",
+		"level.intermediate": "Fortgeschritten",
+		"level.advanced":     "Experten",
+
+		"link.basic":        "Grundlagen",
+		"link.intermediate": "Fortgeschrittene Konzepte",
+		"link.advanced":     "Expertenkonzepte",
+		"link.restful":      "REST-APIs",
+		"link.examples":     "Codebeispiele",
+
+		"basic.title":        "Grundlagen von Webservern",
+		"basic.lead":         "Lerne die Grundlagen von Webservern in Go, von einem einfachen „Hallo Welt“-Server bis zu Routen und statischen Dateien.",
+		"basic.next":         "Jetzt, wo du die Grundlagen kennst, geht es weiter mit:",
+		"intermediate.title": "Fortgeschrittene Webserver-Konzepte",
+		"intermediate.lead":  "Baue dein Wissen mit Techniken wie HTML-Templates, Formularverarbeitung und Middleware aus.",
+		"advanced.title":     "Webserver-Konzepte für Experten",
+		"advanced.lead":      "Meistere Techniken für produktionsreife Webserver, darunter JSON-APIs, Context und geordnetes Herunterfahren.",
+		"restful.title":      "Entwicklung von REST-APIs",
+		"restful.lead":       "Lerne, REST-APIs mit Go zu entwerfen und umzusetzen, mit bewährten Vorgehensweisen für Routing, Datenformate und Versionierung.",
+		"examples.title":     "Codebeispiele",
+		"examples.lead":      "Lade vollständige, lauffähige Webserver-Beispiele herunter, die die Konzepte aus den Tutorials zeigen.",
+
+		"restful.practice_title":   "Üben mit curl",
+		"restful.practice_text":    "Hol dir eine eigene Kopie der Bücher-API, um mit curl oder einem anderen HTTP-Client Bücher anzulegen, zu lesen, zu ändern und zu löschen, und setze sie jederzeit zurück.",
+		"restful.practice_button":  "Übungs-API öffnen",
+		"restful.conformance_lead": "Die API selbst gebaut?",
+		"restful.conformance_link": "Mit der Konformitätsprüfung testen",
+
+		"tutorial.builds_on":      "Baut auf",
+		"tutorial.see_map":        "Zur Übersicht",
+		"tutorial.outdated":       "Diese Übersetzung beruht auf einer älteren Fassung des Tutorials und ist möglicherweise veraltet.",
+		"tutorial.read_english":   "Englische Fassung lesen",
+		"tutorial.example_code":   "Beispielcode",
+		"tutorial.quiz":           "Teste dein Wissen",
+		"tutorial.quiz_score":     "Deine beste Punktzahl ist %d von %d; letzter Versuch %d von %d.",
+		"tutorial.check_answers":  "Antworten prüfen",
+		"tutorial.exercise":       "Probier es selbst",
+		"tutorial.start_exercise": "Übung starten",
+		"tutorial.completed":      "✓ Abgeschlossen",
+		"tutorial.mark_complete":  "Als abgeschlossen markieren",
+		"tutorial.mark_undone":    "Als nicht abgeschlossen markieren",
+
+		"code.view_source": "Quelltext ansehen",
+		"code.download":    "%s herunterladen",
+		"code.run":         "Ausführen",
+		"code.line":        "Zeile %d",
+	},
+}
@@ -0,0 +1,50 @@
+package inspect
+
+import (
+	"strings"
+)
+
+// curlSkipHeaders are set by curl itself or by the connection
+var curlSkipHeaders = map[string]bool{
+	"Accept-Encoding": true,
+	"Connection":      true,
+	"Content-Length":  true,
+	"Cookie":          true,
+	"User-Agent":      true,
+}
+
+// Curl returns a curl command that sends the same request again. Hidden
+// cookies are left out, as are headers curl adds on its own.
+func (req Request) Curl() string {
+	args := []string{"curl", "-i"}
+	if req.Method != "GET" && !(req.Method == "POST" && req.Body.Size > 0) {
+		args = append(args, "-X", req.Method)
+	}
+	args = append(args, quote(req.URL.Full))
+
+	for _, h := range req.Headers {
+		if curlSkipHeaders[h.Name] {
+			continue
+		}
+		for _, v := range h.Values {
+			args = append(args, "-H", quote(h.Name+": "+v))
+		}
+	}
+	for _, c := range req.Cookies {
+		if v := c.Values[0]; v != "(hidden)" {
+			args = append(args, "-b", quote(c.Name+"="+v))
+		}
+	}
+	if req.Body.Size > 0 && !req.Body.Binary {
+		args = append(args, "--data-binary", quote(req.Body.Text))
+	}
+	return strings.Join(args, " ")
+}
+
+// quote quotes s for a POSIX shell
+func quote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:=@,") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,201 @@
+// Package inspect describes an incoming HTTP request the way a Go handler
+// sees it, so learners can find out what ends up in each http.Request field.
+package inspect
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxBodySize is the default limit on how much of a body is captured
+const MaxBodySize = 64 << 10
+
+// Request is a snapshot of an http.Request
+type Request struct {
+	Method        string   `json:"method"`
+	Proto         string   `json:"proto"`
+	URL           URL      `json:"url"`
+	Host          string   `json:"host"`
+	RemoteAddr    string   `json:"remote_addr"`
+	RequestURI    string   `json:"request_uri"`
+	Headers       []Field  `json:"headers"`
+	Cookies       []Field  `json:"cookies"`
+	Query         []Field  `json:"query"`
+	Form          []Field  `json:"form"`
+	Files         []File   `json:"files"`
+	ContentLength int64    `json:"content_length"`
+	Transfer      []string `json:"transfer_encoding,omitempty"`
+	Body          Body     `json:"body"`
+	TLS           *TLS     `json:"tls"`
+}
+
+// URL holds the parts of the request URL
+type URL struct {
+	Full     string `json:"full"`
+	Scheme   string `json:"scheme"`
+	Host     string `json:"host"`
+	Path     string `json:"path"`
+	RawPath  string `json:"raw_path,omitempty"`
+	RawQuery string `json:"raw_query"`
+}
+
+// Field is a name with its values, such as a header or query parameter
+type Field struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// File is an uploaded file from a multipart form
+type File struct {
+	Field       string `json:"field"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Body describes the captured request body. Text bodies are kept as they
+// are; other bodies are only measured.
+type Body struct {
+	Text      string `json:"text,omitempty"`
+	Size      int    `json:"size"`
+	Binary    bool   `json:"binary,omitempty"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// TLS describes the connection's TLS state
+type TLS struct {
+	Version            string `json:"version"`
+	CipherSuite        string `json:"cipher_suite"`
+	ServerName         string `json:"server_name,omitempty"`
+	NegotiatedProtocol string `json:"negotiated_protocol,omitempty"`
+}
+
+// Capture reads up to maxBody bytes of the request body and describes the
+// request. Forms are parsed from the captured body, so a truncated body may
+// lose form values.
+func Capture(r *http.Request, maxBody int64) Request {
+	req := Request{
+		Method:        r.Method,
+		Proto:         r.Proto,
+		Host:          r.Host,
+		RemoteAddr:    r.RemoteAddr,
+		RequestURI:    r.RequestURI,
+		Headers:       fields(r.Header),
+		Cookies:       []Field{},
+		Query:         fields(r.URL.Query()),
+		Form:          []Field{},
+		Files:         []File{},
+		ContentLength: r.ContentLength,
+		Transfer:      r.TransferEncoding,
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+		req.TLS = &TLS{
+			Version:            tls.VersionName(r.TLS.Version),
+			CipherSuite:        tls.CipherSuiteName(r.TLS.CipherSuite),
+			ServerName:         r.TLS.ServerName,
+			NegotiatedProtocol: r.TLS.NegotiatedProtocol,
+		}
+	}
+	req.URL = URL{
+		Full:     scheme + "://" + r.Host + r.URL.RequestURI(),
+		Scheme:   scheme,
+		Host:     r.Host,
+		Path:     r.URL.Path,
+		RawPath:  r.URL.RawPath,
+		RawQuery: r.URL.RawQuery,
+	}
+
+	for _, c := range r.Cookies() {
+		req.Cookies = append(req.Cookies, Field{Name: c.Name, Values: []string{c.Value}})
+	}
+
+	if r.Body != nil {
+		data, _ := io.ReadAll(io.LimitReader(r.Body, maxBody+1))
+		if int64(len(data)) > maxBody {
+			data = data[:maxBody]
+			req.Body.Truncated = true
+		}
+		req.Body.Size = len(data)
+		if utf8.Valid(data) {
+			req.Body.Text = string(data)
+		} else {
+			req.Body.Binary = true
+		}
+		req.parseForm(r.Header.Get("Content-Type"), data)
+	}
+	return req
+}
+
+// parseForm fills in form values and files from a captured body
+func (req *Request) parseForm(contentType string, data []byte) {
+	mediaType, params, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(data)); err == nil {
+			req.Form = fields(values)
+		}
+	case "multipart/form-data":
+		form, err := multipart.NewReader(bytes.NewReader(data), params["boundary"]).ReadForm(int64(len(data)))
+		if err != nil {
+			return
+		}
+		defer form.RemoveAll()
+		req.Form = fields(form.Value)
+		for name, files := range form.File {
+			for _, f := range files {
+				req.Files = append(req.Files, File{Field: name, Filename: f.Filename, ContentType: f.Header.Get("Content-Type"), Size: f.Size})
+			}
+		}
+		sort.Slice(req.Files, func(i, j int) bool { return req.Files[i].Field < req.Files[j].Field })
+	}
+}
+
+// Redact hides the values of the named cookies, such as session cookies
+// that pages should not be able to read
+func (req *Request) Redact(cookies ...string) {
+	hidden := make(map[string]bool)
+	for _, name := range cookies {
+		hidden[name] = true
+	}
+	for i, c := range req.Cookies {
+		if hidden[c.Name] {
+			req.Cookies[i].Values = []string{"(hidden)"}
+		}
+	}
+	for i, h := range req.Headers {
+		if h.Name != "Cookie" {
+			continue
+		}
+		for j, v := range h.Values {
+			parts := strings.Split(v, ";")
+			for k, part := range parts {
+				name, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+				if hidden[name] {
+					parts[k] = " " + name + "=(hidden)"
+				}
+			}
+			req.Headers[i].Values[j] = strings.TrimSpace(strings.Join(parts, ";"))
+		}
+	}
+}
+
+// fields turns a header or value map into a sorted list
+func fields(m map[string][]string) []Field {
+	list := make([]Field, 0, len(m))
+	for name, values := range m {
+		list = append(list, Field{Name: name, Values: values})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
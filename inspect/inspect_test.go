@@ -0,0 +1,93 @@
+package inspect
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCapture(t *testing.T) {
+	r := httptest.NewRequest("POST", "/inspect/echo?b=2&a=1&a=3", strings.NewReader("name=Gopher&lang=Go"))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.Header.Set("X-Trace", "abc")
+	r.AddCookie(&http.Cookie{Name: "session", Value: "secret"})
+	r.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+
+	req := Capture(r, MaxBodySize)
+	req.Redact("session")
+
+	if req.URL.Full != "http://example.com/inspect/echo?b=2&a=1&a=3" || req.URL.Path != "/inspect/echo" || req.TLS != nil {
+		t.Errorf("URL = %+v", req.URL)
+	}
+	if len(req.Query) != 2 || req.Query[0].Name != "a" || strings.Join(req.Query[0].Values, ",") != "1,3" {
+		t.Errorf("Query = %+v", req.Query)
+	}
+	if len(req.Form) != 2 || req.Form[0].Name != "lang" || req.Form[1].Values[0] != "Gopher" {
+		t.Errorf("Form = %+v", req.Form)
+	}
+	if req.Body.Text != "name=Gopher&lang=Go" || req.Body.Size != 19 || req.Body.Truncated {
+		t.Errorf("Body = %+v", req.Body)
+	}
+	if req.Cookies[0].Values[0] != "(hidden)" || req.Cookies[1].Values[0] != "dark" {
+		t.Errorf("Cookies = %+v", req.Cookies)
+	}
+	for _, h := range req.Headers {
+		if h.Name == "Cookie" && h.Values[0] != "session=(hidden); theme=dark" {
+			t.Errorf("Cookie header = %q", h.Values[0])
+		}
+	}
+
+	want := "curl -i 'http://example.com/inspect/echo?b=2&a=1&a=3' -H 'Content-Type: application/x-www-form-urlencoded' -H 'X-Trace: abc' -b theme=dark --data-binary 'name=Gopher&lang=Go'"
+	if got := req.Curl(); got != want {
+		t.Errorf("Curl() = %s\nwant %s", got, want)
+	}
+}
+
+func TestCaptureLimits(t *testing.T) {
+	r := httptest.NewRequest("PUT", "/inspect", bytes.NewReader([]byte{0xff, 0xfe, 'x', 'y'}))
+	req := Capture(r, 3)
+	if !req.Body.Truncated || !req.Body.Binary || req.Body.Size != 3 || req.Body.Text != "" {
+		t.Errorf("Body = %+v", req.Body)
+	}
+	if got := req.Curl(); got != "curl -i -X PUT http://example.com/inspect" {
+		t.Errorf("Curl() = %s", got)
+	}
+}
+
+func TestCaptureMultipart(t *testing.T) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	mw.WriteField("title", "Notes")
+	fw, _ := mw.CreateFormFile("upload", "notes.txt")
+	fw.Write([]byte("hello"))
+	mw.Close()
+
+	r := httptest.NewRequest("POST", "/inspect", &body)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	req := Capture(r, MaxBodySize)
+	if len(req.Form) != 1 || req.Form[0].Values[0] != "Notes" {
+		t.Errorf("Form = %+v", req.Form)
+	}
+	if len(req.Files) != 1 || req.Files[0] != (File{Field: "upload", Filename: "notes.txt", ContentType: "application/octet-stream", Size: 5}) {
+		t.Errorf("Files = %+v", req.Files)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	for s, want := range map[string]string{
+		"plain":      "plain",
+		"":           "''",
+		"two words":  "'two words'",
+		"it's":       `'it'\''s'`,
+		"a&b":        "'a&b'",
+		"user@host":  "user@host",
+		`{"a": "b"}`: `'{"a": "b"}'`,
+	} {
+		if got := quote(s); got != want {
+			t.Errorf("quote(%q) = %s, want %s", s, got, want)
+		}
+	}
+}
@@ -0,0 +1,463 @@
+// Package lint checks tutorials and examples for mistakes that neither the
+// compiler nor the prerequisite graph catch, such as broken links, unclosed
+// HTML tags and duplicate IDs. Each finding names the rule it breaks, where
+// the mistake is and how serious it is.
+package lint
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang-webserver-tutorial/content"
+	"golang-webserver-tutorial/highlight"
+	"golang-webserver-tutorial/i18n"
+)
+
+// Severity says how serious a finding is. Errors fail the lint command;
+// warnings are worth a look but may be intended.
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Rule is one check made by the linter
+type Rule struct {
+	ID       string   `json:"id"`
+	Severity Severity `json:"severity"`
+	Summary  string   `json:"summary"`
+}
+
+// Rules lists every check in the order they are made
+var Rules = []Rule{
+	{"duplicate-id", SeverityError, "Tutorial IDs and example filenames must be unique"},
+	{"missing-title", SeverityError, "Tutorials and examples need a title"},
+	{"missing-description", SeverityError, "Tutorials and examples need a description"},
+	{"missing-explanation", SeverityWarning, "Tutorials should explain their code"},
+	{"empty-code", SeverityError, "Code blocks and examples need source code"},
+	{"unclosed-tag", SeverityError, "HTML in descriptions, explanations and callouts must be well nested"},
+	{"broken-link", SeverityError, "Links to the site must point at a page, file or anchor that exists"},
+	{"example-filename", SeverityError, "Example filenames must be lowercase Go files such as echo_server.go"},
+	{"example-directory", SeverityError, "The example directory must be named after the example and hold its file"},
+	{"stale-example", SeverityWarning, "Directories in static/examples should belong to an example"},
+}
+
+// Location is where a finding was made. File and Line point into the
+// source the content came from, when it is known.
+type Location struct {
+	File  string `json:"file,omitempty"`
+	Line  int    `json:"line,omitempty"`
+	Item  string `json:"item"`
+	Field string `json:"field,omitempty"`
+}
+
+// String formats the location as file:line: item field
+func (l Location) String() string {
+	var b strings.Builder
+	if l.File != "" {
+		b.WriteString(l.File)
+		if l.Line > 0 {
+			b.WriteString(":" + strconv.Itoa(l.Line))
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(l.Item)
+	if l.Field != "" {
+		b.WriteString(" " + l.Field)
+	}
+	return b.String()
+}
+
+// Finding is one mistake found by a rule
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Location Location `json:"location"`
+	Message  string   `json:"message"`
+}
+
+// String formats the finding as a single line
+func (f Finding) String() string {
+	return fmt.Sprintf("%s: %s [%s] %s", f.Location, f.Severity, f.Rule, f.Message)
+}
+
+// Report is the result of linting the content
+type Report struct {
+	Findings []Finding `json:"findings"`
+	Errors   int       `json:"errors"`
+	Warnings int       `json:"warnings"`
+}
+
+// Content is everything that is linted
+type Content struct {
+	Levels   []content.Level
+	Examples []content.CodeExample
+
+	// Routes are the URL patterns the site serves, as registered with
+	// http.ServeMux. Links are not checked when it is empty.
+	Routes []string
+
+	// Root is the repository directory, used to find source lines, static
+	// files and example directories. File checks are skipped when it is
+	// empty.
+	Root string
+
+	// Edited holds the keys, such as "tutorial/hello-world", of content
+	// published from the admin area rather than written in Go
+	Edited map[string]bool
+}
+
+// Sources of content
+const (
+	TutorialsFile = "content/tutorials.go"
+	ExamplesFile  = "content/examples.go"
+	EditedFile    = "data/content.json"
+	ExamplesDir   = "static/examples"
+)
+
+// Check runs every rule over the content and returns the findings sorted
+// by location
+func Check(c Content) Report {
+	// Findings is an empty list rather than null in JSON when nothing is found
+	l := &linter{Content: c, report: Report{Findings: []Finding{}}, sources: make(map[string][]string)}
+	l.checkTutorials()
+	l.checkExamples()
+	l.checkExampleDirectories()
+
+	sort.SliceStable(l.report.Findings, func(i, j int) bool {
+		a, b := l.report.Findings[i].Location, l.report.Findings[j].Location
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	return l.report
+}
+
+// linter collects findings while the rules run
+type linter struct {
+	Content
+	report  Report
+	sources map[string][]string
+}
+
+// add records a finding for the rule with the given ID
+func (l *linter) add(rule string, loc Location, format string, args ...interface{}) {
+	severity := SeverityError
+	for _, r := range Rules {
+		if r.ID == rule {
+			severity = r.Severity
+		}
+	}
+	l.report.Findings = append(l.report.Findings, Finding{
+		Rule:     rule,
+		Severity: severity,
+		Location: loc,
+		Message:  fmt.Sprintf(format, args...),
+	})
+	if severity == SeverityError {
+		l.report.Errors++
+	} else {
+		l.report.Warnings++
+	}
+}
+
+// locate returns where a tutorial or example is written: the line of its ID
+// in the Go source, or the file of edits made in the admin area
+func (l *linter) locate(kind, id string) Location {
+	loc := Location{Item: kind + " " + id}
+	if l.Edited[kind+"/"+id] {
+		loc.File = EditedFile
+		return loc
+	}
+	file, key := TutorialsFile, "ID"
+	if kind == "example" {
+		file, key = ExamplesFile, "Filename"
+	}
+	loc.File = file
+	if l.Root == "" {
+		return loc
+	}
+
+	lines, ok := l.sources[file]
+	if !ok {
+		data, _ := os.ReadFile(filepath.Join(l.Root, filepath.FromSlash(file)))
+		lines = strings.Split(string(data), "\n")
+		l.sources[file] = lines
+	}
+	pattern := regexp.MustCompile(`\b` + key + `:\s*"` + regexp.QuoteMeta(id) + `"`)
+	for i, line := range lines {
+		if pattern.MatchString(line) {
+			loc.Line = i + 1
+			break
+		}
+	}
+	return loc
+}
+
+// field returns loc with the field set
+func field(loc Location, name string) Location {
+	loc.Field = name
+	return loc
+}
+
+func (l *linter) checkTutorials() {
+	seen := make(map[string]string)
+	for _, level := range l.Levels {
+		for _, t := range level.Tutorials {
+			loc := l.locate("tutorial", t.ID)
+			if other, ok := seen[t.ID]; ok {
+				l.add("duplicate-id", field(loc, "ID"), "tutorial %q is on both the %s and %s levels", t.ID, other, level.ID)
+			}
+			seen[t.ID] = level.ID
+
+			if strings.TrimSpace(t.Title) == "" {
+				l.add("missing-title", field(loc, "Title"), "tutorial has no title")
+			}
+			if isBlank(t.Description) {
+				l.add("missing-description", field(loc, "Description"), "tutorial has no description")
+			}
+			if isBlank(t.Explanation) {
+				l.add("missing-explanation", field(loc, "Explanation"), "tutorial does not explain its code")
+			}
+
+			l.checkHTML(field(loc, "Description"), t.Description, level, t)
+			l.checkHTML(field(loc, "Explanation"), t.Explanation, level, t)
+			for i, block := range t.Code {
+				name := fmt.Sprintf("Code[%d]", i+1)
+				if strings.TrimSpace(block.Source) == "" {
+					l.add("empty-code", field(loc, name+".Source"), "code block %s has no source", block.Filename)
+				}
+				for j, callout := range block.Callouts {
+					l.checkHTML(field(loc, fmt.Sprintf("%s.Callouts[%d]", name, j+1)), callout.Note, level, t)
+				}
+			}
+		}
+	}
+}
+
+// exampleFilename matches the filenames examples may have
+var exampleFilename = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*\.go$`)
+
+func (l *linter) checkExamples() {
+	seen := make(map[string]bool)
+	for _, e := range l.Examples {
+		loc := l.locate("example", e.Filename)
+		if seen[e.Filename] {
+			l.add("duplicate-id", field(loc, "Filename"), "example %q is listed twice", e.Filename)
+		}
+		seen[e.Filename] = true
+
+		if !exampleFilename.MatchString(e.Filename) {
+			l.add("example-filename", field(loc, "Filename"), "%q is not a lowercase Go filename such as echo_server.go", e.Filename)
+		}
+		if strings.TrimSpace(e.Title) == "" {
+			l.add("missing-title", field(loc, "Title"), "example has no title")
+		}
+		if isBlank(e.Description) {
+			l.add("missing-description", field(loc, "Description"), "example has no description")
+		}
+		if strings.TrimSpace(e.Code) == "" {
+			l.add("empty-code", field(loc, "Code"), "example has no code")
+		}
+		l.checkHTML(field(loc, "Description"), e.Description, content.Level{}, content.Tutorial{})
+	}
+}
+
+// checkExampleDirectories compares static/examples with the examples: each
+// example is written to a directory named after its file, without .go
+func (l *linter) checkExampleDirectories() {
+	if l.Root == "" {
+		return
+	}
+	dir := filepath.Join(l.Root, filepath.FromSlash(ExamplesDir))
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		// Generated when the server starts
+		return
+	}
+
+	examples := make(map[string]string)
+	for _, e := range l.Examples {
+		examples[strings.TrimSuffix(e.Filename, ".go")] = e.Filename
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		loc := Location{File: path.Join(ExamplesDir, entry.Name()), Item: "directory " + entry.Name()}
+		filename, ok := examples[entry.Name()]
+		if !ok {
+			l.add("stale-example", loc, "no example is named %s.go", entry.Name())
+			continue
+		}
+		files, _ := os.ReadDir(filepath.Join(dir, entry.Name()))
+		found := false
+		for _, f := range files {
+			switch {
+			case f.Name() == filename:
+				found = true
+			case strings.HasSuffix(f.Name(), ".go"):
+				l.add("example-directory", Location{File: path.Join(loc.File, f.Name()), Item: loc.Item},
+					"%s does not match the example's filename %s", f.Name(), filename)
+			}
+		}
+		if !found {
+			l.add("example-directory", loc, "%s is missing from the example's directory", filename)
+		}
+	}
+}
+
+// isBlank reports whether HTML has no text or elements
+func isBlank(html template.HTML) bool {
+	return strings.TrimSpace(string(html)) == ""
+}
+
+var (
+	// tag matches an HTML comment or a start, end or self-closing tag
+	tag = regexp.MustCompile(`<!--[\s\S]*?-->|<(/?)([a-zA-Z][a-zA-Z0-9-]*)\b(?:[^>"']|"[^"]*"|'[^']*')*?(/?)>`)
+
+	// link matches the target of a link or embedded resource
+	link = regexp.MustCompile(`\b(?:href|src)\s*=\s*"([^"]*)"`)
+)
+
+// voidElements never have an end tag
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// checkHTML checks that tags in html are closed in order and that its links
+// work. Anchors are looked up on the level page the tutorial is shown on.
+func (l *linter) checkHTML(loc Location, html template.HTML, level content.Level, t content.Tutorial) {
+	var open []string
+	for _, m := range tag.FindAllStringSubmatch(string(html), -1) {
+		name := strings.ToLower(m[2])
+		switch {
+		case name == "" || voidElements[name] || m[3] == "/":
+			// A comment or an element without content
+		case m[1] == "":
+			open = append(open, name)
+		case len(open) == 0 || open[len(open)-1] != name:
+			if len(open) == 0 {
+				l.add("unclosed-tag", loc, "</%s> closes a tag that was never opened", name)
+			} else {
+				l.add("unclosed-tag", loc, "</%s> found while <%s> is still open", name, open[len(open)-1])
+			}
+			// Recover by closing up to the matching tag, if it is open
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i] == name {
+					open = open[:i]
+					break
+				}
+			}
+		default:
+			open = open[:len(open)-1]
+		}
+	}
+	for _, name := range open {
+		l.add("unclosed-tag", loc, "<%s> is never closed", name)
+	}
+
+	for _, m := range link.FindAllStringSubmatch(string(html), -1) {
+		if problem := l.checkLink(m[1], level); problem != "" {
+			l.add("broken-link", loc, "%s: %s", m[1], problem)
+		}
+	}
+}
+
+// checkLink returns what is wrong with a link, or "" if it works or cannot
+// be checked. Links to other sites are not followed.
+func (l *linter) checkLink(target string, level content.Level) string {
+	if strings.HasPrefix(target, "//") || strings.Contains(target, "://") || strings.HasPrefix(target, "mailto:") {
+		return ""
+	}
+	target, fragment, _ := strings.Cut(target, "#")
+	target, _, _ = strings.Cut(target, "?")
+
+	page := level.Path
+	if target != "" {
+		if !strings.HasPrefix(target, "/") {
+			return "links to the site must start with /"
+		}
+		if len(l.Routes) == 0 {
+			return ""
+		}
+		if _, rest, ok := i18n.SplitPath(target); ok {
+			target = rest
+		}
+		if !l.served(target) {
+			return "no page is served at " + target
+		}
+		if strings.HasPrefix(target, "/static/") && l.Root != "" {
+			if _, err := os.Stat(filepath.Join(l.Root, filepath.FromSlash(strings.TrimPrefix(target, "/")))); err != nil {
+				return "there is no such static file"
+			}
+		}
+		page = target
+	}
+
+	if fragment == "" {
+		return ""
+	}
+	for _, lv := range l.Levels {
+		if lv.Path == page {
+			if !hasAnchor(lv, fragment) {
+				return "there is no #" + fragment + " on " + page
+			}
+			return ""
+		}
+	}
+	// Anchors on other pages are not known
+	return ""
+}
+
+// served reports whether one of the routes serves path, matching patterns
+// the way http.ServeMux does. The home page pattern only serves "/".
+func (l *linter) served(p string) bool {
+	for _, pattern := range l.Routes {
+		switch {
+		case pattern == "/":
+			if p == "/" {
+				return true
+			}
+		case strings.HasSuffix(pattern, "/"):
+			if strings.HasPrefix(p, pattern) {
+				return true
+			}
+		case p == pattern:
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnchor reports whether a level page has an element with the ID: a
+// tutorial section or a line of one of its code blocks
+func hasAnchor(level content.Level, id string) bool {
+	for _, t := range level.Tutorials {
+		if id == t.ID {
+			return true
+		}
+		for i, block := range t.Code {
+			prefix := t.CodeAnchor(i) + "-L"
+			if !strings.HasPrefix(id, prefix) {
+				continue
+			}
+			n, err := strconv.Atoi(strings.TrimPrefix(id, prefix))
+			lines := highlight.LineCount(highlight.TrimSource(block.Source))
+			if err == nil && n >= 1 && n <= lines {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package lint
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/content"
+)
+
+// write creates a file under dir, with its directories
+func write(t *testing.T, dir, name, data string) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func tutorial(id string) content.Tutorial {
+	return content.Tutorial{
+		ID:          id,
+		Title:       "Title",
+		Description: "<p>Description</p>",
+		Explanation: "<p>Explanation</p>",
+		Code:        []content.CodeBlock{{Filename: "main.go", Source: "package main\n\nfunc main() {}"}},
+	}
+}
+
+func TestCheck(t *testing.T) {
+	root := t.TempDir()
+	write(t, root, TutorialsFile, "package content\n\n\t\tID:    \"first\",\n\n\t\tID: \"second\",\n")
+	write(t, root, ExamplesFile, "package content\n\n\t\tFilename: \"echo_server.go\",\n")
+	write(t, root, "static/css/style.css", "")
+	write(t, root, ExamplesDir+"/echo_server/echo_server.go", "")
+	write(t, root, ExamplesDir+"/echo_server/server.go", "")
+	write(t, root, ExamplesDir+"/old_server/old_server.go", "")
+
+	first, second, third := tutorial("first"), tutorial("second"), tutorial("first")
+	first.Description = `<p>See <a href="/intermediate">the next level</a>, <a href="/intermedaite">this typo</a>,
+		<a href="/es/basic#second">a translation</a>, <a href="/static/css/style.css">a file</a>,
+		<a href="/static/css/missing.css">a missing file</a> and <a href="https://go.dev/">Go</a>.`
+	first.Explanation = `<p><a href="#first-1-L3">Line 3</a> and <a href="#first-1-L9">line 9</a><br>
+		<img src="/static/css/style.css"></p></ul>`
+	second.Explanation = ""
+	second.Code[0].Callouts = []content.Callout{{Line: 1, Note: "<code>package</code> <b>names"}}
+	third.Title = ""
+
+	c := Content{
+		Levels: []content.Level{
+			{ID: "basic", Path: "/basic", Tutorials: []content.Tutorial{first, second}},
+			{ID: "intermediate", Path: "/intermediate", Tutorials: []content.Tutorial{third}},
+		},
+		Examples: []content.CodeExample{
+			{Filename: "echo_server.go", Title: "Echo", Description: "Echo", Code: "package main"},
+			{Filename: "Edited Server.go", Title: "Edited", Description: "<em>Edited"},
+		},
+		Routes: []string{"/static/", "/", "/basic", "/intermediate"},
+		Root:   root,
+		Edited: map[string]bool{"example/Edited Server.go": true},
+	}
+	report := Check(c)
+
+	var got []string
+	for _, f := range report.Findings {
+		got = append(got, f.String())
+	}
+	want := []string{
+		`content/tutorials.go:3: tutorial first Description: error [unclosed-tag] <p> is never closed`,
+		`content/tutorials.go:3: tutorial first Description: error [broken-link] /intermedaite: no page is served at /intermedaite`,
+		`content/tutorials.go:3: tutorial first Description: error [broken-link] /static/css/missing.css: there is no such static file`,
+		`content/tutorials.go:3: tutorial first Explanation: error [unclosed-tag] </ul> closes a tag that was never opened`,
+		`content/tutorials.go:3: tutorial first Explanation: error [broken-link] #first-1-L9: there is no #first-1-L9 on /basic`,
+		`content/tutorials.go:3: tutorial first ID: error [duplicate-id] tutorial "first" is on both the basic and intermediate levels`,
+		`content/tutorials.go:3: tutorial first Title: error [missing-title] tutorial has no title`,
+		`content/tutorials.go:5: tutorial second Explanation: warning [missing-explanation] tutorial does not explain its code`,
+		`content/tutorials.go:5: tutorial second Code[1].Callouts[1]: error [unclosed-tag] <b> is never closed`,
+		`data/content.json: example Edited Server.go Filename: error [example-filename] "Edited Server.go" is not a lowercase Go filename such as echo_server.go`,
+		`data/content.json: example Edited Server.go Code: error [empty-code] example has no code`,
+		`data/content.json: example Edited Server.go Description: error [unclosed-tag] <em> is never closed`,
+		`static/examples/echo_server/server.go: directory echo_server: error [example-directory] server.go does not match the example's filename echo_server.go`,
+		`static/examples/old_server: directory old_server: warning [stale-example] no example is named old_server.go`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if report.Errors != len(want)-2 || report.Warnings != 2 {
+		t.Errorf("got %d errors and %d warnings", report.Errors, report.Warnings)
+	}
+}
+
+func TestCheckWithoutFiles(t *testing.T) {
+	// Without routes or a root, only the content itself is checked
+	c := Content{
+		Levels: []content.Level{{ID: "basic", Path: "/basic", Tutorials: []content.Tutorial{tutorial("first")}}},
+	}
+	c.Levels[0].Tutorials[0].Description = `<a href="/anywhere">Anywhere</a> <a href="relative">relative</a>`
+	report := Check(c)
+	if len(report.Findings) != 1 || report.Findings[0].Message != "relative: links to the site must start with /" {
+		t.Errorf("findings: %v", report.Findings)
+	}
+	if loc := report.Findings[0].Location; loc.File != TutorialsFile || loc.Line != 0 {
+		t.Errorf("location %v", loc)
+	}
+}
+
+func TestCheckSource(t *testing.T) {
+	// The sanitizer closes the <p>, so lint has to see the tutorial as written
+	raw := tutorial("unclosed")
+	raw.Description = "<p>Never closed"
+	levels := content.Published{Tutorials: []content.PublishedTutorial{{Level: "basic", Tutorial: raw}}}.SourceLevels()
+
+	var found bool
+	for _, f := range Check(Content{Levels: levels}).Findings {
+		found = found || (f.Location.Item == "tutorial unclosed" && f.Message == "<p> is never closed")
+	}
+	if !found {
+		t.Error("unclosed <p> in the source was not reported")
+	}
+
+	sanitized, stripped := content.ContentPolicy.SanitizeTutorial(raw)
+	if len(stripped) != 1 || stripped[0].Removed != "unclosed <p> element" {
+		t.Errorf("sanitizer reported %v", stripped)
+	}
+	if findings := Check(Content{Levels: []content.Level{{ID: "basic", Path: "/basic", Tutorials: []content.Tutorial{sanitized}}}}).Findings; len(findings) != 0 {
+		t.Errorf("sanitized tutorial: %v", findings)
+	}
+}
+
+func TestCleanReportJSON(t *testing.T) {
+	report := Check(Content{Levels: []content.Level{{ID: "basic", Path: "/basic", Tutorials: []content.Tutorial{tutorial("first")}}}})
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"findings":[],"errors":0,"warnings":0}`; got != want {
+		t.Errorf("JSON = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,506 @@
+// Package live runs the tutorial's example servers as managed subprocesses
+// and reverse-proxies requests to them, so learners can try an example
+// without downloading it.
+package live
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"golang-webserver-tutorial/sandbox"
+)
+
+var (
+	// ErrTooMany is returned when the maximum number of examples is running
+	ErrTooMany = errors.New("live: too many examples are running, try again later")
+
+	// ErrOwnerLimit is returned when someone starts more examples than
+	// Config.MaxPerOwner
+	ErrOwnerLimit = errors.New("live: you already have an example running; stop it before starting another")
+
+	// ErrNotRunning is returned when an example has not been started
+	ErrNotRunning = errors.New("live: example is not running")
+)
+
+// Config controls how many examples run and for how long
+type Config struct {
+	// MaxInstances caps the number of examples running at once
+	MaxInstances int
+
+	// MaxPerOwner caps the number of examples one person may have started
+	// and still running
+	MaxPerOwner int
+
+	// IdleTimeout stops examples that have not been used for this long
+	IdleTimeout time.Duration
+
+	// LogLines is how many lines of output are kept for each example
+	LogLines int
+
+	// StartTimeout is how long to wait for a started example to accept connections
+	StartTimeout time.Duration
+}
+
+// Manager starts, proxies and stops example servers. Examples run outside
+// the sandbox with network access, so callers must only start the site's own
+// code (see content.CodeExample.CanRunLive); only the build goes through it.
+type Manager struct {
+	sandbox  *sandbox.Sandbox
+	buildDir string
+	config   Config
+
+	mu        sync.Mutex
+	instances map[string]*Instance
+
+	// building maps examples being started to who started them
+	building map[string]string
+}
+
+// DefaultBuildDir is where example binaries are kept, in the user's cache
+// directory rather than a shared temporary one where others could plant
+// a binary of their own
+func DefaultBuildDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "go-tutorial-live")
+}
+
+// NewManager creates a manager that compiles examples with sb and keeps the
+// binaries in buildDir
+func NewManager(sb *sandbox.Sandbox, buildDir string, config Config) *Manager {
+	if config.MaxInstances == 0 {
+		config.MaxInstances = 3
+	}
+	if config.MaxPerOwner == 0 {
+		config.MaxPerOwner = 1
+	}
+	if config.IdleTimeout == 0 {
+		config.IdleTimeout = 5 * time.Minute
+	}
+	if config.LogLines == 0 {
+		config.LogLines = 200
+	}
+	if config.StartTimeout == 0 {
+		config.StartTimeout = 5 * time.Second
+	}
+	return &Manager{
+		sandbox:   sb,
+		buildDir:  buildDir,
+		config:    config,
+		instances: make(map[string]*Instance),
+		building:  make(map[string]string),
+	}
+}
+
+// Status describes a running example
+type Status struct {
+	Name     string
+	Owner    string
+	Addr     string
+	Started  time.Time
+	LastUsed time.Time
+	Log      []string
+}
+
+// Instance is one running example server
+type Instance struct {
+	name    string
+	owner   string
+	addr    string
+	started time.Time
+	cmd     *exec.Cmd
+	proxy   *httputil.ReverseProxy
+	log     *lineLog
+	done    chan struct{}
+
+	mu       sync.Mutex
+	lastUsed time.Time
+}
+
+// Start builds and starts the example called name on behalf of owner, or
+// returns the instance already running. prefix is the path the example is
+// proxied under, such as /live/rest_api, and is used to fix up redirects.
+func (m *Manager) Start(ctx context.Context, owner, name, source, prefix string) (*Instance, error) {
+	m.mu.Lock()
+	if inst, ok := m.instances[name]; ok {
+		m.mu.Unlock()
+		inst.touch()
+		return inst, nil
+	}
+	if _, ok := m.building[name]; ok || len(m.instances)+len(m.building) >= m.config.MaxInstances {
+		m.mu.Unlock()
+		return nil, ErrTooMany
+	}
+	if m.owned(owner) >= m.config.MaxPerOwner {
+		m.mu.Unlock()
+		return nil, ErrOwnerLimit
+	}
+	m.building[name] = owner
+	m.mu.Unlock()
+
+	inst, err := m.start(ctx, owner, name, source, prefix)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.building, name)
+	if err != nil {
+		return nil, err
+	}
+	m.instances[name] = inst
+
+	// Forget the instance if the process exits on its own
+	go func() {
+		<-inst.done
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.instances[name] == inst {
+			delete(m.instances, name)
+		}
+	}()
+	return inst, nil
+}
+
+// owned counts the examples owner has running or starting; m.mu must be held
+func (m *Manager) owned(owner string) int {
+	n := 0
+	for _, inst := range m.instances {
+		if inst.owner == owner {
+			n++
+		}
+	}
+	for _, o := range m.building {
+		if o == owner {
+			n++
+		}
+	}
+	return n
+}
+
+func (m *Manager) start(ctx context.Context, owner, name, source, prefix string) (*Instance, error) {
+	bin, err := m.build(ctx, name, source)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := freeAddr()
+	if err != nil {
+		return nil, err
+	}
+
+	inst := &Instance{
+		name:     name,
+		owner:    owner,
+		addr:     addr,
+		started:  time.Now(),
+		lastUsed: time.Now(),
+		log:      &lineLog{max: m.config.LogLines},
+		done:     make(chan struct{}),
+	}
+
+	// Run in a scratch directory so examples serving ./static find nothing
+	// of the tutorial server's
+	workDir := filepath.Join(m.buildDir, name+"-run")
+	if err := os.MkdirAll(workDir, 0700); err != nil {
+		return nil, err
+	}
+	inst.cmd = exec.Command(bin)
+	inst.cmd.Dir = workDir
+	inst.cmd.Env = []string{AddrEnv + "=" + addr, "PATH=/usr/bin:/bin", "HOME=" + workDir}
+	inst.cmd.Stdout = inst.log
+	inst.cmd.Stderr = inst.log
+	if err := inst.cmd.Start(); err != nil {
+		return nil, err
+	}
+	go func() {
+		err := inst.cmd.Wait()
+		inst.log.Write([]byte(fmt.Sprintf("[process exited: %v]\n", exitReason(err))))
+		close(inst.done)
+	}()
+
+	if err := waitForListener(addr, inst.done, m.config.StartTimeout); err != nil {
+		inst.stop()
+		return nil, err
+	}
+
+	target := &url.URL{Scheme: "http", Host: addr}
+	inst.proxy = httputil.NewSingleHostReverseProxy(target)
+	director := inst.proxy.Director
+	inst.proxy.Director = func(r *http.Request) {
+		director(r)
+		// The tutorial site's cookies are none of the example's business
+		r.Header.Del("Cookie")
+	}
+	inst.proxy.ModifyResponse = func(resp *http.Response) error {
+		// Keep redirects inside the proxied path
+		if loc := resp.Header.Get("Location"); strings.HasPrefix(loc, "/") && !strings.HasPrefix(loc, "//") {
+			resp.Header.Set("Location", prefix+loc)
+		}
+		// Treat example pages as a separate origin so they cannot script the tutorial site
+		resp.Header.Set("Content-Security-Policy", "sandbox allow-forms")
+		resp.Header.Del("Set-Cookie")
+		return nil
+	}
+	return inst, nil
+}
+
+// build compiles the rewritten example once per source version
+func (m *Manager) build(ctx context.Context, name, source string) (string, error) {
+	rewritten, err := Rewrite(source)
+	if err != nil {
+		return "", err
+	}
+	// The binaries run outside the sandbox, so only trust ones in a
+	// directory nobody else can write to
+	if err := privateDir(m.buildDir); err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256([]byte(rewritten))
+	bin := filepath.Join(m.buildDir, name+"-"+hex.EncodeToString(sum[:8]))
+	if cached(bin) {
+		return bin, nil
+	}
+
+	mod, err := m.sandbox.NewModule("example", map[string]string{"main.go": rewritten})
+	if err != nil {
+		return "", err
+	}
+	defer mod.Close()
+
+	// Sandboxed commands can only write inside their module, so the binary
+	// is copied out once it is built
+	var out strings.Builder
+	result, err := m.sandbox.Go(ctx, mod, sandbox.DefaultLimits, &out, &out, "build", "-o", "example", ".")
+	if err != nil {
+		return "", err
+	}
+	if result.ExitCode != 0 || result.TimedOut {
+		return "", fmt.Errorf("live: building %s failed: %s", name, out.String())
+	}
+	if err := copyFile(bin, filepath.Join(mod.Dir, "example")); err != nil {
+		return "", err
+	}
+	return bin, nil
+}
+
+// privateDir creates dir if needed and checks that it is a directory owned
+// by this process's user that nobody else can access
+func privateDir(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() || info.Mode().Perm()&0077 != 0 || !ownedByUs(info) {
+		return fmt.Errorf("live: %s must be a directory that only its owner can access", dir)
+	}
+	return nil
+}
+
+// cached reports whether bin is a binary this process built earlier: a
+// regular file of ours that nobody else can modify
+func cached(bin string) bool {
+	info, err := os.Lstat(bin)
+	return err == nil && info.Mode().IsRegular() && info.Mode().Perm()&0022 == 0 && ownedByUs(info)
+}
+
+// copyFile copies the executable at src to dst, writing it under a temporary
+// name first so a partly copied binary is never run
+func copyFile(dst, src string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.CreateTemp(filepath.Dir(dst), filepath.Base(dst)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(out.Name())
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(out.Name(), 0755); err != nil {
+		return err
+	}
+	return os.Rename(out.Name(), dst)
+}
+
+// Get returns the running instance of the example called name
+func (m *Manager) Get(name string) (*Instance, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	inst, ok := m.instances[name]
+	return inst, ok
+}
+
+// Stop stops the example called name if it is running
+func (m *Manager) Stop(name string) {
+	m.mu.Lock()
+	inst, ok := m.instances[name]
+	delete(m.instances, name)
+	m.mu.Unlock()
+	if ok {
+		inst.stop()
+	}
+}
+
+// StopIdle stops every example that has not been used within the idle timeout
+func (m *Manager) StopIdle() {
+	m.mu.Lock()
+	var idle []*Instance
+	for name, inst := range m.instances {
+		if time.Since(inst.LastUsed()) > m.config.IdleTimeout {
+			idle = append(idle, inst)
+			delete(m.instances, name)
+		}
+	}
+	m.mu.Unlock()
+	for _, inst := range idle {
+		inst.stop()
+	}
+}
+
+// StopAll stops every running example
+func (m *Manager) StopAll() {
+	m.mu.Lock()
+	instances := m.instances
+	m.instances = make(map[string]*Instance)
+	m.mu.Unlock()
+	for _, inst := range instances {
+		inst.stop()
+	}
+}
+
+// ServeHTTP proxies a request to the example, which must already have any
+// path prefix stripped
+func (i *Instance) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	i.touch()
+	i.proxy.ServeHTTP(w, r)
+}
+
+// Status reports the example's address, timings and recent output
+func (i *Instance) Status() Status {
+	return Status{
+		Name:     i.name,
+		Owner:    i.owner,
+		Addr:     i.addr,
+		Started:  i.started,
+		LastUsed: i.LastUsed(),
+		Log:      i.log.Lines(),
+	}
+}
+
+// Owner returns who started the example
+func (i *Instance) Owner() string {
+	return i.owner
+}
+
+// LastUsed returns when the example last served a request
+func (i *Instance) LastUsed() time.Time {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.lastUsed
+}
+
+func (i *Instance) touch() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.lastUsed = time.Now()
+}
+
+// stop kills the process and waits for it to exit
+func (i *Instance) stop() {
+	i.cmd.Process.Kill()
+	<-i.done
+}
+
+// freeAddr finds a free loopback port for an example to listen on
+func freeAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	addr := l.Addr().String()
+	l.Close()
+	return addr, nil
+}
+
+// waitForListener waits until addr accepts connections, the process exits or
+// the timeout passes
+func waitForListener(addr string, exited <-chan struct{}, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		select {
+		case <-exited:
+			return errors.New("live: example exited before it started listening")
+		default:
+		}
+		if conn, err := net.DialTimeout("tcp", addr, 100*time.Millisecond); err == nil {
+			conn.Close()
+			return nil
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return errors.New("live: example did not start listening in time")
+}
+
+func exitReason(err error) string {
+	if err == nil {
+		return "exit status 0"
+	}
+	return err.Error()
+}
+
+// lineLog keeps the last lines written to it
+type lineLog struct {
+	mu      sync.Mutex
+	max     int
+	lines   []string
+	partial string
+}
+
+func (l *lineLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	text := l.partial + string(p)
+	parts := strings.Split(text, "\n")
+	l.partial = parts[len(parts)-1]
+	l.lines = append(l.lines, parts[:len(parts)-1]...)
+	if len(l.lines) > l.max {
+		l.lines = append([]string(nil), l.lines[len(l.lines)-l.max:]...)
+	}
+	return len(p), nil
+}
+
+// Lines returns the kept lines, including any unfinished last line
+func (l *lineLog) Lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	lines := append([]string(nil), l.lines...)
+	if l.partial != "" {
+		lines = append(lines, l.partial)
+	}
+	return lines
+}
@@ -0,0 +1,143 @@
+package live
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"golang-webserver-tutorial/sandbox"
+)
+
+const redirectServer = `package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+)
+
+func main() {
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusFound)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "example", Value: "1"})
+		fmt.Fprintf(w, "path=%s cookie=%q", r.URL.Path, r.Header.Get("Cookie"))
+	})
+	log.Println("listening")
+	log.Fatal(http.ListenAndServe(":8080", nil))
+}
+`
+
+func TestManager(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles Go code")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox requires Linux")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	sb := sandbox.New(sandbox.DefaultCacheDir(), 2)
+	if err := sb.Warm(context.Background(), sandbox.WarmImports); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewManager(sb, filepath.Join(t.TempDir(), "live"), Config{MaxInstances: 1})
+	defer m.StopAll()
+
+	inst, err := m.Start(context.Background(), "alice", "hello", redirectServer, "/live/hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Start(context.Background(), "bob", "other", redirectServer, "/live/other"); err != ErrTooMany {
+		t.Errorf("second example: got %v, want ErrTooMany", err)
+	}
+	m.config.MaxInstances = 2
+	if _, err := m.Start(context.Background(), "alice", "other", redirectServer, "/live/other"); err != ErrOwnerLimit {
+		t.Errorf("second example of one owner: got %v, want ErrOwnerLimit", err)
+	}
+
+	req := httptest.NewRequest("GET", "/greeting", nil)
+	req.Header.Set("Cookie", "session=secret")
+	rr := httptest.NewRecorder()
+	inst.ServeHTTP(rr, req)
+	body, _ := io.ReadAll(rr.Body)
+	if string(body) != `path=/greeting cookie=""` {
+		t.Errorf("proxied body = %q", body)
+	}
+	if rr.Header().Get("Set-Cookie") != "" {
+		t.Error("Set-Cookie was passed through")
+	}
+	if csp := rr.Header().Get("Content-Security-Policy"); !strings.HasPrefix(csp, "sandbox") {
+		t.Errorf("Content-Security-Policy = %q", csp)
+	}
+
+	rr = httptest.NewRecorder()
+	inst.ServeHTTP(rr, httptest.NewRequest("GET", "/old", nil))
+	if rr.Code != http.StatusFound || rr.Header().Get("Location") != "/live/hello/new" {
+		t.Errorf("redirect: %d to %q", rr.Code, rr.Header().Get("Location"))
+	}
+
+	if log := inst.Status().Log; len(log) == 0 || !strings.Contains(log[0], "listening") {
+		t.Errorf("log = %q", log)
+	}
+
+	// An idle example is stopped and forgotten
+	m.config.IdleTimeout = time.Nanosecond
+	m.StopIdle()
+	if _, ok := m.Get("hello"); ok {
+		t.Error("idle example is still running")
+	}
+	select {
+	case <-inst.done:
+	default:
+		t.Error("idle example's process was not stopped")
+	}
+}
+
+func TestBuildDir(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+	dir := t.TempDir()
+	os.Chmod(dir, 0777)
+	m := NewManager(nil, dir, Config{})
+	if _, err := m.build(context.Background(), "hello", redirectServer); err == nil || !strings.Contains(err.Error(), "only its owner") {
+		t.Errorf("shared build directory: got %v", err)
+	}
+
+	dir = filepath.Join(t.TempDir(), "live")
+	if err := privateDir(dir); err != nil {
+		t.Fatal(err)
+	}
+	bin := filepath.Join(dir, "hello-0123")
+	os.WriteFile(bin, []byte("#!/bin/sh\n"), 0777)
+	os.Chmod(bin, 0777)
+	if cached(bin) {
+		t.Error("a binary anyone can modify was trusted")
+	}
+	os.Chmod(bin, 0755)
+	if !cached(bin) {
+		t.Error("a binary of ours was not trusted")
+	}
+}
+
+func TestLineLog(t *testing.T) {
+	l := &lineLog{max: 2}
+	io.WriteString(l, "one\ntw")
+	io.WriteString(l, "o\nthree\nfour")
+	if got := strings.Join(l.Lines(), ","); got != "two,three,four" {
+		t.Errorf("Lines() = %q", got)
+	}
+}
@@ -0,0 +1,124 @@
+package live
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+)
+
+// AddrEnv is the environment variable that holds the address a rewritten
+// example listens on
+const AddrEnv = "LIVE_ADDR"
+
+// ErrNoServer is returned when an example never calls http.ListenAndServe
+var ErrNoServer = errors.New("live: example does not start an HTTP server")
+
+// Rewrite changes an example so every http.ListenAndServe call and
+// http.Server literal listens on the address in $LIVE_ADDR instead of the
+// hard-coded one, importing "os" if needed.
+func Rewrite(source string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "main.go", source, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	httpName := importName(file, "net/http", "http")
+	osName := importName(file, "os", "")
+	if osName == "" {
+		osName = "os"
+		addImport(file, "os")
+	}
+	getenv := func() ast.Expr {
+		return &ast.CallExpr{
+			Fun:  &ast.SelectorExpr{X: ast.NewIdent(osName), Sel: ast.NewIdent("Getenv")},
+			Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(AddrEnv)}},
+		}
+	}
+
+	found := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CallExpr:
+			if isSelector(n.Fun, httpName, "ListenAndServe") && len(n.Args) == 2 {
+				n.Args[0] = getenv()
+				found = true
+			}
+		case *ast.CompositeLit:
+			if isSelector(n.Type, httpName, "Server") {
+				for _, elt := range n.Elts {
+					if kv, ok := elt.(*ast.KeyValueExpr); ok {
+						if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Addr" {
+							kv.Value = getenv()
+							found = true
+						}
+					}
+				}
+			}
+		}
+		return true
+	})
+	if !found {
+		return "", ErrNoServer
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// importName returns the name a file uses for an imported package, or
+// fallback if the package is not imported
+func importName(file *ast.File, path, fallback string) string {
+	for _, spec := range file.Imports {
+		if p, _ := strconv.Unquote(spec.Path.Value); p == path {
+			if spec.Name != nil {
+				return spec.Name.Name
+			}
+			return lastElem(path)
+		}
+	}
+	return fallback
+}
+
+func lastElem(path string) string {
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] == '/' {
+			return path[i+1:]
+		}
+	}
+	return path
+}
+
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	x, ok := sel.X.(*ast.Ident)
+	return ok && x.Name == pkg && sel.Sel.Name == name
+}
+
+// addImport adds an import to the file's first import declaration
+func addImport(file *ast.File, path string) {
+	spec := &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}}
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			if !gen.Lparen.IsValid() {
+				gen.Lparen = gen.Pos()
+				gen.Rparen = gen.End()
+			}
+			gen.Specs = append(gen.Specs, spec)
+			file.Imports = append(file.Imports, spec)
+			return
+		}
+	}
+	file.Decls = append([]ast.Decl{&ast.GenDecl{Tok: token.IMPORT, Specs: []ast.Spec{spec}}}, file.Decls...)
+	file.Imports = append(file.Imports, spec)
+}
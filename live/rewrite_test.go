@@ -0,0 +1,53 @@
+package live
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/content"
+)
+
+func TestRewriteExamples(t *testing.T) {
+	for _, example := range content.GetCodeExamples() {
+		out, err := Rewrite(example.Code)
+		if err != nil {
+			t.Errorf("%s: %v", example.Filename, err)
+			continue
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), example.Filename, out, 0); err != nil {
+			t.Errorf("%s: rewritten source does not parse: %v", example.Filename, err)
+		}
+		if !strings.Contains(out, `os.Getenv("LIVE_ADDR")`) || strings.Contains(out, `ListenAndServe("localhost:8080"`) {
+			t.Errorf("%s: address was not replaced:\n%s", example.Filename, out)
+		}
+	}
+}
+
+func TestRewriteServerLiteral(t *testing.T) {
+	src := `package main
+
+import (
+	"net/http"
+	"os"
+)
+
+func main() {
+	srv := &http.Server{Addr: ":8080", Handler: nil}
+	srv.ListenAndServe()
+	os.Exit(0)
+}
+`
+	out, err := Rewrite(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, `Addr: os.Getenv("LIVE_ADDR")`) || strings.Count(out, `"os"`) != 1 {
+		t.Errorf("unexpected rewrite:\n%s", out)
+	}
+
+	if _, err := Rewrite("package main\n\nfunc main() {}\n"); err != ErrNoServer {
+		t.Errorf("program without a server: error = %v", err)
+	}
+}
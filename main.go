@@ -1,41 +1,167 @@
 package main
 
 import (
+        "context"
         "fmt"
         "log"
         "net/http"
+        "os"
+        "os/signal"
         "path/filepath"
+        "strconv"
+        "strings"
+        "syscall"
         "time"
 
+        "golang-webserver-tutorial/auth"
+        "golang-webserver-tutorial/authoring"
+        "golang-webserver-tutorial/content"
         "golang-webserver-tutorial/handlers"
+        "golang-webserver-tutorial/progress"
+        "golang-webserver-tutorial/reload"
+        "golang-webserver-tutorial/sandbox"
 )
 
 func main() {
         // Define server port
         port := "5000"
+        var err error
 
-        // Create a file server for static assets
-        fs := http.FileServer(http.Dir("static"))
-        http.Handle("/static/", http.StripPrefix("/static/", fs))
+        // Show tutorials and examples published from the admin area
+        handlers.Authoring, err = authoring.NewFileStore(filepath.Join("data", "content.json"))
+        if err != nil {
+                log.Fatalf("Failed to load edited content: %v", err)
+        }
+        stripped := content.SetPublished(handlers.Authoring.Published())
+        // Drafts need REVIEW_APPROVALS approvals from reviewers before they go live
+        if n, err := strconv.Atoi(os.Getenv("REVIEW_APPROVALS")); err == nil && n >= 0 {
+                handlers.Authoring.RequiredApprovals = n
+        }
+
+        // Refuse to start if tutorial prerequisites are missing or form a cycle
+        if _, err := content.LoadGraph(); err != nil {
+                log.Fatalf("Invalid tutorial content: %v", err)
+        }
+
+        // Content is sanitized as it is loaded; log what was removed so it can be fixed
+        for _, s := range append(content.BuiltinStripped(), stripped...) {
+                log.Printf("Sanitized content: %s", s)
+        }
+
+        // Translations made before their tutorial was last updated are shown with a notice
+        for _, stale := range content.StaleTranslations() {
+                log.Printf("Translation %s/%s is out of date: translated from %s, tutorial updated %s",
+                        stale.Locale, stale.TutorialID, stale.Source.Format("2006-01-02"), stale.Updated.Format("2006-01-02"))
+        }
+
+        // Store user accounts on disk; SESSION_SECRET keeps people logged in across
+        // restarts and users named in ADMINS are always admins
+        users, err := auth.NewFileUserStore(filepath.Join("data", "users.json"))
+        if err != nil {
+                log.Fatalf("Failed to load users: %v", err)
+        }
+        sessions := auth.NewMemorySessionStore()
+        var admins []string
+        for _, name := range strings.Split(os.Getenv("ADMINS"), ",") {
+                if name = strings.TrimSpace(name); name != "" {
+                        admins = append(admins, name)
+                }
+        }
+        handlers.Accounts = auth.NewManager(users, sessions, auth.Config{
+                Secret: []byte(os.Getenv("SESSION_SECRET")),
+                Admins: admins,
+        })
+
+        // Store tutorial progress for each learner
+        handlers.Progress, err = progress.NewFileStore(filepath.Join("data", "progress.json"))
+        if err != nil {
+                log.Fatalf("Failed to load progress: %v", err)
+        }
+
+        // Remove expired sessions periodically
+        go func() {
+                for now := range time.Tick(time.Hour) {
+                        sessions.DeleteExpired(now)
+                }
+        }()
+
+        // Stop live examples nobody is using, forget expired practice APIs and
+        // publish scheduled drafts
+        go func() {
+                for now := range time.Tick(time.Minute) {
+                        handlers.Live.StopIdle()
+                        handlers.Books.DeleteExpired(now)
+                        handlers.PublishScheduled(now)
+                }
+        }()
+
+        // Don't leave live examples running when the server is stopped
+        go func() {
+                stop := make(chan os.Signal, 1)
+                signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+                <-stop
+                handlers.Live.StopAll()
+                os.Exit(0)
+        }()
+
+        // Build absolute URLs in feeds, the sitemap and pages from SITE_URL rather
+        // than the Host header, which clients can forge
+        if raw := os.Getenv("SITE_URL"); raw != "" {
+                handlers.SiteURL, err = handlers.ParseSiteURL(raw)
+                if err != nil {
+                        log.Fatal(err)
+                }
+        } else {
+                log.Printf("SITE_URL is not set; feeds and the sitemap use the request's host")
+        }
 
-        // Register route handlers
-        http.HandleFunc("/", handlers.HomeHandler)
-        http.HandleFunc("/basic", handlers.BasicHandler)
-        http.HandleFunc("/intermediate", handlers.IntermediateHandler)
-        http.HandleFunc("/advanced", handlers.AdvancedHandler)
-        http.HandleFunc("/restful", handlers.RestfulHandler)
-        http.HandleFunc("/examples", handlers.ExamplesHandler)
-        http.HandleFunc("/download/", handlers.DownloadHandler)
+        // Send the Content-Security-Policy report-only with CSP_REPORT_ONLY=1, so
+        // violations are logged at /csp-report without blocking anything
+        handlers.Security.ReportOnly = os.Getenv("CSP_REPORT_ONLY") == "1"
+
+        // Register static assets and route handlers, checking each route's permission
+        // and adding security headers
+        handlers.Register(http.DefaultServeMux, handlers.Routes())
+
+        // Build common packages so the first exercise is not slowed down by a cold cache
+        go func() {
+                if err := handlers.Sandbox.Warm(context.Background(), sandbox.WarmImports); err != nil {
+                        log.Printf("Sandbox unavailable: %v", err)
+                }
+        }()
 
         // Create examples directory if it doesn't exist
         examplesDir := filepath.Join("static", "examples")
         handlers.EnsureExamplesGenerated(examplesDir)
 
+        // Parse every template now so mistakes stop the server starting
+        if err := handlers.ReloadTemplates(); err != nil {
+                log.Fatalf("Invalid templates: %v", err)
+        }
+
+        // Pick up changes to templates, static files and edited content without a
+        // restart; HOT_RELOAD=1 also reloads open pages when they change. The
+        // built-in content in content/*.go is compiled in and needs a restart.
+        handlers.HotReload = os.Getenv("HOT_RELOAD") == "1"
+        watcher := reload.NewWatcher("templates", "static", filepath.Join("data", "content.json"))
+        watcher.Skip = func(path string) bool {
+                name := filepath.Base(path)
+                return path == examplesDir || strings.HasPrefix(name, ".") || strings.HasSuffix(name, "~") || strings.HasSuffix(name, ".tmp")
+        }
+        go watcher.Run(context.Background(), time.Second, func(paths []string, err error) {
+                if err != nil {
+                        log.Printf("Watching files failed: %v", err)
+                        return
+                }
+                handlers.ApplyChanges(paths)
+        })
+
         // Configure server
         server := &http.Server{
                 Addr:           "0.0.0.0:" + port,
+                Handler:        handlers.Localize(http.DefaultServeMux),
                 ReadTimeout:    10 * time.Second,
-                WriteTimeout:   10 * time.Second,
+                WriteTimeout:   90 * time.Second, // leaves room for grading exercises
                 MaxHeaderBytes: 1 << 20,
         }
 
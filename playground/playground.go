@@ -0,0 +1,207 @@
+// Package playground builds and runs Go programs submitted from the browser,
+// streaming their output as a sequence of events and caching results by
+// source hash.
+package playground
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"golang-webserver-tutorial/sandbox"
+)
+
+// MaxSourceSize is the largest program accepted, in bytes
+const MaxSourceSize = 64 << 10
+
+// ErrTooLarge is returned for programs over MaxSourceSize
+var ErrTooLarge = errors.New("playground: program is too large")
+
+// Event kinds, in the order they can appear
+const (
+	// KindBuild carries compiler output
+	KindBuild = "build"
+
+	// KindStdout and KindStderr carry the program's output
+	KindStdout = "stdout"
+	KindStderr = "stderr"
+
+	// KindExit is always the last event
+	KindExit = "exit"
+)
+
+// Event is one piece of a run's output
+type Event struct {
+	Kind string `json:"kind"`
+	Data string `json:"data,omitempty"`
+
+	// The remaining fields are only set on the exit event
+	ExitCode  *int  `json:"exit_code,omitempty"`
+	TimedOut  bool  `json:"timed_out,omitempty"`
+	Truncated bool  `json:"truncated,omitempty"`
+	Cached    bool  `json:"cached,omitempty"`
+	Duration  int64 `json:"duration_ms,omitempty"`
+}
+
+// Playground runs programs in a sandbox and remembers recent results
+type Playground struct {
+	Sandbox *sandbox.Sandbox
+
+	// Build limits compiling; Run limits the program itself
+	Build sandbox.Limits
+	Run   sandbox.Limits
+
+	// CacheSize is how many results are kept; zero disables caching
+	CacheSize int
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]*list.Element
+	lru   list.List
+}
+
+type cacheEntry struct {
+	key    [sha256.Size]byte
+	events []Event
+}
+
+// New creates a playground with limits suited to short example programs
+func New(sb *sandbox.Sandbox) *Playground {
+	run := sandbox.DefaultLimits
+	run.Timeout = 10 * time.Second
+	run.CPU = 5 * time.Second
+	return &Playground{
+		Sandbox:   sb,
+		Build:     sandbox.DefaultLimits,
+		Run:       run,
+		CacheSize: 256,
+	}
+}
+
+// Execute builds and runs source as package main, calling emit for each
+// event as it happens. Results of earlier runs of the same source are
+// replayed from the cache.
+func (p *Playground) Execute(ctx context.Context, source string, emit func(Event)) error {
+	if len(source) > MaxSourceSize {
+		return ErrTooLarge
+	}
+
+	key := sha256.Sum256([]byte(source))
+	if events, ok := p.lookup(key); ok {
+		for _, event := range events {
+			if event.Kind == KindExit {
+				event.Cached = true
+			}
+			emit(event)
+		}
+		return nil
+	}
+
+	// Record events as they are sent so the run can be cached
+	var mu sync.Mutex
+	var events []Event
+	record := func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		emit(event)
+	}
+
+	exit, err := p.execute(ctx, source, record)
+	if err != nil {
+		return err
+	}
+	record(exit)
+
+	// A run cut short by the client going away says nothing about the program
+	if ctx.Err() == nil {
+		p.store(key, events)
+	}
+	return nil
+}
+
+// execute builds and runs source, returning the exit event
+func (p *Playground) execute(ctx context.Context, source string, emit func(Event)) (Event, error) {
+	m, err := p.Sandbox.NewModule("play", map[string]string{"main.go": source})
+	if err != nil {
+		return Event{}, err
+	}
+	defer m.Close()
+
+	start := time.Now()
+	build := eventWriter(KindBuild, emit)
+	result, err := p.Sandbox.Go(ctx, m, p.Build, build, build, "build", "-o", "prog", ".")
+	if err != nil {
+		return Event{}, err
+	}
+	if result.ExitCode != 0 || result.TimedOut {
+		return exitEvent(result, start), nil
+	}
+
+	result, err = p.Sandbox.Exec(ctx, m, p.Run, eventWriter(KindStdout, emit), eventWriter(KindStderr, emit), "prog")
+	if err != nil {
+		return Event{}, err
+	}
+	return exitEvent(result, start), nil
+}
+
+func exitEvent(result sandbox.Result, start time.Time) Event {
+	code := result.ExitCode
+	return Event{
+		Kind:      KindExit,
+		ExitCode:  &code,
+		TimedOut:  result.TimedOut,
+		Truncated: result.Truncated,
+		Duration:  time.Since(start).Milliseconds(),
+	}
+}
+
+// eventWriter returns a writer that sends each write as an event of the given kind
+func eventWriter(kind string, emit func(Event)) io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		emit(Event{Kind: kind, Data: string(p)})
+		return len(p), nil
+	})
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
+
+// lookup returns cached events for key, marking them recently used
+func (p *Playground) lookup(key [sha256.Size]byte) ([]Event, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	elem, ok := p.cache[key]
+	if !ok {
+		return nil, false
+	}
+	p.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).events, true
+}
+
+// store caches events for key, evicting the least recently used results
+func (p *Playground) store(key [sha256.Size]byte, events []Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.CacheSize <= 0 {
+		return
+	}
+	if p.cache == nil {
+		p.cache = make(map[[sha256.Size]byte]*list.Element)
+	}
+	if elem, ok := p.cache[key]; ok {
+		elem.Value.(*cacheEntry).events = events
+		p.lru.MoveToFront(elem)
+		return
+	}
+	p.cache[key] = p.lru.PushFront(&cacheEntry{key: key, events: events})
+	for p.lru.Len() > p.CacheSize {
+		oldest := p.lru.Back()
+		delete(p.cache, oldest.Value.(*cacheEntry).key)
+		p.lru.Remove(oldest)
+	}
+}
@@ -0,0 +1,105 @@
+package playground
+
+import (
+	"context"
+	"crypto/sha256"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"golang-webserver-tutorial/sandbox"
+)
+
+func TestCacheEviction(t *testing.T) {
+	p := &Playground{CacheSize: 2}
+	key := func(s string) [sha256.Size]byte { return sha256.Sum256([]byte(s)) }
+
+	p.store(key("a"), []Event{{Kind: KindStdout, Data: "a"}})
+	p.store(key("b"), []Event{{Kind: KindStdout, Data: "b"}})
+	p.lookup(key("a")) // a is now the most recently used
+	p.store(key("c"), []Event{{Kind: KindStdout, Data: "c"}})
+
+	if _, ok := p.lookup(key("b")); ok {
+		t.Error("least recently used entry was not evicted")
+	}
+	for _, k := range []string{"a", "c"} {
+		if _, ok := p.lookup(key(k)); !ok {
+			t.Errorf("entry %q was evicted", k)
+		}
+	}
+}
+
+func TestExecute(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiles Go code")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox requires Linux")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	sb := sandbox.New(sandbox.DefaultCacheDir(), 2)
+	if err := sb.Warm(context.Background(), []string{"fmt", "os"}); err != nil {
+		t.Fatal(err)
+	}
+	p := New(sb)
+
+	source := `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Println("to stdout")
+	fmt.Fprintln(os.Stderr, "to stderr")
+	os.Exit(3)
+}
+`
+	run := func() (out map[string]string, exit Event) {
+		out = make(map[string]string)
+		err := p.Execute(context.Background(), source, func(e Event) {
+			if e.Kind == KindExit {
+				exit = e
+				return
+			}
+			out[e.Kind] += e.Data
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return out, exit
+	}
+
+	out, exit := run()
+	if out[KindStdout] != "to stdout\n" || out[KindStderr] != "to stderr\n" {
+		t.Errorf("output = %q", out)
+	}
+	if exit.ExitCode == nil || *exit.ExitCode != 3 || exit.Cached {
+		t.Errorf("exit event = %+v", exit)
+	}
+
+	// The second run is replayed from the cache
+	out, exit = run()
+	if !exit.Cached || out[KindStdout] != "to stdout\n" {
+		t.Errorf("second run: cached = %v, output %q", exit.Cached, out)
+	}
+
+	// Compile errors are reported as build output
+	var build strings.Builder
+	p.Execute(context.Background(), "package main\n\nfunc main() { undefined() }\n", func(e Event) {
+		if e.Kind == KindBuild {
+			build.WriteString(e.Data)
+		}
+	})
+	if !strings.Contains(build.String(), "undefined") {
+		t.Errorf("build output = %q", build.String())
+	}
+
+	if err := p.Execute(context.Background(), strings.Repeat(" ", MaxSourceSize+1), func(Event) {}); err != ErrTooLarge {
+		t.Errorf("oversized program error = %v", err)
+	}
+}
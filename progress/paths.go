@@ -0,0 +1,96 @@
+package progress
+
+import "golang-webserver-tutorial/content"
+
+// Tutorial states on the learning map
+const (
+	StatusCompleted = "completed"
+	StatusUnlocked  = "unlocked"
+	StatusLocked    = "locked"
+)
+
+// Node is a tutorial on the learning map with the learner's status
+type Node struct {
+	Tutorial content.Tutorial
+	Level    content.Level
+	Status   string
+
+	// Missing lists the prerequisites still to finish for a locked tutorial
+	Missing []content.Tutorial
+}
+
+// URL returns the link to the tutorial on its level page
+func (n Node) URL() string {
+	return n.Level.Path + "#" + n.Tutorial.ID
+}
+
+// PathProgress is a learner's progress along a learning path
+type PathProgress struct {
+	Path  content.Path
+	Steps []Node
+	Done  int
+
+	// Next is the first unfinished step, or nil when the path is done
+	Next *Node
+}
+
+// Percent returns the share of the path that is complete, from 0 to 100
+func (p PathProgress) Percent() int {
+	if len(p.Steps) == 0 {
+		return 0
+	}
+	return p.Done * 100 / len(p.Steps)
+}
+
+// Node returns the tutorial with the given ID and the learner's status for it.
+// Tutorials are unlocked once all their prerequisites are complete.
+func (s Summary) Node(g *content.Graph, id string) Node {
+	t, level, _ := g.Tutorial(id)
+	n := Node{Tutorial: t, Level: level, Status: StatusUnlocked}
+	switch {
+	case s.completed[id]:
+		n.Status = StatusCompleted
+	default:
+		if n.Missing = g.Missing(id, s.Completed); len(n.Missing) > 0 {
+			n.Status = StatusLocked
+		}
+	}
+	return n
+}
+
+// Map lays out every tutorial in columns by prerequisite depth, so each
+// tutorial appears to the right of everything it depends on
+func (s Summary) Map(g *content.Graph) [][]Node {
+	var columns [][]Node
+	for _, t := range g.Tutorials() {
+		depth := g.Depth(t.ID)
+		for len(columns) <= depth {
+			columns = append(columns, nil)
+		}
+		columns[depth] = append(columns[depth], s.Node(g, t.ID))
+	}
+	return columns
+}
+
+// Path works out the learner's progress along a learning path
+func (s Summary) Path(g *content.Graph, p content.Path) (PathProgress, error) {
+	steps, err := g.Steps(p)
+	if err != nil {
+		return PathProgress{}, err
+	}
+	pp := PathProgress{Path: p}
+	for _, t := range steps {
+		n := s.Node(g, t.ID)
+		if n.Status == StatusCompleted {
+			pp.Done++
+		}
+		pp.Steps = append(pp.Steps, n)
+	}
+	for i := range pp.Steps {
+		if pp.Steps[i].Status != StatusCompleted {
+			pp.Next = &pp.Steps[i]
+			break
+		}
+	}
+	return pp, nil
+}
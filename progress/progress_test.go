@@ -0,0 +1,108 @@
+package progress
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang-webserver-tutorial/content"
+)
+
+func TestSummarize(t *testing.T) {
+	levels := content.GetLevels()
+	first := levels[0].Tutorials
+
+	s := Summarize(levels, Record{})
+	if s.Started() || s.Next != nil || s.Total == 0 {
+		t.Fatalf("empty record: started=%v next=%v total=%d", s.Started(), s.Next, s.Total)
+	}
+
+	record := Record{
+		Completed:    map[string]time.Time{first[0].ID: time.Now(), first[1].ID: time.Now()},
+		LastTutorial: first[0].ID,
+	}
+	s = Summarize(levels, record)
+	if s.Done != 2 || s.Level(levels[0].ID).Done != 2 {
+		t.Errorf("done = %d, level done = %d, want 2", s.Done, s.Level(levels[0].ID).Done)
+	}
+	if !s.Completed(first[1].ID) || s.Completed(first[2].ID) {
+		t.Error("Completed does not match the record")
+	}
+	// The tutorial after the last completed one is already done, so skip it
+	if s.Next == nil || s.Next.ID != first[2].ID {
+		t.Fatalf("next = %v, want %s", s.Next, first[2].ID)
+	}
+	if want := levels[0].Path + "#" + first[2].ID; s.NextURL() != want {
+		t.Errorf("next URL = %q, want %q", s.NextURL(), want)
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "progress.json")
+	store, err := NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store.SetComplete("anon:a", "hello-world", true)
+	store.SetComplete("anon:a", "serve-html", true)
+	store.SetComplete("anon:a", "serve-html", false)
+	store.SetComplete("user:u", "json-apis", true)
+	store.RecordQuiz("anon:a", "hello-world", 3, 3)
+	store.RecordQuiz("user:u", "hello-world", 1, 3)
+	store.RecordQuiz("user:u", "hello-world", 2, 3)
+	if err := store.Merge("anon:a", "user:u"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reload from disk to check the merge was saved
+	store, err = NewFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, _ := store.Get("user:u")
+	if len(record.Completed) != 2 || record.Completed["hello-world"].IsZero() || record.Completed["json-apis"].IsZero() {
+		t.Errorf("merged record = %+v", record.Completed)
+	}
+	quiz := record.Quizzes["hello-world"]
+	if quiz.Best != 3 || quiz.Last != 2 || quiz.Attempts != 3 {
+		t.Errorf("merged quiz score = %+v", quiz)
+	}
+	if anon, _ := store.Get("anon:a"); len(anon.Completed) != 0 {
+		t.Error("anonymous progress was not removed after merging")
+	}
+}
+
+func TestMapAndPaths(t *testing.T) {
+	g, err := content.LoadGraph()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := Summarize(content.GetLevels(), Record{Completed: map[string]time.Time{"hello-world": time.Now()}})
+
+	columns := s.Map(g)
+	if len(columns) == 0 || len(columns[0]) != 1 || columns[0][0].Status != StatusCompleted {
+		t.Fatalf("first column = %+v", columns[0])
+	}
+	statuses := make(map[string]Node)
+	for _, column := range columns {
+		for _, n := range column {
+			statuses[n.Tutorial.ID] = n
+		}
+	}
+	if statuses["handling-routes"].Status != StatusUnlocked {
+		t.Errorf("handling-routes is %s", statuses["handling-routes"].Status)
+	}
+	if n := statuses["html-templates"]; n.Status != StatusLocked || len(n.Missing) != 2 {
+		t.Errorf("html-templates is %s, missing %d", n.Status, len(n.Missing))
+	}
+
+	p, _ := content.FindPath("rest-api-developer")
+	pp, err := s.Path(g, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pp.Done != 1 || len(pp.Steps) != 4 || pp.Next == nil || pp.Next.Tutorial.ID != "handling-routes" || pp.Percent() != 25 {
+		t.Errorf("path progress: done %d of %d, next %v", pp.Done, len(pp.Steps), pp.Next)
+	}
+}
@@ -0,0 +1,306 @@
+package progress
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"golang-webserver-tutorial/jsonfile"
+)
+
+// Record is everything remembered about one learner
+type Record struct {
+	// Completed maps tutorial IDs to when they were marked complete
+	Completed map[string]time.Time `json:"completed,omitempty"`
+
+	// LastTutorial is the tutorial most recently marked complete
+	LastTutorial string    `json:"last_tutorial,omitempty"`
+	LastActive   time.Time `json:"last_active,omitempty"`
+
+	// Quizzes maps tutorial IDs to the learner's quiz scores
+	Quizzes map[string]QuizScore `json:"quizzes,omitempty"`
+
+	// Submissions maps tutorial IDs to exercise submissions, oldest first
+	Submissions map[string][]Submission `json:"submissions,omitempty"`
+}
+
+// MaxSubmissions is how many submissions are kept for each exercise
+const MaxSubmissions = 20
+
+// Submission is one graded attempt at a tutorial exercise
+type Submission struct {
+	Source    string    `json:"source"`
+	Passed    bool      `json:"passed"`
+	TestsPass int       `json:"tests_passed"`
+	Tests     int       `json:"tests"`
+	Submitted time.Time `json:"submitted"`
+}
+
+// QuizScore records a learner's attempts at one tutorial quiz
+type QuizScore struct {
+	Last     int       `json:"last"`
+	Best     int       `json:"best"`
+	Total    int       `json:"total"`
+	Attempts int       `json:"attempts"`
+	Taken    time.Time `json:"taken"`
+}
+
+// Store persists learner progress. Learners are identified by an opaque key,
+// such as a user ID or an anonymous visitor ID.
+type Store interface {
+	Get(learner string) (Record, error)
+	SetComplete(learner, tutorialID string, done bool) error
+	RecordQuiz(learner, tutorialID string, score, total int) error
+	AddSubmission(learner, tutorialID string, submission Submission) error
+
+	// Merge moves the progress recorded under from into to and removes from
+	Merge(from, to string) error
+}
+
+// MemoryStore keeps progress in memory; it is safe for concurrent use
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+	now     func() time.Time
+}
+
+// NewMemoryStore creates an empty in-memory progress store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record), now: time.Now}
+}
+
+// Get returns the learner's record; learners without progress get an empty record
+func (s *MemoryStore) Get(learner string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return copyRecord(s.records[learner]), nil
+}
+
+// SetComplete marks a tutorial as complete or not complete for the learner
+func (s *MemoryStore) SetComplete(learner, tutorialID string, done bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setComplete(learner, tutorialID, done)
+	return nil
+}
+
+func (s *MemoryStore) setComplete(learner, tutorialID string, done bool) {
+	record := copyRecord(s.records[learner])
+	now := s.now().UTC()
+	if done {
+		if record.Completed == nil {
+			record.Completed = make(map[string]time.Time)
+		}
+		if _, ok := record.Completed[tutorialID]; !ok {
+			record.Completed[tutorialID] = now
+		}
+		record.LastTutorial = tutorialID
+	} else {
+		delete(record.Completed, tutorialID)
+	}
+	record.LastActive = now
+	s.records[learner] = record
+}
+
+// RecordQuiz saves a graded quiz attempt for the learner
+func (s *MemoryStore) RecordQuiz(learner, tutorialID string, score, total int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordQuiz(learner, tutorialID, score, total)
+	return nil
+}
+
+func (s *MemoryStore) recordQuiz(learner, tutorialID string, score, total int) {
+	record := copyRecord(s.records[learner])
+	if record.Quizzes == nil {
+		record.Quizzes = make(map[string]QuizScore)
+	}
+	now := s.now().UTC()
+	quiz := record.Quizzes[tutorialID]
+	// A changed quiz makes earlier best scores meaningless
+	if quiz.Total != total {
+		quiz = QuizScore{Total: total}
+	}
+	quiz.Last = score
+	if score > quiz.Best {
+		quiz.Best = score
+	}
+	quiz.Attempts++
+	quiz.Taken = now
+	record.Quizzes[tutorialID] = quiz
+	record.LastActive = now
+	s.records[learner] = record
+}
+
+// AddSubmission saves a graded exercise submission, dropping the oldest once
+// MaxSubmissions are kept
+func (s *MemoryStore) AddSubmission(learner, tutorialID string, submission Submission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addSubmission(learner, tutorialID, submission)
+	return nil
+}
+
+func (s *MemoryStore) addSubmission(learner, tutorialID string, submission Submission) {
+	record := copyRecord(s.records[learner])
+	if record.Submissions == nil {
+		record.Submissions = make(map[string][]Submission)
+	}
+	if submission.Submitted.IsZero() {
+		submission.Submitted = s.now().UTC()
+	}
+	record.Submissions[tutorialID] = keepLatest(append(record.Submissions[tutorialID], submission))
+	record.LastActive = submission.Submitted
+	s.records[learner] = record
+}
+
+// keepLatest sorts submissions oldest first and drops all but the latest MaxSubmissions
+func keepLatest(submissions []Submission) []Submission {
+	sort.SliceStable(submissions, func(i, j int) bool {
+		return submissions[i].Submitted.Before(submissions[j].Submitted)
+	})
+	if len(submissions) > MaxSubmissions {
+		submissions = submissions[len(submissions)-MaxSubmissions:]
+	}
+	return submissions
+}
+
+// Merge moves the progress recorded under from into to and removes from
+func (s *MemoryStore) Merge(from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.merge(from, to)
+	return nil
+}
+
+func (s *MemoryStore) merge(from, to string) {
+	src, ok := s.records[from]
+	if !ok || from == to {
+		return
+	}
+	dst := copyRecord(s.records[to])
+	for id, at := range src.Completed {
+		if dst.Completed == nil {
+			dst.Completed = make(map[string]time.Time)
+		}
+		// Keep the earliest completion time for tutorials finished in both
+		if existing, ok := dst.Completed[id]; !ok || at.Before(existing) {
+			dst.Completed[id] = at
+		}
+	}
+	for id, quiz := range src.Quizzes {
+		if dst.Quizzes == nil {
+			dst.Quizzes = make(map[string]QuizScore)
+		}
+		existing, ok := dst.Quizzes[id]
+		switch {
+		case !ok:
+			dst.Quizzes[id] = quiz
+		case existing.Total != quiz.Total:
+			// Scores from different versions of a quiz cannot be combined
+			if quiz.Taken.After(existing.Taken) {
+				dst.Quizzes[id] = quiz
+			}
+		default:
+			if quiz.Best > existing.Best {
+				existing.Best = quiz.Best
+			}
+			if quiz.Taken.After(existing.Taken) {
+				existing.Last, existing.Taken = quiz.Last, quiz.Taken
+			}
+			existing.Attempts += quiz.Attempts
+			dst.Quizzes[id] = existing
+		}
+	}
+	for id, submissions := range src.Submissions {
+		if dst.Submissions == nil {
+			dst.Submissions = make(map[string][]Submission)
+		}
+		dst.Submissions[id] = keepLatest(append(dst.Submissions[id], submissions...))
+	}
+	if src.LastActive.After(dst.LastActive) {
+		dst.LastTutorial = src.LastTutorial
+		dst.LastActive = src.LastActive
+	}
+	s.records[to] = dst
+	delete(s.records, from)
+}
+
+// copyRecord returns a record that does not share its maps with r
+func copyRecord(r Record) Record {
+	if r.Submissions != nil {
+		submissions := make(map[string][]Submission, len(r.Submissions))
+		for id, list := range r.Submissions {
+			submissions[id] = append([]Submission(nil), list...)
+		}
+		r.Submissions = submissions
+	}
+	if r.Quizzes != nil {
+		quizzes := make(map[string]QuizScore, len(r.Quizzes))
+		for id, quiz := range r.Quizzes {
+			quizzes[id] = quiz
+		}
+		r.Quizzes = quizzes
+	}
+	if r.Completed != nil {
+		completed := make(map[string]time.Time, len(r.Completed))
+		for id, at := range r.Completed {
+			completed[id] = at
+		}
+		r.Completed = completed
+	}
+	return r
+}
+
+// FileStore is a MemoryStore that saves every change to a JSON file
+type FileStore struct {
+	*MemoryStore
+	path string
+}
+
+// NewFileStore loads progress from path, starting empty if the file does not exist
+func NewFileStore(path string) (*FileStore, error) {
+	s := &FileStore{MemoryStore: NewMemoryStore(), path: path}
+	if _, err := jsonfile.Load(path, &s.records); err != nil {
+		return nil, err
+	}
+	if s.records == nil {
+		s.records = make(map[string]Record)
+	}
+	return s, nil
+}
+
+// SetComplete marks a tutorial as complete or not complete and saves the store
+func (s *FileStore) SetComplete(learner, tutorialID string, done bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setComplete(learner, tutorialID, done)
+	return jsonfile.Save(s.path, s.records)
+}
+
+// RecordQuiz saves a graded quiz attempt and saves the store
+func (s *FileStore) RecordQuiz(learner, tutorialID string, score, total int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recordQuiz(learner, tutorialID, score, total)
+	return jsonfile.Save(s.path, s.records)
+}
+
+// AddSubmission saves a graded exercise submission and saves the store
+func (s *FileStore) AddSubmission(learner, tutorialID string, submission Submission) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addSubmission(learner, tutorialID, submission)
+	return jsonfile.Save(s.path, s.records)
+}
+
+// Merge moves progress between learners and saves the store
+func (s *FileStore) Merge(from, to string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[from]; !ok {
+		return nil
+	}
+	s.merge(from, to)
+	return jsonfile.Save(s.path, s.records)
+}
@@ -0,0 +1,121 @@
+package progress
+
+import "golang-webserver-tutorial/content"
+
+// LevelSummary counts the completed tutorials in one level
+type LevelSummary struct {
+	Level content.Level
+	Done  int
+	Total int
+}
+
+// Percent returns the share of the level that is complete, from 0 to 100
+func (l LevelSummary) Percent() int {
+	if l.Total == 0 {
+		return 0
+	}
+	return l.Done * 100 / l.Total
+}
+
+// Summary is a learner's progress across every level, ready for templates
+type Summary struct {
+	Levels []LevelSummary
+	Done   int
+	Total  int
+
+	// Next is the tutorial to continue with, or nil when the learner has not
+	// started or has finished everything
+	Next      *content.Tutorial
+	NextLevel content.Level
+
+	completed map[string]bool
+	quizzes   map[string]QuizScore
+}
+
+// Summarize works out a learner's progress through levels from their record
+func Summarize(levels []content.Level, record Record) Summary {
+	s := Summary{completed: make(map[string]bool), quizzes: record.Quizzes}
+
+	// Collect tutorials in reading order to find where to continue
+	var order []content.Tutorial
+	var orderLevels []content.Level
+	for _, level := range levels {
+		ls := LevelSummary{Level: level, Total: len(level.Tutorials)}
+		for _, tutorial := range level.Tutorials {
+			if _, ok := record.Completed[tutorial.ID]; ok {
+				s.completed[tutorial.ID] = true
+				ls.Done++
+			}
+			order = append(order, tutorial)
+			orderLevels = append(orderLevels, level)
+		}
+		s.Levels = append(s.Levels, ls)
+		s.Done += ls.Done
+		s.Total += ls.Total
+	}
+
+	if s.Done == 0 || s.Done == s.Total {
+		return s
+	}
+
+	// Continue with the first unfinished tutorial after the last one completed,
+	// wrapping around to pick up anything skipped earlier
+	start := 0
+	for i, tutorial := range order {
+		if tutorial.ID == record.LastTutorial {
+			start = i + 1
+		}
+	}
+	for n := 0; n < len(order); n++ {
+		i := (start + n) % len(order)
+		if !s.completed[order[i].ID] {
+			s.Next = &order[i]
+			s.NextLevel = orderLevels[i]
+			break
+		}
+	}
+	return s
+}
+
+// Started reports whether the learner has completed any tutorial
+func (s Summary) Started() bool {
+	return s.Done > 0
+}
+
+// Percent returns the share of all tutorials that is complete, from 0 to 100
+func (s Summary) Percent() int {
+	return LevelSummary{Done: s.Done, Total: s.Total}.Percent()
+}
+
+// Completed reports whether the tutorial with the given ID is complete
+func (s Summary) Completed(id string) bool {
+	return s.completed[id]
+}
+
+// Quiz returns the learner's score on the tutorial's quiz, or nil if they
+// have not taken it
+func (s Summary) Quiz(id string) *QuizScore {
+	quiz, ok := s.quizzes[id]
+	if !ok {
+		return nil
+	}
+	return &quiz
+}
+
+// Level returns the summary for the level with the given ID
+func (s Summary) Level(id string) LevelSummary {
+	for _, level := range s.Levels {
+		if level.Level.ID == id {
+			return level
+		}
+	}
+	return LevelSummary{}
+}
+
+// NextURL links to the tutorial to continue with
+func (s Summary) NextURL() string {
+	if s.Next == nil {
+		return ""
+	}
+	return s.NextLevel.Path + "#" + s.Next.ID
+}
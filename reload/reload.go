@@ -0,0 +1,179 @@
+// Package reload notices when files on disk change, by polling so it needs
+// nothing beyond the standard library, and passes reload events on to
+// subscribers such as open browser tabs.
+package reload
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// fileState is what is compared between scans to spot a change
+type fileState struct {
+	size    int64
+	modTime time.Time
+}
+
+// Watcher polls files and directories for changes. It is not safe for
+// concurrent use; Run scans from a single goroutine.
+type Watcher struct {
+	roots []string
+
+	// Skip leaves out paths for which it returns true, such as generated
+	// files. Skipping a directory skips everything in it.
+	Skip func(path string) bool
+
+	files map[string]fileState
+}
+
+// NewWatcher watches the given files and directories
+func NewWatcher(roots ...string) *Watcher {
+	return &Watcher{roots: roots}
+}
+
+// snapshot records the state of every file under the roots. Roots that do
+// not exist are skipped, so they are reported once they are created.
+func (w *Watcher) snapshot() (map[string]fileState, error) {
+	files := make(map[string]fileState)
+	for _, root := range w.roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if w.Skip != nil && w.Skip(path) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				return nil
+			}
+			info, err := d.Info()
+			if os.IsNotExist(err) {
+				// Removed while walking; the next scan reports it
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			files[path] = fileState{size: info.Size(), modTime: info.ModTime()}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// Scan returns the sorted paths of files created, changed or removed since
+// the last scan. The first scan records where things stand and reports
+// nothing.
+func (w *Watcher) Scan() ([]string, error) {
+	files, err := w.snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if w.files == nil {
+		w.files = files
+		return nil, nil
+	}
+	var changed []string
+	for path, state := range files {
+		if old, ok := w.files[path]; !ok || old != state {
+			changed = append(changed, path)
+		}
+	}
+	for path := range w.files {
+		if _, ok := files[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	w.files = files
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// Run scans now and then every interval until ctx is done, calling changed
+// with the paths that changed. Scan errors are passed to changed with no paths.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, changed func([]string, error)) {
+	if _, err := w.Scan(); err != nil {
+		changed(nil, err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			paths, err := w.Scan()
+			if len(paths) > 0 || err != nil {
+				changed(paths, err)
+			}
+		}
+	}
+}
+
+// Event is sent to subscribers after files change. Error is set when the
+// change could not be applied and the last good version is still served.
+type Event struct {
+	Paths []string `json:"paths"`
+	Error string   `json:"error,omitempty"`
+}
+
+// Hub passes events on to every subscriber. It is safe for concurrent use.
+type Hub struct {
+	mu   sync.Mutex
+	subs map[chan Event]bool
+}
+
+// NewHub creates a hub with no subscribers
+func NewHub() *Hub {
+	return &Hub{subs: make(map[chan Event]bool)}
+}
+
+// Subscribe returns a channel receiving events until Unsubscribe is called
+func (h *Hub) Subscribe() chan Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	ch := make(chan Event, 4)
+	h.subs[ch] = true
+	return ch
+}
+
+// Unsubscribe stops sending events to ch
+func (h *Hub) Unsubscribe(ch chan Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, ch)
+}
+
+// Publish sends e to every subscriber. Subscribers that have fallen behind
+// miss the event rather than holding up the others.
+func (h *Hub) Publish(e Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// Subscribers returns how many subscribers there are
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
@@ -0,0 +1,74 @@
+package reload
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, text string) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("templates/home.html", "home")
+	write("static/css/style.css", "body {}")
+	write("static/examples/a.go", "package main")
+
+	w := NewWatcher(filepath.Join(dir, "templates"), filepath.Join(dir, "static"), filepath.Join(dir, "missing"))
+	w.Skip = func(path string) bool { return strings.HasSuffix(path, "examples") }
+	if changed, err := w.Scan(); err != nil || len(changed) != 0 {
+		t.Fatalf("first scan = %v, %v", changed, err)
+	}
+
+	write("templates/home.html", "home, longer")
+	write("templates/new.html", "new")
+	write("static/examples/a.go", "package main // skipped")
+	os.Remove(filepath.Join(dir, "static/css/style.css"))
+	write("missing/now.txt", "here")
+	want := []string{
+		filepath.Join(dir, "missing/now.txt"),
+		filepath.Join(dir, "static/css/style.css"),
+		filepath.Join(dir, "templates/home.html"),
+		filepath.Join(dir, "templates/new.html"),
+	}
+	if changed, err := w.Scan(); err != nil || !reflect.DeepEqual(changed, want) {
+		t.Errorf("Scan = %v, %v, want %v", changed, err, want)
+	}
+
+	// A change of time alone counts, as editors may rewrite the same bytes
+	later := time.Now().Add(time.Minute)
+	os.Chtimes(filepath.Join(dir, "templates/new.html"), later, later)
+	if changed, _ := w.Scan(); len(changed) != 1 {
+		t.Errorf("touched file: %v", changed)
+	}
+}
+
+func TestHub(t *testing.T) {
+	h := NewHub()
+	a, b := h.Subscribe(), h.Subscribe()
+	h.Publish(Event{Paths: []string{"templates/home.html"}})
+	if e := <-a; e.Paths[0] != "templates/home.html" {
+		t.Errorf("a got %+v", e)
+	}
+	<-b
+
+	// A subscriber that stops reading does not block the others
+	for i := 0; i < 10; i++ {
+		h.Publish(Event{})
+	}
+	h.Unsubscribe(a)
+	if h.Subscribers() != 1 {
+		t.Errorf("Subscribers = %d", h.Subscribers())
+	}
+}
@@ -0,0 +1,6 @@
+//go:build linux && (mips || mipsle || mips64 || mips64le)
+
+package sandbox
+
+// rlimitNproc is RLIMIT_NPROC, which MIPS numbers differently
+const rlimitNproc = 8
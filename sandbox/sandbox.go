@@ -0,0 +1,451 @@
+// Package sandbox compiles and runs untrusted Go code with the local toolchain
+// in throwaway modules, under CPU, memory, process, time and output limits and
+// without network access. Each command runs in its own process namespace, so
+// everything it starts is stopped with it, and sees a private copy of the
+// build cache, so it cannot change how other commands build.
+//
+// Commands see a minimal root file system holding the system directories,
+// GOROOT, their module and the build cache. Only the module and a private
+// /tmp are writable, so submitted code cannot read or change the server's
+// files even though it runs with the server user's permissions.
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnsupported is returned on platforms where commands cannot be isolated
+var ErrUnsupported = errors.New("sandbox: not supported on this platform")
+
+// ErrBusy is returned when every sandbox slot is in use
+var ErrBusy = errors.New("sandbox: too many programs running, try again shortly")
+
+// Limits bound the resources of one sandboxed command
+type Limits struct {
+	// Timeout is the wall clock limit for the whole command
+	Timeout time.Duration
+
+	// CPU is the processor time limit for each process
+	CPU time.Duration
+
+	// Memory is the address space limit for each process in bytes. Go
+	// programs reserve a lot of address space up front, so keep this at
+	// 1 GiB or more.
+	Memory int64
+
+	// Output is the number of bytes of combined stdout and stderr kept
+	Output int
+
+	// Processes limits the processes and threads the command may run at
+	// once, which stops fork bombs. The kernel does not apply it when the
+	// server runs as root.
+	Processes int
+}
+
+// DefaultLimits suit compiling and running small tutorial programs
+var DefaultLimits = Limits{
+	Timeout: 30 * time.Second,
+	CPU:     20 * time.Second,
+	Memory:  2 << 30,
+	Output:  64 << 10,
+
+	// The go command and compiler each run a few dozen threads
+	Processes: 256,
+}
+
+// withDefaults fills zero limits from DefaultLimits
+func (l Limits) withDefaults() Limits {
+	if l.Timeout <= 0 {
+		l.Timeout = DefaultLimits.Timeout
+	}
+	if l.CPU <= 0 {
+		l.CPU = DefaultLimits.CPU
+	}
+	if l.Memory <= 0 {
+		l.Memory = DefaultLimits.Memory
+	}
+	if l.Output <= 0 {
+		l.Output = DefaultLimits.Output
+	}
+	if l.Processes <= 0 {
+		l.Processes = DefaultLimits.Processes
+	}
+	return l
+}
+
+// Sandbox runs go commands in isolated temporary modules
+type Sandbox struct {
+	// GoBin is the go command to use; "go" from PATH when empty
+	GoBin string
+
+	// CacheDir is the build cache shared between runs, which keeps repeated
+	// builds of the standard library fast. Only Warm writes to it; other
+	// commands see it through a private overlay that is thrown away with them.
+	CacheDir string
+
+	slots chan struct{}
+
+	gorootOnce sync.Once
+	goroot     string
+}
+
+// DefaultCacheDir is a build cache location that survives server restarts,
+// in the user's cache directory rather than a shared temporary one
+func DefaultCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, "go-tutorial-sandbox")
+}
+
+// New creates a sandbox that runs at most maxConcurrent commands at once
+func New(cacheDir string, maxConcurrent int) *Sandbox {
+	return &Sandbox{CacheDir: cacheDir, slots: make(chan struct{}, maxConcurrent)}
+}
+
+// WarmImports are the packages tutorial programs and tests usually import
+var WarmImports = []string{
+	"encoding/json",
+	"fmt",
+	"html/template",
+	"log",
+	"net/http",
+	"net/http/httptest",
+	"strings",
+	"testing",
+	"time",
+}
+
+// Warm compiles packages into the build cache so the first learner's program
+// does not spend its time limit building the standard library. Compiling them
+// from scratch can take a minute on a small machine.
+func (s *Sandbox) Warm(ctx context.Context, imports []string) error {
+	var src strings.Builder
+	src.WriteString("package warm\n\nimport (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&src, "\t_ %q\n", path)
+	}
+	src.WriteString(")\n")
+
+	m, err := s.NewModule("warm", map[string]string{"warm_test.go": src.String()})
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	// This is the only command that may write to the shared build cache
+	path, err := exec.LookPath(s.goBin())
+	if err != nil {
+		return err
+	}
+	limits := Limits{Timeout: 10 * time.Minute, CPU: 10 * time.Minute}
+	var out strings.Builder
+	result, err := s.exec(ctx, m, limits, &out, &out, true, path, "test", "-count=1", "-run=^$", ".")
+	if err != nil {
+		return err
+	}
+	if result.ExitCode != 0 || result.TimedOut {
+		return fmt.Errorf("sandbox: warming the build cache failed: %s", out.String())
+	}
+	return nil
+}
+
+// Result describes how a sandboxed command finished
+type Result struct {
+	ExitCode  int
+	TimedOut  bool
+	Truncated bool
+	Duration  time.Duration
+}
+
+// Module is a temporary Go module that sandboxed commands run in
+type Module struct {
+	Dir string
+}
+
+// NewModule writes files, keyed by slash-separated relative path, into a new
+// temporary module named module. Only .go files are accepted.
+func (s *Sandbox) NewModule(module string, files map[string]string) (*Module, error) {
+	dir, err := os.MkdirTemp("", "sandbox-")
+	if err != nil {
+		return nil, err
+	}
+	m := &Module{Dir: dir}
+
+	// The go command ignores modules at the root of TMPDIR, so give
+	// commands a temporary directory of their own inside the module
+	if err := os.Mkdir(filepath.Join(dir, ".tmp"), 0755); err != nil {
+		m.Close()
+		return nil, err
+	}
+
+	goMod := fmt.Sprintf("module %s\n\ngo 1.19\n", module)
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		m.Close()
+		return nil, err
+	}
+	for name, source := range files {
+		if !validFilename(name) {
+			m.Close()
+			return nil, fmt.Errorf("sandbox: invalid file name %q", name)
+		}
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			m.Close()
+			return nil, err
+		}
+		if err := os.WriteFile(path, []byte(source), 0644); err != nil {
+			m.Close()
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// validFilename accepts relative .go paths that stay inside the module
+func validFilename(name string) bool {
+	if !strings.HasSuffix(name, ".go") || strings.HasPrefix(name, "/") || strings.Contains(name, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == "" || part == "." || part == ".." || strings.HasPrefix(part, ".") {
+			return false
+		}
+	}
+	return true
+}
+
+// Close removes the module directory
+func (m *Module) Close() error {
+	return os.RemoveAll(m.Dir)
+}
+
+// Go runs the go command with args in the module
+func (s *Sandbox) Go(ctx context.Context, m *Module, limits Limits, stdout, stderr io.Writer, args ...string) (Result, error) {
+	path, err := exec.LookPath(s.goBin())
+	if err != nil {
+		return Result{}, err
+	}
+	return s.exec(ctx, m, limits, stdout, stderr, false, path, args...)
+}
+
+// Exec runs a program inside the module directory. Relative program paths
+// are resolved against the module.
+func (s *Sandbox) Exec(ctx context.Context, m *Module, limits Limits, stdout, stderr io.Writer, program string, args ...string) (Result, error) {
+	return s.exec(ctx, m, limits, stdout, stderr, false, program, args...)
+}
+
+// spec is a command to run in isolation, passed to the sandbox's init
+// process inside the namespaces
+type spec struct {
+	Dir     string
+	Program string
+	Args    []string
+	Env     []string
+	Limits  Limits
+
+	// ReadOnly are host directories the command can read besides the
+	// system ones, such as GOROOT
+	ReadOnly []string
+
+	// CacheDir is the shared build cache. Changes to it are private to the
+	// command unless WriteCache is set.
+	CacheDir   string
+	WriteCache bool
+}
+
+// exec runs a program, letting it change the shared build cache only if
+// writeCache is set
+func (s *Sandbox) exec(ctx context.Context, m *Module, limits Limits, stdout, stderr io.Writer, writeCache bool, program string, args ...string) (Result, error) {
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	default:
+		return Result{}, ErrBusy
+	}
+
+	limits = limits.withDefaults()
+	if !filepath.IsAbs(program) {
+		program = filepath.Join(m.Dir, program)
+	}
+
+	out := &limitWriter{limit: limits.Output}
+	out.stdout, out.stderr = stdout, stderr
+
+	if s.CacheDir != "" {
+		if err := os.MkdirAll(s.CacheDir, 0700); err != nil {
+			return Result{}, err
+		}
+	}
+	cmd, setup, err := command(spec{
+		Dir:        m.Dir,
+		Program:    program,
+		Args:       args,
+		Env:        s.env(m),
+		Limits:     limits,
+		ReadOnly:   s.readOnly(m, program),
+		CacheDir:   s.CacheDir,
+		WriteCache: writeCache,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	defer setup.Close()
+	cmd.Stdout = out.writer(false)
+	cmd.Stderr = out.writer(true)
+
+	start := time.Now()
+	err = cmd.Start()
+	for _, f := range cmd.ExtraFiles {
+		f.Close()
+	}
+	if err != nil {
+		return Result{}, err
+	}
+
+	// Kill the command and everything it started when the time is up or the
+	// caller gives up
+	ctx, cancel := context.WithTimeout(ctx, limits.Timeout)
+	defer cancel()
+	done := make(chan struct{})
+	timedOut := make(chan bool, 1)
+	go func() {
+		select {
+		case <-ctx.Done():
+			kill(cmd)
+			timedOut <- true
+		case <-done:
+			timedOut <- false
+		}
+	}()
+
+	err = cmd.Wait()
+	close(done)
+	result := Result{
+		Duration:  time.Since(start),
+		TimedOut:  <-timedOut,
+		Truncated: out.truncated(),
+	}
+
+	// The init process reports problems setting up the namespaces before
+	// the program starts
+	if problem, _ := io.ReadAll(setup); len(problem) > 0 {
+		return result, fmt.Errorf("sandbox: %s", problem)
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	default:
+		return result, err
+	}
+	return result, nil
+}
+
+// env is the environment for sandboxed commands: offline, no cgo, and a
+// private home and GOPATH inside the module
+func (s *Sandbox) env(m *Module) []string {
+	return []string{
+		"PATH=/usr/local/bin:/usr/bin:/bin",
+		"HOME=" + m.Dir,
+		"TMPDIR=" + filepath.Join(m.Dir, ".tmp"),
+		"GOPATH=" + filepath.Join(m.Dir, ".gopath"),
+		"GOCACHE=" + s.CacheDir,
+		"GOROOT=" + s.gorootDir(),
+		"GOPROXY=off",
+		"GOFLAGS=-mod=mod",
+		"GOTOOLCHAIN=local",
+		"GOTELEMETRY=off",
+		"GO111MODULE=on",
+		"CGO_ENABLED=0",
+	}
+}
+
+// readOnly lists the directories a command needs outside the module and
+// the system directories: GOROOT and the one holding the program
+func (s *Sandbox) readOnly(m *Module, program string) []string {
+	var dirs []string
+	if goroot, err := filepath.EvalSymlinks(s.gorootDir()); err == nil {
+		dirs = append(dirs, goroot)
+	}
+	if path, err := filepath.EvalSymlinks(program); err == nil && !strings.HasPrefix(path, m.Dir+string(filepath.Separator)) {
+		dirs = append(dirs, filepath.Dir(path))
+	}
+	return dirs
+}
+
+func (s *Sandbox) goBin() string {
+	if s.GoBin == "" {
+		return "go"
+	}
+	return s.GoBin
+}
+
+// gorootDir finds the toolchain's GOROOT so sandboxed commands can use it
+// without inheriting the server's environment
+func (s *Sandbox) gorootDir() string {
+	s.gorootOnce.Do(func() {
+		out, err := exec.Command(s.goBin(), "env", "GOROOT").Output()
+		if err == nil {
+			s.goroot = strings.TrimSpace(string(out))
+		}
+	})
+	return s.goroot
+}
+
+// limitWriter caps the combined output of a command, dropping anything past
+// the limit
+type limitWriter struct {
+	mu             sync.Mutex
+	limit          int
+	written        int
+	dropped        bool
+	stdout, stderr io.Writer
+}
+
+func (l *limitWriter) writer(stderr bool) io.Writer {
+	return writerFunc(func(p []byte) (int, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		n := len(p)
+		if room := l.limit - l.written; len(p) > room {
+			p = p[:room]
+			l.dropped = true
+		}
+		l.written += len(p)
+		if len(p) > 0 {
+			w := l.stdout
+			if stderr {
+				w = l.stderr
+			}
+			if w != nil {
+				w.Write(p)
+			}
+		}
+		// Report the full length so the program keeps running normally
+		return n, nil
+	})
+}
+
+func (l *limitWriter) truncated() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.dropped
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) { return f(p) }
@@ -0,0 +1,302 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// initEnv passes the spec to the sandbox's init process. The init process is
+// this program started again, so it runs whatever binary uses the package.
+const initEnv = "GO_TUTORIAL_SANDBOX_SPEC"
+
+// nobody is the user that programs run as inside the sandbox
+const nobody = 65534
+
+func init() {
+	if encoded, ok := os.LookupEnv(initEnv); ok {
+		os.Exit(runInit(encoded))
+	}
+}
+
+// command starts this program again as the init process of new user, mount,
+// PID and network namespaces, which then runs the spec. The namespaces have
+// no network access, and killing init stops every process the command
+// started, even ones that leave its process group. The returned file reports
+// why setting up the namespaces failed, if it did.
+func command(sp spec) (*exec.Cmd, *os.File, error) {
+	encoded, err := json.Marshal(sp)
+	if err != nil {
+		return nil, nil, err
+	}
+	setup, report, err := os.Pipe()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cmd := exec.Command("/proc/self/exe")
+	cmd.Args = []string{"sandbox-init"}
+	cmd.Env = []string{initEnv + "=" + string(encoded)}
+	cmd.Dir = sp.Dir
+	cmd.ExtraFiles = []*os.File{report}
+
+	uid, gid := os.Getuid(), os.Getgid()
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Setpgid:   true,
+		Pdeathsig: syscall.SIGKILL,
+		Cloneflags: syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID |
+			syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS,
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: uid, Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: 0, HostID: gid, Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+	}
+	return cmd, setup, nil
+}
+
+// kill stops the command and every process it started
+func kill(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// runInit runs inside the namespaces as process 1. It sets them up, runs the
+// program and exits with its status, at which point the kernel kills any
+// processes the program left behind.
+func runInit(encoded string) int {
+	report := os.NewFile(3, "setup")
+	syscall.CloseOnExec(3)
+
+	var sp spec
+	err := json.Unmarshal([]byte(encoded), &sp)
+	if err == nil {
+		err = sp.isolate()
+	}
+	var cmd *exec.Cmd
+	if err == nil {
+		cmd, err = sp.start()
+	}
+	if err != nil {
+		fmt.Fprint(report, err)
+		return 1
+	}
+	report.Close()
+
+	// Reap every process that ends up here until the program exits
+	for {
+		var status syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &status, 0, nil)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err != nil {
+			return 1
+		}
+		if pid != cmd.Process.Pid {
+			continue
+		}
+		if status.Signaled() {
+			return 128 + int(status.Signal())
+		}
+		return status.ExitStatus()
+	}
+}
+
+// systemPaths are the host directories every command can read, which hold the
+// shell, standard tools and shared libraries. /etc is left out.
+var systemPaths = []string{"/bin", "/lib", "/lib32", "/lib64", "/sbin", "/usr"}
+
+// devices are the device files every command can use
+var devices = []string{"/dev/null", "/dev/zero", "/dev/random", "/dev/urandom"}
+
+// isolate moves the namespace into a new root that holds only the system
+// directories, the spec's read-only paths, the module, the build cache, a
+// private /tmp and a /proc that only shows the sandbox's processes. Only the
+// module, /tmp and the build cache are writable, and changes to the cache go
+// to a private overlay unless the spec may write to it.
+func (sp spec) isolate() error {
+	if err := syscall.Mount("", "/", "", syscall.MS_REC|syscall.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("making mounts private: %v", err)
+	}
+	if strings.ContainsAny(sp.CacheDir+sp.Dir, ",:") {
+		return fmt.Errorf("cache and module paths cannot contain commas or colons")
+	}
+
+	// The new root and the cache overlay's changes live on a small private
+	// file system that disappears with the namespace
+	scratch, err := os.MkdirTemp(sp.Dir, ".sandbox")
+	if err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", scratch, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "size=256m,mode=0700"); err != nil {
+		return fmt.Errorf("mounting the sandbox root: %v", err)
+	}
+	root := filepath.Join(scratch, "root")
+	if err := os.Mkdir(root, 0755); err != nil {
+		return err
+	}
+	// pivot_root needs the new root to be a mount point
+	if err := syscall.Mount(root, root, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("mounting the sandbox root: %v", err)
+	}
+
+	// /tmp comes first so the module, which is usually inside it, is
+	// mounted over it rather than hidden by it
+	tmp := filepath.Join(root, "tmp")
+	if err := os.Mkdir(tmp, 0755); err != nil {
+		return err
+	}
+	if err := syscall.Mount("tmpfs", tmp, "tmpfs", syscall.MS_NOSUID|syscall.MS_NODEV, "size=64m,mode=1777"); err != nil {
+		return fmt.Errorf("mounting /tmp: %v", err)
+	}
+	for _, path := range append(append(systemPaths, sp.ReadOnly...), devices...) {
+		if err := mountInto(root, path, false); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := mountInto(root, sp.Dir, true); err != nil {
+		return err
+	}
+	proc := filepath.Join(root, "proc")
+	if err := os.Mkdir(proc, 0755); err != nil {
+		return err
+	}
+	if err := syscall.Mount("proc", proc, "proc", syscall.MS_NOSUID|syscall.MS_NODEV|syscall.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("mounting /proc: %v", err)
+	}
+
+	switch {
+	case sp.CacheDir == "":
+	case sp.WriteCache:
+		if err := mountInto(root, sp.CacheDir, true); err != nil {
+			return err
+		}
+	default:
+		upper, work := filepath.Join(scratch, "upper"), filepath.Join(scratch, "work")
+		for _, dir := range []string{upper, work} {
+			if err := os.Mkdir(dir, 0700); err != nil {
+				return err
+			}
+		}
+		target := filepath.Join(root, sp.CacheDir)
+		if err := os.MkdirAll(target, 0700); err != nil {
+			return err
+		}
+		options := fmt.Sprintf("lowerdir=%s,upperdir=%s,workdir=%s", sp.CacheDir, upper, work)
+		if err := syscall.Mount("overlay", target, "overlay", syscall.MS_NOSUID|syscall.MS_NODEV, options); err != nil {
+			return fmt.Errorf("mounting the build cache overlay: %v", err)
+		}
+	}
+
+	// Switch to the new root and drop the host's
+	if err := os.Chdir(root); err != nil {
+		return err
+	}
+	if err := syscall.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("changing root: %v", err)
+	}
+	if err := syscall.Unmount(".", syscall.MNT_DETACH); err != nil {
+		return fmt.Errorf("detaching the host root: %v", err)
+	}
+	if err := os.Chdir("/"); err != nil {
+		return err
+	}
+	flags := uintptr(syscall.MS_BIND | syscall.MS_REMOUNT | syscall.MS_RDONLY | syscall.MS_NOSUID | syscall.MS_NODEV)
+	if err := syscall.Mount("", "/", "", flags, ""); err != nil {
+		return fmt.Errorf("making the sandbox root read-only: %v", err)
+	}
+	return nil
+}
+
+// mountInto makes the host path visible at the same path under root,
+// read-only unless writable is set. Symbolic links are copied rather than
+// followed.
+func mountInto(root, path string, writable bool) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(root, path)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, target)
+	case info.IsDir():
+		err = os.MkdirAll(target, 0755)
+	default:
+		err = os.WriteFile(target, nil, 0644)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := syscall.Mount(path, target, "", syscall.MS_BIND, ""); err != nil {
+		return fmt.Errorf("mounting %s: %v", path, err)
+	}
+	if writable {
+		return nil
+	}
+
+	// A remount has to keep the flags the host mount was locked with
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(target, &fs); err != nil {
+		return err
+	}
+	kept := uintptr(fs.Flags) & (syscall.MS_NOSUID | syscall.MS_NODEV | syscall.MS_NOEXEC |
+		syscall.MS_NOATIME | syscall.MS_NODIRATIME | syscall.MS_RELATIME)
+	if err := syscall.Mount("", target, "", kept|syscall.MS_BIND|syscall.MS_REMOUNT|syscall.MS_RDONLY, ""); err != nil {
+		return fmt.Errorf("making %s read-only: %v", path, err)
+	}
+	return nil
+}
+
+// start runs the program as nobody in a user namespace of its own, so it has
+// no privileges over the mounts set up for it, under the spec's limits
+func (sp spec) start() (*exec.Cmd, error) {
+	limits := sp.Limits
+	for _, limit := range []struct {
+		resource int
+		value    uint64
+	}{
+		{syscall.RLIMIT_CPU, uint64(limits.CPU.Seconds() + 0.5)},
+		{syscall.RLIMIT_AS, uint64(limits.Memory)},
+		{rlimitNproc, uint64(limits.Processes)},
+	} {
+		// init is not limited by them before it starts the program, which
+		// inherits them
+		if err := syscall.Setrlimit(limit.resource, &syscall.Rlimit{Cur: limit.value, Max: limit.value}); err != nil {
+			return nil, fmt.Errorf("setting resource limits: %v", err)
+		}
+	}
+
+	cmd := exec.Command(sp.Program, sp.Args...)
+	cmd.Dir = sp.Dir
+	cmd.Env = sp.Env
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		Cloneflags: syscall.CLONE_NEWUSER,
+		UidMappings: []syscall.SysProcIDMap{
+			{ContainerID: nobody, HostID: 0, Size: 1},
+		},
+		GidMappings: []syscall.SysProcIDMap{
+			{ContainerID: nobody, HostID: 0, Size: 1},
+		},
+		GidMappingsEnableSetgroups: false,
+	}
+	return cmd, cmd.Start()
+}
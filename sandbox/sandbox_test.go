@@ -0,0 +1,237 @@
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestSandbox skips tests that need the toolchain and namespaces
+func newTestSandbox(t *testing.T) *Sandbox {
+	if testing.Short() {
+		t.Skip("compiles Go code")
+	}
+	if runtime.GOOS != "linux" {
+		t.Skip("sandbox requires Linux")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go command not found")
+	}
+	s := New(DefaultCacheDir(), 2)
+	if err := s.Warm(context.Background(), []string{"fmt", "net", "time"}); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestValidFilename(t *testing.T) {
+	for name, want := range map[string]bool{
+		"main.go":        true,
+		"pkg/util.go":    true,
+		"../escape.go":   false,
+		"/etc/passwd.go": false,
+		"go.mod":         false,
+		".hidden/x.go":   false,
+		"a//b.go":        false,
+	} {
+		if got := validFilename(name); got != want {
+			t.Errorf("validFilename(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func run(t *testing.T, s *Sandbox, limits Limits, source string) (Result, string) {
+	t.Helper()
+	m, err := s.NewModule("play", map[string]string{"main.go": source})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	var out bytes.Buffer
+	result, err := s.Go(context.Background(), m, limits, &out, &out, "run", ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result, out.String()
+}
+
+func TestRun(t *testing.T) {
+	s := newTestSandbox(t)
+
+	result, out := run(t, s, DefaultLimits, `package main
+
+import "fmt"
+
+func main() { fmt.Println("hello from the sandbox") }
+`)
+	if result.ExitCode != 0 || !strings.Contains(out, "hello from the sandbox") {
+		t.Errorf("exit %d, output %q", result.ExitCode, out)
+	}
+
+	// The network is not reachable
+	result, out = run(t, s, DefaultLimits, `package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+func main() {
+	_, err := net.DialTimeout("tcp", "1.1.1.1:80", 2*time.Second)
+	fmt.Println("dial error:", err != nil)
+}
+`)
+	if !strings.Contains(out, "dial error: true") {
+		t.Errorf("network was reachable: %q", out)
+	}
+
+	// Programs that run too long are killed
+	limits := DefaultLimits
+	limits.Timeout = 3 * time.Second
+	result, _ = run(t, s, limits, `package main
+
+import "time"
+
+func main() { time.Sleep(time.Hour) }
+`)
+	if !result.TimedOut {
+		t.Error("endless program was not stopped")
+	}
+
+	// Output past the limit is dropped
+	limits = DefaultLimits
+	limits.Output = 100
+	result, out = run(t, s, limits, `package main
+
+import "fmt"
+
+func main() {
+	for i := 0; i < 1000; i++ {
+		fmt.Println("spam")
+	}
+}
+`)
+	if !result.Truncated || len(out) != 100 {
+		t.Errorf("truncated = %v, %d bytes kept", result.Truncated, len(out))
+	}
+}
+
+func TestIsolation(t *testing.T) {
+	s := newTestSandbox(t)
+
+	// Changes to the build cache stay with the command that made them
+	result, out := run(t, s, DefaultLimits, `package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	err := os.WriteFile(filepath.Join(os.Getenv("GOCACHE"), "poisoned"), []byte("x"), 0644)
+	fmt.Println("write error:", err)
+}
+`)
+	if result.ExitCode != 0 || !strings.Contains(out, "write error: <nil>") {
+		t.Errorf("exit %d, output %q", result.ExitCode, out)
+	}
+	if _, err := os.Stat(filepath.Join(s.CacheDir, "poisoned")); !os.IsNotExist(err) {
+		t.Errorf("shared build cache was changed: %v", err)
+	}
+
+	// Fork bombs run out of processes, unless the kernel exempts root
+	limits := DefaultLimits
+	limits.Processes = 30
+	_, out = run(t, s, limits, `package main
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+func main() {
+	started := 0
+	for ; started < 100; started++ {
+		if err := exec.Command("/bin/sleep", "30").Start(); err != nil {
+			break
+		}
+	}
+	fmt.Println("started", started)
+}
+`)
+	if !strings.Contains(out, "started ") || strings.Contains(out, "started 100") && os.Getuid() != 0 {
+		t.Errorf("process limit not applied: %q", out)
+	}
+
+	// Processes that leave the program's session are stopped with it
+	m, err := s.NewModule("play", map[string]string{"main.go": `package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+func main() {
+	cmd := exec.Command("/bin/sh", "-c", "sleep 2; echo escaped > escaped.txt")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	cmd.Start()
+}
+`})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+	if result, err := s.Go(context.Background(), m, DefaultLimits, nil, nil, "run", "."); err != nil || result.ExitCode != 0 {
+		t.Fatalf("exit %d, %v", result.ExitCode, err)
+	}
+	time.Sleep(3 * time.Second)
+	if _, err := os.Stat(filepath.Join(m.Dir, "escaped.txt")); !os.IsNotExist(err) {
+		t.Error("a process outlived the command")
+	}
+}
+
+func TestFileSystem(t *testing.T) {
+	s := newTestSandbox(t)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(wd, "escaped.txt")
+	defer os.Remove(outside)
+
+	// The server's files can be neither changed nor read, while the module
+	// and /tmp stay writable
+	result, out := run(t, s, DefaultLimits, fmt.Sprintf(`package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	fmt.Println("outside:", os.WriteFile(%q, []byte("x"), 0644) != nil)
+	_, err := os.ReadFile(%q)
+	fmt.Println("read:", err != nil)
+	fmt.Println("goroot:", os.WriteFile(os.Getenv("GOROOT")+"/escaped.txt", []byte("x"), 0644) != nil)
+	fmt.Println("module:", os.WriteFile("written.txt", []byte("x"), 0644))
+	fmt.Println("tmp:", os.WriteFile("/tmp/written.txt", []byte("x"), 0644))
+}
+`, outside, filepath.Join(wd, "sandbox.go")))
+	want := "outside: true\nread: true\ngoroot: true\nmodule: <nil>\ntmp: <nil>\n"
+	if result.ExitCode != 0 || out != want {
+		t.Errorf("exit %d, output %q, want %q", result.ExitCode, out, want)
+	}
+	if _, err := os.Stat(outside); !os.IsNotExist(err) {
+		t.Errorf("file written outside the module: %v", err)
+	}
+}
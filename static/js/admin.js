@@ -0,0 +1,27 @@
+// Render a live preview of the admin editor form while the author types
+document.addEventListener('DOMContentLoaded', function() {
+    const form = document.querySelector('.admin-form[data-preview-url]');
+    const frame = document.querySelector('.admin-preview iframe');
+    if (!form || !frame) {
+        return;
+    }
+    
+    let timer;
+    const preview = () => {
+        fetch(form.dataset.previewUrl, {
+            method: 'POST',
+            body: new URLSearchParams(new FormData(form)),
+        })
+            .then(response => response.text())
+            .then(html => {
+                frame.srcdoc = html;
+            })
+            .catch(() => {});
+    };
+    
+    form.addEventListener('input', () => {
+        clearTimeout(timer);
+        timer = setTimeout(preview, 500);
+    });
+    preview();
+});
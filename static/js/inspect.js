@@ -0,0 +1,66 @@
+// Build requests on the inspector page, show the matching curl command and
+// send them with fetch
+document.addEventListener('DOMContentLoaded', function() {
+    const form = document.querySelector('.request-builder');
+    if (!form) {
+        return;
+    }
+    const curl = form.querySelector('.builder-curl');
+    const output = document.querySelector('.builder-response');
+    document.querySelector('.builder-fallback').hidden = true;
+    
+    const quote = s => /^[A-Za-z0-9_./:=@,-]+$/.test(s) ? s : "'" + s.replace(/'/g, "'\\''") + "'";
+    
+    // Read the builder into the URL, method, headers and body to send
+    const build = () => {
+        const method = form.elements.method.value;
+        let url = location.origin + form.elements.path.value;
+        const query = form.elements.query.value.trim().replace(/^\?/, '');
+        if (query) {
+            url += '?' + query;
+        }
+        const headers = [];
+        form.elements.headers.value.split('\n').forEach(line => {
+            const i = line.indexOf(':');
+            if (i > 0) {
+                headers.push([line.slice(0, i).trim(), line.slice(i + 1).trim()]);
+            }
+        });
+        const body = method === 'GET' ? '' : form.elements.body.value;
+        return { method, url, headers, body };
+    };
+    
+    const updateCurl = () => {
+        const request = build();
+        const args = ['curl', '-i'];
+        if (request.method !== 'GET' && !(request.method === 'POST' && request.body)) {
+            args.push('-X', request.method);
+        }
+        args.push(quote(request.url));
+        request.headers.forEach(([name, value]) => args.push('-H', quote(name + ': ' + value)));
+        if (request.body) {
+            args.push('--data-binary', quote(request.body));
+        }
+        curl.querySelector('code').textContent = args.join(' ');
+    };
+    
+    form.addEventListener('input', updateCurl);
+    curl.hidden = false;
+    updateCurl();
+    
+    form.addEventListener('submit', event => {
+        event.preventDefault();
+        const request = build();
+        const headers = new Headers(request.headers);
+        headers.set('Accept', 'application/json');
+        output.hidden = false;
+        output.textContent = 'Sending…';
+        fetch(request.url, { method: request.method, headers, body: request.body || undefined })
+            .then(response => response.text().then(text => {
+                output.textContent = response.status + ' ' + response.statusText + '\n\n' + text;
+            }))
+            .catch(err => {
+                output.textContent = 'The request failed: ' + err.message;
+            });
+    });
+});
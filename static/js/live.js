@@ -0,0 +1,27 @@
+// Refresh the server log on live example pages while the example runs
+document.addEventListener('DOMContentLoaded', function() {
+    const log = document.querySelector('.live-log[data-status-url]');
+    if (!log) {
+        return;
+    }
+    
+    const refresh = () => {
+        fetch(log.dataset.statusUrl, { headers: { 'Accept': 'application/json' } })
+            .then(response => response.json())
+            .then(status => {
+                const atBottom = log.scrollTop + log.clientHeight >= log.scrollHeight - 4;
+                log.textContent = status.log.map(line => line + '\n').join('');
+                if (atBottom) {
+                    log.scrollTop = log.scrollHeight;
+                }
+                if (status.running) {
+                    setTimeout(refresh, 2000);
+                }
+            })
+            .catch(() => setTimeout(refresh, 10000));
+    };
+    
+    if (document.querySelector('.live-status.running')) {
+        refresh();
+    }
+});
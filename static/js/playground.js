@@ -0,0 +1,115 @@
+// Edit and run Go code on the server from tutorial snippets and examples
+document.addEventListener('DOMContentLoaded', function() {
+    // Build the editor shown below a snippet the first time Run is pressed
+    const createPlayground = source => {
+        const playground = document.createElement('div');
+        playground.className = 'playground';
+        
+        const editor = document.createElement('textarea');
+        editor.className = 'code-editor';
+        editor.spellcheck = false;
+        editor.rows = Math.min(30, source.split('\n').length + 1);
+        editor.value = source;
+        
+        const actions = document.createElement('div');
+        actions.className = 'playground-actions';
+        const run = document.createElement('button');
+        run.type = 'button';
+        run.className = 'btn';
+        run.textContent = 'Run';
+        const reset = document.createElement('button');
+        reset.type = 'button';
+        reset.className = 'btn btn-secondary';
+        reset.textContent = 'Reset';
+        actions.append(run, reset);
+        
+        const output = document.createElement('pre');
+        output.className = 'playground-output';
+        output.hidden = true;
+        
+        run.addEventListener('click', () => runProgram(editor.value, output, run));
+        reset.addEventListener('click', () => { editor.value = source; });
+        
+        playground.append(editor, actions, output);
+        return playground;
+    };
+    
+    const appendOutput = (output, kind, text) => {
+        const span = document.createElement('span');
+        span.className = 'output-' + kind;
+        span.textContent = text;
+        output.appendChild(span);
+    };
+    
+    const describeExit = event => {
+        if (event.data) return event.data;
+        let text = event.timed_out ? 'Program stopped: time limit reached' : 'Program exited with code ' + event.exit_code;
+        if (event.truncated) text += ' (output truncated)';
+        if (event.cached) text += ' (cached result)';
+        return text;
+    };
+    
+    // Stream newline-delimited JSON events from /api/run into the output
+    const runProgram = async (source, output, button) => {
+        output.hidden = false;
+        output.textContent = '';
+        button.disabled = true;
+        appendOutput(output, 'status', 'Running…\n');
+        
+        try {
+            const response = await fetch('/api/run', {
+                method: 'POST',
+                headers: {'Content-Type': 'application/json'},
+                body: JSON.stringify({source: source})
+            });
+            if (!response.ok) {
+                const body = await response.json().catch(() => ({}));
+                throw new Error(body.error || response.statusText);
+            }
+            
+            output.textContent = '';
+            const reader = response.body.getReader();
+            const decoder = new TextDecoder();
+            let buffered = '';
+            for (;;) {
+                const {value, done} = await reader.read();
+                if (done) break;
+                buffered += decoder.decode(value, {stream: true});
+                const lines = buffered.split('\n');
+                buffered = lines.pop();
+                lines.filter(line => line).forEach(line => {
+                    const event = JSON.parse(line);
+                    if (event.kind === 'exit') {
+                        appendOutput(output, 'status', '\n' + describeExit(event) + '\n');
+                    } else {
+                        appendOutput(output, event.kind, event.data);
+                    }
+                });
+            }
+        } catch (err) {
+            appendOutput(output, 'stderr', 'Could not run the program: ' + err.message + '\n');
+        } finally {
+            button.disabled = false;
+        }
+    };
+    
+    document.querySelectorAll('.run-button').forEach(button => {
+        button.addEventListener('click', async () => {
+            const holder = button.closest('[data-source]');
+            const container = button.closest('.tab-panel, .example-card');
+            if (!holder || !container) return;
+            
+            let playground = container.querySelector('.playground');
+            if (playground) {
+                playground.hidden = !playground.hidden;
+                return;
+            }
+            
+            const response = await fetch(holder.dataset.source);
+            if (!response.ok) return;
+            playground = createPlayground(await response.text());
+            container.appendChild(playground);
+            playground.querySelector('.code-editor').focus();
+        });
+    });
+});
@@ -0,0 +1,28 @@
+// Reload the page when the templates, content or static files it is built
+// from change; only servers started with HOT_RELOAD=1 ask for this
+document.addEventListener('DOMContentLoaded', function() {
+    const meta = document.querySelector('meta[name="reload-events"]');
+    if (!meta || !window.EventSource) {
+        return;
+    }
+    
+    // Don't throw away what someone is typing
+    let editing = false;
+    document.addEventListener('input', () => {
+        editing = true;
+    });
+    
+    const events = new EventSource(meta.content);
+    events.addEventListener('reload', event => {
+        const change = JSON.parse(event.data);
+        if (editing) {
+            console.info('Files changed, reload to see them:', change.paths);
+            return;
+        }
+        window.location.reload();
+    });
+    events.addEventListener('reload-failed', event => {
+        const change = JSON.parse(event.data);
+        console.error('Could not apply changes, still showing the last good version:', change.error);
+    });
+});
@@ -1,11 +1,29 @@
 document.addEventListener('DOMContentLoaded', function() {
-    // Initialize Prism.js for syntax highlighting
-    try {
-        if (typeof Prism !== 'undefined') {
-            Prism.highlightAll();
-        }
-    } catch (e) {
-        console.error("Prism initialization error:", e);
+    // Let the stylesheet know scripts are available for tabbed code blocks
+    document.documentElement.classList.add('js');
+    
+    // Switch between the files of a multi-file code example
+    const showPanel = panel => {
+        const tabs = panel.closest('.code-tabs');
+        if (!tabs) return;
+        tabs.querySelectorAll('.tab-panel').forEach(p => p.classList.toggle('active', p === panel));
+        tabs.querySelectorAll('.tab').forEach(tab => {
+            tab.classList.toggle('active', tab.dataset.panel === panel.id);
+        });
+    };
+    
+    document.querySelectorAll('.code-tabs .tab').forEach(tab => {
+        tab.addEventListener('click', () => {
+            const panel = document.getElementById(tab.dataset.panel);
+            if (panel) showPanel(panel);
+        });
+    });
+    
+    // Reveal the tab holding a line linked from another page
+    if (location.hash) {
+        const linked = document.getElementById(location.hash.slice(1));
+        const panel = linked && linked.closest('.tab-panel');
+        if (panel) showPanel(panel);
     }
     
     // Add smooth scrolling for anchor links
@@ -16,10 +34,15 @@ document.addEventListener('DOMContentLoaded', function() {
             const targetId = this.getAttribute('href');
             if (targetId === '#') return;
             
-            const target = document.querySelector(targetId);
+            const target = document.getElementById(targetId.slice(1));
             if (target) {
+                // Line references may point into a tab that is not showing yet
+                const panel = target.closest('.tab-panel');
+                if (panel) showPanel(panel);
+                history.replaceState(null, '', targetId);
+
                 window.scrollTo({
-                    top: target.offsetTop - 80, // Adjust for header height
+                    top: target.getBoundingClientRect().top + window.scrollY - 80, // Adjust for header height
                     behavior: 'smooth'
                 });
             }
@@ -49,8 +72,12 @@ document.addEventListener('DOMContentLoaded', function() {
         
         // Add copy functionality
         button.addEventListener('click', () => {
-            const code = block.textContent;
-            navigator.clipboard.writeText(code).then(() => {
+            // Prefer the exact source served by the site over the rendered text
+            const panel = block.closest('[data-source]');
+            const source = panel
+                ? fetch(panel.dataset.source).then(res => res.ok ? res.text() : block.textContent).catch(() => block.textContent)
+                : Promise.resolve(block.textContent);
+            source.then(code => navigator.clipboard.writeText(code)).then(() => {
                 button.textContent = 'Copied!';
                 setTimeout(() => {
                     button.textContent = 'Copy';
@@ -89,6 +116,14 @@ document.addEventListener('DOMContentLoaded', function() {
         }
     };
     
+    // Let the Tab key indent code in editors instead of leaving the field
+    document.addEventListener('keydown', e => {
+        if (!e.target.matches('.code-editor')) return;
+        if (e.key !== 'Tab' || e.shiftKey || e.ctrlKey || e.altKey || e.metaKey) return;
+        e.preventDefault();
+        e.target.setRangeText('\t', e.target.selectionStart, e.target.selectionEnd, 'end');
+    });
+    
     // Call once on load
     createMobileNav();
     